@@ -0,0 +1,58 @@
+package events
+
+import (
+	"search-engine/domain"
+	"search-engine/pkg/apierror"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Record(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req domain.EventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
+
+	if err := domain.ValidateEventsRequest(req); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
+
+	recorded, err := h.service.Record(c.Context(), req.ToEvents())
+	if err != nil {
+		h.logger.Error("event recording failed",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+		return h.errorResponse(c, apierror.ErrInternalServer, requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(
+		fiber.Map{"recorded": recorded},
+		&domain.Meta{RequestID: requestID},
+	))
+}
+
+func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, requestID string) error {
+	response := domain.NewErrorResponse(apiErr.Code, apiErr.Message, requestID)
+	return c.Status(apiErr.StatusCode).JSON(response)
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App) {
+	v1 := app.Group("/api/v1")
+	v1.Post("/events", h.Record)
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"search-engine/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	Record(ctx context.Context, events []domain.Event) (int, error)
+	Engagement(ctx context.Context, contentIDs []uuid.UUID, since time.Time) (map[uuid.UUID]domain.Engagement, error)
+}
+
+type Config struct {
+	CTRWeight   float64
+	Window      time.Duration
+	PriorCTR    float64
+	PriorWeight float64
+}
+
+type Service struct {
+	repo   Repository
+	config Config
+	logger *zap.Logger
+}
+
+func NewService(repo Repository, config Config, logger *zap.Logger) *Service {
+	if config.CTRWeight <= 0 {
+		config.CTRWeight = 10
+	}
+	if config.Window <= 0 {
+		config.Window = 30 * 24 * time.Hour
+	}
+	if config.PriorCTR <= 0 {
+		config.PriorCTR = 0.05
+	}
+	if config.PriorWeight <= 0 {
+		config.PriorWeight = 20
+	}
+
+	return &Service{
+		repo:   repo,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (s *Service) Record(ctx context.Context, events []domain.Event) (int, error) {
+	recorded, err := s.repo.Record(ctx, events)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record events: %w", err)
+	}
+	return recorded, nil
+}
+
+// Boosts returns the click-through score signal for each content ID: how far
+// its CTR is above or below the prior. Content without any recorded events
+// sits at the prior and gets 0, so new items are neither rewarded nor
+// punished for lacking feedback.
+func (s *Service) Boosts(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	if len(contentIDs) == 0 {
+		return map[uuid.UUID]float64{}, nil
+	}
+
+	engagement, err := s.repo.Engagement(ctx, contentIDs, time.Now().Add(-s.config.Window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load engagement: %w", err)
+	}
+
+	boosts := make(map[uuid.UUID]float64, len(contentIDs))
+	for _, id := range contentIDs {
+		ctr := engagement[id].DebiasedCTR(s.config.PriorCTR, s.config.PriorWeight)
+		boosts[id] = s.config.CTRWeight * (ctr - s.config.PriorCTR)
+	}
+	return boosts, nil
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	engagement map[uuid.UUID]domain.Engagement
+	since      time.Time
+}
+
+func (r *fakeRepository) Record(ctx context.Context, events []domain.Event) (int, error) {
+	return len(events), nil
+}
+
+func (r *fakeRepository) Engagement(ctx context.Context, contentIDs []uuid.UUID, since time.Time) (map[uuid.UUID]domain.Engagement, error) {
+	r.since = since
+	return r.engagement, nil
+}
+
+func TestService_Boosts(t *testing.T) {
+	clicked := domain.NewUUID()
+	ignored := domain.NewUUID()
+	unseen := domain.NewUUID()
+
+	repo := &fakeRepository{engagement: map[uuid.UUID]domain.Engagement{
+		clicked: {Impressions: 20, Clicks: 10, Examinations: 10},
+		ignored: {Impressions: 20, Clicks: 0, Examinations: 10},
+	}}
+	service := NewService(repo, Config{CTRWeight: 10, Window: 24 * time.Hour, PriorCTR: 0.1, PriorWeight: 10}, zap.NewNop())
+
+	boosts, err := service.Boosts(context.Background(), []uuid.UUID{clicked, ignored, unseen})
+	if err != nil {
+		t.Fatalf("Boosts() error = %v", err)
+	}
+
+	if !(boosts[clicked] > boosts[unseen] && boosts[unseen] > boosts[ignored]) {
+		t.Errorf("boosts = %v, want clicked > unseen > ignored", boosts)
+	}
+	if boosts[unseen] != 0 {
+		t.Errorf("unseen boost = %v, want 0", boosts[unseen])
+	}
+	if boosts[ignored] >= 0 {
+		t.Errorf("ignored boost = %v, want below 0", boosts[ignored])
+	}
+	if time.Since(repo.since) < 23*time.Hour {
+		t.Errorf("since = %v, want window start", repo.since)
+	}
+}
@@ -0,0 +1,65 @@
+package ingestion
+
+import (
+	"errors"
+
+	"search-engine/domain"
+	"search-engine/pkg/apierror"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	scheduler *Scheduler
+	logger    *zap.Logger
+}
+
+func NewHandler(scheduler *Scheduler, logger *zap.Logger) *Handler {
+	return &Handler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) Status(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	return c.JSON(domain.NewSuccessResponse(
+		fiber.Map{"providers": h.scheduler.Status()},
+		&domain.Meta{RequestID: requestID},
+	))
+}
+
+func (h *Handler) Run(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	providerName := c.Params("provider")
+
+	stats, err := h.scheduler.RunNow(c.Context(), providerName)
+	if errors.Is(err, ErrUnknownProvider) {
+		return h.errorResponse(c, apierror.NewNotFoundError("Provider '"+providerName+"'"), requestID)
+	}
+	if errors.Is(err, ErrRunInProgress) {
+		return h.errorResponse(c, apierror.NewConflictError(err.Error()), requestID)
+	}
+	if err != nil {
+		h.logger.Error("manual ingestion run failed",
+			zap.String("provider", providerName),
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(stats, &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, requestID string) error {
+	response := domain.NewErrorResponse(apiErr.Code, apiErr.Message, requestID)
+	return c.Status(apiErr.StatusCode).JSON(response)
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/ingestion", h.Status)
+	admin.Post("/ingestion/:provider/run", h.Run)
+}
@@ -0,0 +1,350 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/scoring"
+	"search-engine/infra/provider"
+
+	"go.uber.org/zap"
+)
+
+const historySize = 10
+
+type Repository interface {
+	// UpsertBatch returns how many of the contents were inserted or changed.
+	UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error)
+}
+
+// CacheInvalidator drops cached results filtered to a provider.
+type CacheInvalidator interface {
+	InvalidateProviderFilter(ctx context.Context, provider string) error
+}
+
+type Config struct {
+	Interval    time.Duration
+	Jitter      time.Duration
+	BatchSize   int
+	Timeout     time.Duration
+	Scorer      scoring.Strategy
+	Invalidator CacheInvalidator
+}
+
+type RunStats struct {
+	Provider   string    `json:"provider"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Fetched    int       `json:"fetched"`
+	Valid      int       `json:"valid"`
+	Invalid    int       `json:"invalid"`
+	Upserted   int       `json:"upserted"`
+	Changed    int       `json:"changed"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type ProviderStatus struct {
+	Provider string     `json:"provider"`
+	Interval string     `json:"interval"`
+	Running  bool       `json:"running"`
+	NextRun  time.Time  `json:"next_run,omitempty"`
+	LastRun  *RunStats  `json:"last_run,omitempty"`
+	History  []RunStats `json:"history,omitempty"`
+}
+
+type job struct {
+	provider provider.FetchableProvider
+	interval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	nextRun time.Time
+	history []RunStats
+}
+
+type Scheduler struct {
+	repo   Repository
+	scorer scoring.Strategy
+	config Config
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	jobs   map[string]*job
+	order  []string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	ErrUnknownProvider = errors.New("unknown provider")
+	ErrRunInProgress   = errors.New("ingestion run already in progress")
+)
+
+func NewScheduler(repo Repository, config Config, logger *zap.Logger) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Minute
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+	if config.Scorer == nil {
+		config.Scorer = scoring.NewScorer()
+	}
+
+	return &Scheduler{
+		repo:   repo,
+		scorer: config.Scorer,
+		config: config,
+		logger: logger,
+		jobs:   make(map[string]*job),
+	}
+}
+
+func (s *Scheduler) AddProvider(p provider.ContentProvider, interval time.Duration) bool {
+	fetchable, ok := p.(provider.FetchableProvider)
+	if !ok || !provider.IsFetchable(p) {
+		s.logger.Warn("provider does not support full fetch, skipping ingestion",
+			zap.String("provider", p.Name()),
+		)
+		return false
+	}
+
+	if interval <= 0 {
+		interval = s.config.Interval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[p.Name()]; !exists {
+		s.order = append(s.order, p.Name())
+	}
+	s.jobs[p.Name()] = &job{provider: fetchable, interval: interval}
+
+	return true
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	s.cancel = cancel
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+
+	s.logger.Info("ingestion scheduler started", zap.Int("providers", len(jobs)))
+}
+
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("ingestion scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	delay := s.jitter()
+	for {
+		j.mu.Lock()
+		j.nextRun = time.Now().Add(delay)
+		j.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.run(ctx, j); err != nil && !errors.Is(err, ErrRunInProgress) {
+			s.logger.Warn("ingestion run failed",
+				zap.String("provider", j.provider.Name()),
+				zap.Error(err),
+			)
+		}
+
+		delay = j.interval + s.jitter()
+	}
+}
+
+func (s *Scheduler) jitter() time.Duration {
+	if s.config.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.config.Jitter)
+}
+
+func (s *Scheduler) RunNow(ctx context.Context, providerName string) (*RunStats, error) {
+	s.mu.RLock()
+	j, ok := s.jobs[providerName]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, providerName)
+	}
+
+	return s.run(ctx, j)
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) (*RunStats, error) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return nil, ErrRunInProgress
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	stats := s.ingest(ctx, j.provider)
+	if stats.Changed > 0 {
+		s.invalidate(ctx, stats.Provider)
+	}
+
+	j.mu.Lock()
+	j.running = false
+	j.history = append(j.history, stats)
+	if len(j.history) > historySize {
+		j.history = j.history[len(j.history)-historySize:]
+	}
+	j.mu.Unlock()
+
+	if stats.Error != "" {
+		return &stats, errors.New(stats.Error)
+	}
+	return &stats, nil
+}
+
+func (s *Scheduler) ingest(ctx context.Context, p provider.FetchableProvider) RunStats {
+	stats := RunStats{
+		Provider:  p.Name(),
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		stats.FinishedAt = time.Now()
+		duration := stats.FinishedAt.Sub(stats.StartedAt)
+		stats.DurationMs = duration.Milliseconds()
+
+		s.logger.Info("ingestion run finished",
+			zap.String("provider", stats.Provider),
+			zap.Int("fetched", stats.Fetched),
+			zap.Int("valid", stats.Valid),
+			zap.Int("invalid", stats.Invalid),
+			zap.Int("upserted", stats.Upserted),
+			zap.Int("changed", stats.Changed),
+			zap.Duration("duration", duration),
+			zap.String("error", stats.Error),
+		)
+	}()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	fetched, err := p.FetchAll(fetchCtx)
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	stats.Fetched = len(fetched)
+
+	valid, invalid := domain.ValidateProviderContents(fetched)
+	stats.Valid = len(valid)
+	stats.Invalid = len(invalid)
+
+	batch := make([]*domain.Content, 0, s.config.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		changed, err := s.repo.UpsertBatch(ctx, batch)
+		if err == nil {
+			stats.Upserted += len(batch)
+			stats.Changed += changed
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	for _, pc := range valid {
+		content := domain.NewContentFromProvider(pc, p.Name(), s.scorer.Breakdown(pc))
+		batch = append(batch, &content)
+
+		if len(batch) >= s.config.BatchSize {
+			if err := flush(); err != nil {
+				stats.Error = err.Error()
+				return stats
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		stats.Error = err.Error()
+	}
+
+	return stats
+}
+
+func (s *Scheduler) invalidate(ctx context.Context, providerName string) {
+	if s.config.Invalidator == nil {
+		return
+	}
+	if err := s.config.Invalidator.InvalidateProviderFilter(ctx, providerName); err != nil {
+		s.logger.Warn("failed to invalidate cached searches",
+			zap.String("provider", providerName),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Scheduler) Status() []ProviderStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]ProviderStatus, 0, len(s.order))
+	for _, name := range s.order {
+		j := s.jobs[name]
+
+		j.mu.Lock()
+		status := ProviderStatus{
+			Provider: name,
+			Interval: j.interval.String(),
+			Running:  j.running,
+			NextRun:  j.nextRun,
+			History:  append([]RunStats(nil), j.history...),
+		}
+		if len(j.history) > 0 {
+			last := j.history[len(j.history)-1]
+			status.LastRun = &last
+		}
+		j.mu.Unlock()
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
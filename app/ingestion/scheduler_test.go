@@ -0,0 +1,131 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	contents []domain.ProviderContent
+	err      error
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (p *fakeProvider) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
+	return p.contents, p.err
+}
+
+type fakeRepository struct {
+	batches   []int
+	unchanged bool
+}
+
+func (r *fakeRepository) UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error) {
+	r.batches = append(r.batches, len(contents))
+	if r.unchanged {
+		return 0, nil
+	}
+	return len(contents), nil
+}
+
+func TestScheduler_RunNowUpsertsInBatches(t *testing.T) {
+	contents := make([]domain.ProviderContent, 0, 6)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		contents = append(contents, domain.ProviderContent{
+			ExternalID:  id,
+			Title:       "Title " + id,
+			Type:        "video",
+			PublishedAt: time.Now(),
+		})
+	}
+	contents = append(contents, domain.ProviderContent{ExternalID: "bad", Type: "video"})
+
+	repo := &fakeRepository{}
+	scheduler := NewScheduler(repo, Config{BatchSize: 2}, zap.NewNop())
+	scheduler.AddProvider(&fakeProvider{contents: contents}, time.Hour)
+
+	stats, err := scheduler.RunNow(context.Background(), "fake")
+	if err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	if stats.Fetched != 6 || stats.Valid != 5 || stats.Invalid != 1 || stats.Upserted != 5 || stats.Changed != 5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if len(repo.batches) != 3 || repo.batches[2] != 1 {
+		t.Errorf("batches = %v, want [2 2 1]", repo.batches)
+	}
+
+	status := scheduler.Status()
+	if len(status) != 1 || status[0].LastRun == nil || status[0].LastRun.Upserted != 5 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestScheduler_RunNowRecordsErrors(t *testing.T) {
+	scheduler := NewScheduler(&fakeRepository{}, Config{}, zap.NewNop())
+	scheduler.AddProvider(&fakeProvider{err: errors.New("upstream down")}, 0)
+
+	stats, err := scheduler.RunNow(context.Background(), "fake")
+	if err == nil || stats.Error != "upstream down" {
+		t.Errorf("RunNow() = %+v, %v; want recorded upstream error", stats, err)
+	}
+
+	if _, err := scheduler.RunNow(context.Background(), "missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("RunNow(missing) error = %v, want ErrUnknownProvider", err)
+	}
+}
+
+type fakeInvalidator struct {
+	providers []string
+}
+
+func (i *fakeInvalidator) InvalidateProviderFilter(ctx context.Context, provider string) error {
+	i.providers = append(i.providers, provider)
+	return nil
+}
+
+func TestScheduler_RunNowInvalidatesCache(t *testing.T) {
+	contents := []domain.ProviderContent{{ExternalID: "a", Title: "A", Type: "video", PublishedAt: time.Now()}}
+	tests := []struct {
+		name     string
+		provider *fakeProvider
+		repo     *fakeRepository
+		want     int
+	}{
+		{"contents changed", &fakeProvider{contents: contents}, &fakeRepository{}, 1},
+		{"contents unchanged", &fakeProvider{contents: contents}, &fakeRepository{unchanged: true}, 0},
+		{"nothing upserted", &fakeProvider{err: errors.New("upstream down")}, &fakeRepository{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalidator := &fakeInvalidator{}
+			scheduler := NewScheduler(tt.repo, Config{Invalidator: invalidator}, zap.NewNop())
+			scheduler.AddProvider(tt.provider, time.Hour)
+
+			scheduler.RunNow(context.Background(), "fake")
+
+			if len(invalidator.providers) != tt.want {
+				t.Errorf("invalidated = %v, want %d call(s)", invalidator.providers, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package rescore
+
+import (
+	"errors"
+
+	"search-engine/domain"
+	"search-engine/pkg/apierror"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	job    *Job
+	logger *zap.Logger
+}
+
+func NewHandler(job *Job, logger *zap.Logger) *Handler {
+	return &Handler{
+		job:    job,
+		logger: logger,
+	}
+}
+
+func (h *Handler) Status(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	return c.JSON(domain.NewSuccessResponse(h.job.Status(), &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) Run(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	stats, err := h.job.RunNow(c.Context())
+	if errors.Is(err, ErrRunInProgress) {
+		return h.errorResponse(c, apierror.NewConflictError(err.Error()), requestID)
+	}
+	if err != nil {
+		h.logger.Error("manual rescore run failed",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(stats, &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, requestID string) error {
+	response := domain.NewErrorResponse(apiErr.Code, apiErr.Message, requestID)
+	return c.Status(apiErr.StatusCode).JSON(response)
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App) {
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/rescore", h.Status)
+	admin.Post("/rescore/run", h.Run)
+}
@@ -0,0 +1,250 @@
+package rescore
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/scoring"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	ListForRescore(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error)
+	UpdateScores(ctx context.Context, contents []*domain.Content) (int, error)
+}
+
+// CacheInvalidator drops cached data that depends on a provider's contents.
+type CacheInvalidator interface {
+	InvalidateProvider(ctx context.Context, provider string) error
+}
+
+type Config struct {
+	Interval    time.Duration
+	BatchSize   int
+	MinDelta    float64
+	Scorer      scoring.Strategy
+	Invalidator CacheInvalidator
+}
+
+type RunStats struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Scanned    int       `json:"scanned"`
+	Updated    int       `json:"updated"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type Status struct {
+	Interval string    `json:"interval"`
+	Running  bool      `json:"running"`
+	LastRun  *RunStats `json:"last_run,omitempty"`
+}
+
+var ErrRunInProgress = errors.New("rescore run already in progress")
+
+type Job struct {
+	repo   Repository
+	config Config
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+	lastRun *RunStats
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewJob(repo Repository, config Config, logger *zap.Logger) *Job {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.MinDelta <= 0 {
+		config.MinDelta = 0.01
+	}
+	if config.Scorer == nil {
+		config.Scorer = scoring.NewScorer()
+	}
+
+	return &Job{
+		repo:   repo,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (j *Job) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	j.mu.Lock()
+	j.cancel = cancel
+	j.mu.Unlock()
+
+	j.wg.Add(1)
+	go j.loop(ctx)
+
+	j.logger.Info("rescore job started", zap.Duration("interval", j.config.Interval))
+}
+
+func (j *Job) Stop() {
+	j.mu.Lock()
+	cancel := j.cancel
+	j.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	j.wg.Wait()
+
+	j.logger.Info("rescore job stopped")
+}
+
+func (j *Job) loop(ctx context.Context) {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := j.RunNow(ctx); err != nil && !errors.Is(err, ErrRunInProgress) {
+			j.logger.Warn("rescore run failed", zap.Error(err))
+		}
+	}
+}
+
+func (j *Job) RunNow(ctx context.Context) (*RunStats, error) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return nil, ErrRunInProgress
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	stats := j.run(ctx)
+
+	j.mu.Lock()
+	j.running = false
+	j.lastRun = &stats
+	j.mu.Unlock()
+
+	if stats.Error != "" {
+		return &stats, errors.New(stats.Error)
+	}
+	return &stats, nil
+}
+
+func (j *Job) run(ctx context.Context) RunStats {
+	stats := RunStats{StartedAt: time.Now()}
+	defer func() {
+		stats.FinishedAt = time.Now()
+		duration := stats.FinishedAt.Sub(stats.StartedAt)
+		stats.DurationMs = duration.Milliseconds()
+
+		j.logger.Info("rescore run finished",
+			zap.Int("scanned", stats.Scanned),
+			zap.Int("updated", stats.Updated),
+			zap.Duration("duration", duration),
+			zap.String("error", stats.Error),
+		)
+	}()
+
+	providers := make(map[string]struct{})
+	defer j.invalidate(ctx, providers)
+
+	var afterID uuid.UUID
+	for {
+		contents, err := j.repo.ListForRescore(ctx, afterID, j.config.BatchSize)
+		if err != nil {
+			stats.Error = err.Error()
+			return stats
+		}
+		if len(contents) == 0 {
+			return stats
+		}
+		stats.Scanned += len(contents)
+		afterID = contents[len(contents)-1].ID
+
+		changed := make([]*domain.Content, 0, len(contents))
+		for i := range contents {
+			breakdown := j.config.Scorer.Breakdown(contents[i].ToProviderContent())
+			if !j.changed(contents[i].Breakdown, breakdown) {
+				continue
+			}
+			contents[i].Breakdown = breakdown
+			contents[i].Score = breakdown.Total
+			changed = append(changed, &contents[i])
+			providers[contents[i].Provider] = struct{}{}
+		}
+
+		if len(changed) > 0 {
+			updated, err := j.repo.UpdateScores(ctx, changed)
+			stats.Updated += updated
+			if err != nil {
+				stats.Error = err.Error()
+				return stats
+			}
+		}
+
+		if len(contents) < j.config.BatchSize {
+			return stats
+		}
+	}
+}
+
+// invalidate drops cached searches over the providers whose scores changed.
+func (j *Job) invalidate(ctx context.Context, providers map[string]struct{}) {
+	if j.config.Invalidator == nil {
+		return
+	}
+	for provider := range providers {
+		if err := j.config.Invalidator.InvalidateProvider(ctx, provider); err != nil {
+			j.logger.Warn("failed to invalidate cached searches",
+				zap.String("provider", provider),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// changed reports whether a recomputed score differs enough from the stored
+// one to be worth writing back. Rows stored before score components were
+// persisted have components that do not explain their score and are always
+// rewritten.
+func (j *Job) changed(stored, recomputed domain.ScoreBreakdown) bool {
+	if !stored.Explains(stored.Total) {
+		return true
+	}
+	return math.Abs(recomputed.Total-stored.Total) >= j.config.MinDelta ||
+		math.Abs(recomputed.FreshnessScore-stored.FreshnessScore) >= j.config.MinDelta
+}
+
+func (j *Job) Status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := Status{
+		Interval: j.config.Interval.String(),
+		Running:  j.running,
+	}
+	if j.lastRun != nil {
+		last := *j.lastRun
+		status.LastRun = &last
+	}
+	return status
+}
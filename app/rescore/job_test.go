@@ -0,0 +1,123 @@
+package rescore
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/scoring"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	contents []domain.Content
+	updated  []*domain.Content
+	lists    int
+}
+
+func (r *fakeRepository) ListForRescore(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error) {
+	r.lists++
+
+	var page []domain.Content
+	for _, content := range r.contents {
+		if content.ID.String() > afterID.String() && len(page) < limit {
+			page = append(page, content)
+		}
+	}
+	return page, nil
+}
+
+func (r *fakeRepository) UpdateScores(ctx context.Context, contents []*domain.Content) (int, error) {
+	r.updated = append(r.updated, contents...)
+	return len(contents), nil
+}
+
+func TestJob_RunNowUpdatesChangedScores(t *testing.T) {
+	scorer := scoring.NewWeightedScorer("decay", scoring.Weights{FreshnessHalfLife: 24 * time.Hour})
+
+	current := domain.ProviderContent{Type: "text", ReadingTime: 5, PublishedAt: time.Now().Add(-48 * time.Hour)}
+	upToDate := domain.Content{
+		ID:          domain.NewUUID(),
+		Type:        domain.ContentTypeText,
+		ReadingTime: current.ReadingTime,
+		PublishedAt: current.PublishedAt,
+		Breakdown:   scorer.Breakdown(current),
+	}
+	decayed := upToDate
+	decayed.ID = domain.NewUUID()
+	decayed.Breakdown = domain.ScoreBreakdown{BaseScore: 5, TypeMultiplier: 1, FreshnessScore: 5, Total: 10}
+	legacy := upToDate
+	legacy.ID = domain.NewUUID()
+	legacy.Breakdown = domain.ScoreBreakdown{}
+
+	contents := []domain.Content{upToDate, decayed, legacy}
+	sort.Slice(contents, func(i, j int) bool { return contents[i].ID.String() < contents[j].ID.String() })
+
+	repo := &fakeRepository{contents: contents}
+	job := NewJob(repo, Config{BatchSize: 2, Scorer: scorer}, zap.NewNop())
+
+	stats, err := job.RunNow(context.Background())
+	if err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	if stats.Scanned != 3 || stats.Updated != 2 {
+		t.Errorf("stats = %+v, want 3 scanned and 2 updated", stats)
+	}
+	if repo.lists != 2 {
+		t.Errorf("ListForRescore called %d times, want 2 batches", repo.lists)
+	}
+	for _, content := range repo.updated {
+		if content.ID == upToDate.ID {
+			t.Errorf("up-to-date content %s should not be rewritten", content.ID)
+		}
+		if content.Score != content.Breakdown.Total || content.Breakdown.FreshnessScore != 1.25 {
+			t.Errorf("updated content = %+v, want decayed freshness 1.25", content.Breakdown)
+		}
+	}
+
+	if status := job.Status(); status.LastRun == nil || status.LastRun.Updated != 2 {
+		t.Errorf("Status() = %+v, want last run with 2 updates", status)
+	}
+}
+
+type fakeInvalidator struct {
+	providers []string
+}
+
+func (i *fakeInvalidator) InvalidateProvider(ctx context.Context, provider string) error {
+	i.providers = append(i.providers, provider)
+	return nil
+}
+
+func TestJob_RunNowInvalidatesChangedProviders(t *testing.T) {
+	scorer := scoring.NewScorer()
+
+	current := domain.ProviderContent{Type: "video", Views: 100, PublishedAt: time.Now()}
+	upToDate := domain.Content{
+		ID:          domain.NewUUID(),
+		Provider:    "a",
+		Type:        domain.ContentTypeVideo,
+		Views:       current.Views,
+		PublishedAt: current.PublishedAt,
+		Breakdown:   scorer.Breakdown(current),
+	}
+	stale := upToDate
+	stale.ID = domain.NewUUID()
+	stale.Provider = "b"
+	stale.Breakdown = domain.ScoreBreakdown{}
+
+	invalidator := &fakeInvalidator{}
+	job := NewJob(&fakeRepository{contents: []domain.Content{upToDate, stale}}, Config{Scorer: scorer, Invalidator: invalidator}, zap.NewNop())
+
+	if _, err := job.RunNow(context.Background()); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if len(invalidator.providers) != 1 || invalidator.providers[0] != "b" {
+		t.Errorf("invalidated = %v, want [b]", invalidator.providers)
+	}
+}
@@ -0,0 +1,300 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/fuzzy"
+	"search-engine/domain/scoring"
+
+	"go.uber.org/zap"
+)
+
+// Cache stores search results and snapshots. Lock takes key for ttl and
+// returns ErrLockHeld while someone else holds it; Unlock releases it only
+// for the token Lock returned. Tag adds a stored key to tags for ttl, and
+// Invalidate drops every key carrying any of the tags, returning how many.
+type Cache interface {
+	Get(ctx context.Context, key string, dest any) error
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, ttl time.Duration) (string, error)
+	Unlock(ctx context.Context, key, token string) error
+	Tag(ctx context.Context, key string, tags []string, ttl time.Duration) error
+	Invalidate(ctx context.Context, tags ...string) (int, error)
+}
+
+// Cached results are tagged with the providers and content types they were
+// read from, "*" standing for all of them, and with their normalized query.
+const (
+	providerTagPrefix = "provider:"
+	typeTagPrefix     = "type:"
+	queryTagPrefix    = "query:"
+	anyTagValue       = "*"
+)
+
+// CachePurge selects cached results to drop: those carrying Tag, those that
+// depend on Provider, and those made with Query.
+type CachePurge struct {
+	Tag      string
+	Provider string
+	Query    string
+}
+
+var (
+	ErrCacheMiss = errors.New("cache miss")
+	ErrLockHeld  = errors.New("lock held")
+)
+
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Stale     int64 `json:"stale"`
+	Coalesced int64 `json:"coalesced"`
+	Refreshes int64 `json:"refreshes"`
+}
+
+type cacheMetrics struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	stale     atomic.Int64
+	coalesced atomic.Int64
+	refreshes atomic.Int64
+}
+
+// cacheEntry is what is stored under a search cache key. The key itself
+// expires after the stale window, FreshUntil marks the end of the fresh one.
+type cacheEntry struct {
+	Result     *SearchResult           `json:"result"`
+	FreshUntil time.Time               `json:"fresh_until"`
+	Breakdowns []domain.ScoreBreakdown `json:"breakdowns,omitempty"`
+}
+
+const lockPollInterval = 50 * time.Millisecond
+
+var errRefreshInProgress = errors.New("refresh in progress")
+
+func (s *Service) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:      s.metrics.hits.Load(),
+		Misses:    s.metrics.misses.Load(),
+		Stale:     s.metrics.stale.Load(),
+		Coalesced: s.metrics.coalesced.Load(),
+		Refreshes: s.metrics.refreshes.Load(),
+	}
+}
+
+func (s *Service) cachedSearch(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	key := s.generateCacheKey(params)
+
+	if entry, ok := s.readCache(ctx, key); ok {
+		if time.Now().Before(entry.FreshUntil) {
+			s.metrics.hits.Add(1)
+			s.logger.Debug("cache hit", zap.String("cache_key", key))
+			return entry.Result, nil
+		}
+
+		s.metrics.stale.Add(1)
+		s.logger.Debug("serving stale cache entry", zap.String("cache_key", key))
+		s.refreshInBackground(key, params, strategy)
+		return entry.Result, nil
+	}
+
+	s.metrics.misses.Add(1)
+
+	// The search outlives a caller that gives up, since others may be
+	// waiting for the same result.
+	leader := false
+	value, err, _ := s.flight.Do(key, func() (any, error) {
+		leader = true
+		return s.fill(context.WithoutCancel(ctx), key, params, strategy, true)
+	})
+	if !leader {
+		s.metrics.coalesced.Add(1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value.(*SearchResult), nil
+}
+
+func (s *Service) readCache(ctx context.Context, key string) (cacheEntry, bool) {
+	var entry cacheEntry
+	if s.cache == nil {
+		return entry, false
+	}
+	if err := s.cache.Get(ctx, key, &entry); err != nil || entry.Result == nil {
+		return entry, false
+	}
+
+	result := *entry.Result
+	result.Items = restoreBreakdowns(result.Items, entry.Breakdowns)
+	entry.Result = &result
+	return entry, true
+}
+
+// fill executes a search and caches the result. When another instance holds
+// the lock for the key, fill waits for its result if wait is set and gives
+// up otherwise.
+func (s *Service) fill(ctx context.Context, key string, params SearchParams, strategy scoring.Strategy, wait bool) (*SearchResult, error) {
+	if s.cache == nil {
+		return s.execute(ctx, params, strategy)
+	}
+
+	lockKey := key + ":lock"
+	token, err := s.cache.Lock(ctx, lockKey, s.config.Cache.LockTTL)
+	switch {
+	case errors.Is(err, ErrLockHeld):
+		if !wait {
+			return nil, errRefreshInProgress
+		}
+		if result, ok := s.awaitFill(ctx, key); ok {
+			s.metrics.coalesced.Add(1)
+			return result, nil
+		}
+	case err != nil:
+		s.logger.Warn("failed to take cache lock", zap.Error(err), zap.String("cache_key", key))
+	default:
+		defer func() {
+			if err := s.cache.Unlock(context.Background(), lockKey, token); err != nil {
+				s.logger.Warn("failed to release cache lock", zap.Error(err), zap.String("cache_key", key))
+			}
+		}()
+	}
+
+	result, err := s.execute(ctx, params, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := cacheEntry{
+		Result:     result,
+		FreshUntil: time.Now().Add(s.cacheTTL),
+		Breakdowns: breakdownsOf(result.Items),
+	}
+	ttl := s.cacheTTL + s.config.Cache.StaleTTL
+	if err := s.cache.Set(ctx, key, entry, ttl); err != nil {
+		s.logger.Warn("failed to cache result",
+			zap.Error(err),
+			zap.String("cache_key", key),
+		)
+		return result, nil
+	}
+	if err := s.cache.Tag(ctx, key, cacheTags(params), ttl); err != nil {
+		s.logger.Warn("failed to tag cached result",
+			zap.Error(err),
+			zap.String("cache_key", key),
+		)
+	}
+	return result, nil
+}
+
+func cacheTags(params SearchParams) []string {
+	tags := []string{queryTagPrefix + queryTagValue(params.Query)}
+
+	providers := params.Filter.Providers
+	if len(providers) == 0 {
+		providers = []string{anyTagValue}
+	}
+	for _, provider := range providers {
+		tags = append(tags, providerTagPrefix+provider)
+	}
+
+	types := params.Filter.ContentTypes
+	if len(types) == 0 {
+		types = []string{anyTagValue}
+	}
+	for _, contentType := range types {
+		tags = append(tags, typeTagPrefix+contentType)
+	}
+	return tags
+}
+
+func queryTagValue(query string) string {
+	return strings.Join(fuzzy.Tokenize(query), " ")
+}
+
+// InvalidateProvider drops cached results that may include contents of the
+// provider, after its stored contents changed.
+func (s *Service) InvalidateProvider(ctx context.Context, provider string) error {
+	_, err := s.PurgeCache(ctx, CachePurge{Provider: provider})
+	return err
+}
+
+// InvalidateProviderFilter drops only the cached results filtered to the
+// provider. Unfiltered results ("provider:*") are left to expire with their
+// TTL, so every provider's ingestion run does not clear them.
+func (s *Service) InvalidateProviderFilter(ctx context.Context, provider string) error {
+	_, err := s.PurgeCache(ctx, CachePurge{Tag: providerTagPrefix + provider})
+	return err
+}
+
+func (s *Service) PurgeCache(ctx context.Context, purge CachePurge) (int, error) {
+	var tags []string
+	if purge.Tag != "" {
+		tags = append(tags, purge.Tag)
+	}
+	if purge.Provider != "" {
+		tags = append(tags, providerTagPrefix+purge.Provider, providerTagPrefix+anyTagValue)
+	}
+	if purge.Query != "" {
+		tags = append(tags, queryTagPrefix+queryTagValue(purge.Query))
+	}
+	if s.cache == nil || len(tags) == 0 {
+		return 0, nil
+	}
+
+	purged, err := s.cache.Invalidate(ctx, tags...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge cache: %w", err)
+	}
+	s.logger.Info("search cache purged", zap.Strings("tags", tags), zap.Int("purged", purged))
+	return purged, nil
+}
+
+// awaitFill polls for the result another instance is computing, for as long
+// as it may hold the lock.
+func (s *Service) awaitFill(ctx context.Context, key string) (*SearchResult, bool) {
+	deadline := time.Now().Add(s.config.Cache.LockTTL)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(lockPollInterval):
+		}
+
+		if entry, ok := s.readCache(ctx, key); ok && time.Now().Before(entry.FreshUntil) {
+			return entry.Result, true
+		}
+	}
+	return nil, false
+}
+
+// refreshInBackground re-executes a search whose cached result went stale.
+// Only one refresh per key runs in this instance, and the Redis lock keeps
+// other instances from refreshing it too.
+func (s *Service) refreshInBackground(key string, params SearchParams, strategy scoring.Strategy) {
+	if _, running := s.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer s.refreshing.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.Cache.LockTTL)
+		defer cancel()
+
+		if _, err := s.fill(ctx, key, params, strategy, false); err != nil {
+			if !errors.Is(err, errRefreshInProgress) {
+				s.logger.Warn("cache refresh failed", zap.Error(err), zap.String("cache_key", key))
+			}
+			return
+		}
+		s.metrics.refreshes.Add(1)
+	}()
+}
@@ -1,13 +1,17 @@
 package search
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
 	"search-engine/domain"
+	"search-engine/domain/query"
+	"search-engine/domain/scoring"
 	"search-engine/pkg/apierror"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -37,42 +41,34 @@ func (h *Handler) Search(c *fiber.Ctx) error {
 		return h.errorResponse(c, apierror.ErrInvalidSortField, requestID)
 	}
 
-	params := SearchParams{
-		Query:        req.Query,
-		Tags:         req.Tags,
-		ContentTypes: req.ContentTypes,
-		SortBy:       req.OrderBy,
-		Page:         req.Page,
-		PerPage:      req.PerPage,
-	}
-
-	result, err := h.service.Search(c.Context(), params)
+	filter, err := req.Filter()
 	if err != nil {
-		h.logger.Error("search failed",
-			zap.Error(err),
-			zap.String("request_id", requestID),
-		)
-		return h.errorResponse(c, apierror.ErrInternalServer, requestID)
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
 	}
 
-	response := domain.NewSuccessResponse(
-		domain.SearchData{Items: result.Items},
-		&domain.Meta{
-			Page:       result.Page,
-			PerPage:    result.PerPage,
-			Total:      result.Total,
-			TotalPages: result.TotalPages,
-			RequestID:  requestID,
+	params := SearchParams{
+		Query:   req.Query,
+		Filter:  filter,
+		SortBy:  req.OrderBy,
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Scorer:  req.Scorer,
+		Cursor:  req.Cursor,
+		Dedup:   req.Dedup,
+		Facets: domain.FacetRequest{
+			Fields:   req.Facets,
+			Interval: req.FacetInterval,
+			Size:     req.FacetSize,
 		},
-	)
+	}
 
-	return c.JSON(response)
+	return h.search(c, params, req.Explain, requestID)
 }
 
 func (h *Handler) SearchGET(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
 
-	query := c.Query("q", "")
+	q := c.Query("q", "")
 	tagsParam := c.Query("tags", "")
 	contentType := c.Query("type", "")
 	sortBy := c.Query("sort", "relevant_score")
@@ -92,36 +88,92 @@ func (h *Handler) SearchGET(c *fiber.Ctx) error {
 		perPage = 100
 	}
 
-	var tags []string
-	if tagsParam != "" {
-		tags = strings.Split(tagsParam, ",")
-		for i := range tags {
-			tags[i] = strings.TrimSpace(tags[i])
+	if sortBy != "popularity" && sortBy != "relevant_score" {
+		return h.errorResponse(c, apierror.ErrInvalidSortField, requestID)
+	}
+
+	req := domain.SearchRequest{
+		Tags:          splitList(tagsParam),
+		TagMode:       c.Query("tag_mode"),
+		ExcludeTags:   splitList(c.Query("exclude_tags")),
+		ContentTypes:  splitList(contentType),
+		Providers:     splitList(c.Query("providers")),
+		PublishedFrom: c.Query("published_from"),
+		PublishedTo:   c.Query("published_to"),
+	}
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{
+		{"min_views", &req.MinViews},
+		{"min_likes", &req.MinLikes},
+		{"min_reactions", &req.MinReactions},
+		{"max_reading_time", &req.MaxReadingTime},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		if *param.target, err = strconv.Atoi(value); err != nil {
+			return h.errorResponse(c, apierror.NewValidationError(param.name+" must be a number"), requestID)
 		}
 	}
 
-	var contentTypes []string
-	if contentType != "" {
-		contentTypes = strings.Split(contentType, ",")
-		for i := range contentTypes {
-			contentTypes[i] = strings.TrimSpace(contentTypes[i])
+	if value := c.Query("dedup"); value != "" {
+		dedup, err := strconv.ParseBool(value)
+		if err != nil {
+			return h.errorResponse(c, apierror.NewValidationError("dedup must be true or false"), requestID)
 		}
+		req.Dedup = &dedup
 	}
 
-	if sortBy != "popularity" && sortBy != "relevant_score" {
-		return h.errorResponse(c, apierror.ErrInvalidSortField, requestID)
+	filter, err := req.Filter()
+	if err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
 	}
 
 	params := SearchParams{
-		Query:        query,
-		Tags:         tags,
-		ContentTypes: contentTypes,
-		SortBy:       sortBy,
-		Page:         page,
-		PerPage:      perPage,
+		Query:   q,
+		Filter:  filter,
+		SortBy:  sortBy,
+		Page:    page,
+		PerPage: perPage,
+		Scorer:  c.Query("scorer"),
+		Cursor:  c.Query("cursor"),
+		Dedup:   req.Dedup,
+		Facets: domain.FacetRequest{
+			Fields:   splitList(c.Query("facets")),
+			Interval: c.Query("facet_interval"),
+			Size:     c.QueryInt("facet_size"),
+		},
+	}
+
+	return h.search(c, params, c.QueryBool("explain"), requestID)
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func (h *Handler) search(c *fiber.Ctx, params SearchParams, explain bool, requestID string) error {
+	params.Facets.SetDefaults()
+	if err := params.Facets.Validate(); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
 	}
 
 	result, err := h.service.Search(c.Context(), params)
+	var syntaxErr *query.SyntaxError
+	if errors.Is(err, scoring.ErrUnknownStrategy) || errors.Is(err, ErrInvalidCursor) || errors.Is(err, ErrCursorExpired) || errors.As(err, &syntaxErr) {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
 	if err != nil {
 		h.logger.Error("search failed",
 			zap.Error(err),
@@ -130,13 +182,25 @@ func (h *Handler) SearchGET(c *fiber.Ctx) error {
 		return h.errorResponse(c, apierror.ErrInternalServer, requestID)
 	}
 
+	var data interface{} = domain.SearchData{Items: result.Items, Facets: result.Facets, Suggestion: result.Suggestion}
+	if explain {
+		items, err := h.service.Explain(result.Items, result.Scorer)
+		if err != nil {
+			return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+		}
+		data = domain.ExplainedSearchData{Items: items, Facets: result.Facets, Suggestion: result.Suggestion}
+	}
+
 	response := domain.NewSuccessResponse(
-		domain.SearchData{Items: result.Items},
+		data,
 		&domain.Meta{
 			Page:       result.Page,
 			PerPage:    result.PerPage,
 			Total:      result.Total,
 			TotalPages: result.TotalPages,
+			Estimated:  result.TotalEstimated,
+			NextCursor: result.NextCursor,
+			PrevCursor: result.PrevCursor,
 			RequestID:  requestID,
 		},
 	)
@@ -144,6 +208,95 @@ func (h *Handler) SearchGET(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+func (h *Handler) ExplainScore(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req domain.ScoreExplainRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
+
+	if err := domain.ValidateScoreExplainRequest(req); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
+
+	breakdown, err := h.service.ExplainScore(req.ToProviderContent(), c.Query("scorer"))
+	if err != nil {
+		return h.errorResponse(c, apierror.NewValidationError(err.Error()), requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(breakdown, &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) GetContent(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	id := c.Params("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		return h.errorResponse(c, apierror.NewValidationError("invalid content id"), requestID)
+	}
+
+	detail, err := h.service.GetContent(c.Context(), id, c.QueryBool("include_raw"))
+	if err != nil {
+		return h.contentError(c, err, requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(detail, &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) GetProviderContent(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	providerName := c.Params("provider")
+	externalID := c.Params("externalId")
+
+	detail, err := h.service.GetContentByExternalID(c.Context(), providerName, externalID, c.QueryBool("include_raw"))
+	if err != nil {
+		return h.contentError(c, err, requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(detail, &domain.Meta{RequestID: requestID}))
+}
+
+func (h *Handler) contentError(c *fiber.Ctx, err error, requestID string) error {
+	if errors.Is(err, domain.ErrContentNotFound) {
+		return h.errorResponse(c, apierror.NewNotFoundError("Content"), requestID)
+	}
+
+	h.logger.Error("content lookup failed",
+		zap.Error(err),
+		zap.String("request_id", requestID),
+	)
+	return h.errorResponse(c, apierror.ErrInternalServer, requestID)
+}
+
+func (h *Handler) CacheStats(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	return c.JSON(domain.NewSuccessResponse(h.service.CacheStats(), &domain.Meta{RequestID: requestID}))
+}
+
+// PurgeCache drops cached results by tag, provider or query, given as query
+// parameters.
+func (h *Handler) PurgeCache(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	purge := CachePurge{Tag: c.Query("tag"), Provider: c.Query("provider"), Query: c.Query("query")}
+	if purge.Tag == "" && purge.Provider == "" && purge.Query == "" {
+		return h.errorResponse(c, apierror.NewValidationError("tag, provider or query is required"), requestID)
+	}
+
+	purged, err := h.service.PurgeCache(c.Context(), purge)
+	if err != nil {
+		h.logger.Error("cache purge failed",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+		return h.errorResponse(c, apierror.ErrInternalServer, requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(fiber.Map{"purged": purged}, &domain.Meta{RequestID: requestID}))
+}
+
 func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, requestID string) error {
 	response := domain.NewErrorResponse(apiErr.Code, apiErr.Message, requestID)
 	return c.Status(apiErr.StatusCode).JSON(response)
@@ -152,4 +305,12 @@ func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, request
 func (h *Handler) RegisterRoutes(app *fiber.App) {
 	v1 := app.Group("/api/v1")
 	v1.Post("/search", h.Search)
+	v1.Get("/search", h.SearchGET)
+	v1.Post("/score/explain", h.ExplainScore)
+	v1.Get("/contents/:id", h.GetContent)
+	v1.Get("/providers/:provider/contents/:externalId", h.GetProviderContent)
+
+	admin := app.Group("/api/v1/admin")
+	admin.Get("/search/cache", h.CacheStats)
+	admin.Delete("/search/cache", h.PurgeCache)
 }
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+
+	"search-engine/domain"
+	"search-engine/domain/dedup"
+	"search-engine/domain/fuzzy"
+
+	"go.uber.org/zap"
+)
+
+// ContentIndexer is notified of every content written through an indexing
+// repository. Indexers are best effort and never fail the write.
+type ContentIndexer interface {
+	IndexContents(ctx context.Context, contents []*domain.Content)
+}
+
+type dictionaryIndexer struct {
+	dictionary *fuzzy.Dictionary
+}
+
+// DictionaryIndexer feeds the "did you mean" dictionary.
+func DictionaryIndexer(dictionary *fuzzy.Dictionary) ContentIndexer {
+	return dictionaryIndexer{dictionary: dictionary}
+}
+
+func (d dictionaryIndexer) IndexContents(ctx context.Context, contents []*domain.Content) {
+	for _, content := range contents {
+		d.dictionary.AddContent(content.Title, content.Tags)
+	}
+}
+
+type clusterIndexer struct {
+	store   ClusterStore
+	deduper *dedup.Deduper
+	logger  *zap.Logger
+}
+
+// ClusterIndexer assigns every stored content to its duplicate cluster, so
+// database searches can hide duplicates.
+func ClusterIndexer(store ClusterStore, deduper *dedup.Deduper, logger *zap.Logger) ContentIndexer {
+	return clusterIndexer{store: store, deduper: deduper, logger: logger}
+}
+
+func (c clusterIndexer) IndexContents(ctx context.Context, contents []*domain.Content) {
+	for _, content := range contents {
+		if err := c.store.Assign(ctx, content, c.deduper.Key(*content), c.deduper.Window()); err != nil {
+			c.logger.Warn("failed to assign duplicate cluster",
+				zap.String("provider", content.Provider),
+				zap.String("external_id", content.ExternalID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// indexingRepository passes every upserted content to the indexers, so
+// ingestion batches and persisted live results are both covered.
+type indexingRepository struct {
+	Repository
+	indexers []ContentIndexer
+}
+
+func NewIndexingRepository(repo Repository, indexers ...ContentIndexer) Repository {
+	return &indexingRepository{Repository: repo, indexers: indexers}
+}
+
+func (r *indexingRepository) Upsert(ctx context.Context, content *domain.Content) error {
+	if err := r.Repository.Upsert(ctx, content); err != nil {
+		return err
+	}
+	r.index(ctx, []*domain.Content{content})
+	return nil
+}
+
+func (r *indexingRepository) UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error) {
+	changed, err := r.Repository.UpsertBatch(ctx, contents)
+	if err != nil {
+		return changed, err
+	}
+	r.index(ctx, contents)
+	return changed, nil
+}
+
+func (r *indexingRepository) index(ctx context.Context, contents []*domain.Content) {
+	for _, indexer := range r.indexers {
+		indexer.IndexContents(ctx, contents)
+	}
+}
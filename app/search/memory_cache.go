@@ -0,0 +1,189 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryCache is a bounded in-process LRU cache. Values are kept as they were
+// set, so a hit costs no decoding: Get copies the value into dest, which must
+// point to the type that was set. Readers share the value and must not
+// modify it.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	locks   map[string]memoryLock
+	tagged  map[string]map[string]struct{}
+}
+
+type memoryEntry struct {
+	key     string
+	value   any
+	expires time.Time
+	tags    []string
+}
+
+type memoryLock struct {
+	token   string
+	expires time.Time
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = 10000
+	}
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		locks:   make(map[string]memoryLock),
+		tagged:  make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string, dest any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return ErrCacheMiss
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(elem)
+		return ErrCacheMiss
+	}
+
+	target := reflect.ValueOf(dest)
+	value := reflect.ValueOf(entry.value)
+	if target.Kind() != reflect.Pointer || target.IsNil() || !value.IsValid() || !value.Type().AssignableTo(target.Elem().Type()) {
+		return fmt.Errorf("cache value for %s is %T, not %T", key, entry.value, dest)
+	}
+	target.Elem().Set(value)
+
+	c.order.MoveToFront(elem)
+	return nil
+}
+
+// Set stores value until ttl passes, or until it is evicted when ttl is zero.
+func (c *MemoryCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+func (c *MemoryCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if lock, ok := c.locks[key]; ok && time.Now().Before(lock.expires) {
+		return "", ErrLockHeld
+	}
+	token := uuid.NewString()
+	c.locks[key] = memoryLock{token: token, expires: time.Now().Add(ttl)}
+	return token, nil
+}
+
+func (c *MemoryCache) Unlock(ctx context.Context, key, token string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if lock, ok := c.locks[key]; ok && lock.token == token {
+		delete(c.locks, key)
+	}
+	return nil
+}
+
+// Tag adds key to tags until the key itself is dropped; ttl is not needed
+// since the entry carries its own.
+func (c *MemoryCache) Tag(ctx context.Context, key string, tags []string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	for _, tag := range tags {
+		keys, ok := c.tagged[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tagged[tag] = keys
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = struct{}{}
+			entry.tags = append(entry.tags, tag)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Invalidate(ctx context.Context, tags ...string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := 0
+	for _, tag := range tags {
+		for key := range c.tagged[tag] {
+			c.remove(c.entries[key])
+			dropped++
+		}
+	}
+	return dropped, nil
+}
+
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemoryCache) remove(elem *list.Element) {
+	entry := elem.Value.(*memoryEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(c.tagged[tag], entry.key)
+		if len(c.tagged[tag]) == 0 {
+			delete(c.tagged, tag)
+		}
+	}
+}
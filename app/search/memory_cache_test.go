@@ -0,0 +1,120 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	cache.Set(ctx, "a", 1, 0)
+	cache.Set(ctx, "b", 2, 0)
+	var got int
+	if err := cache.Get(ctx, "a", &got); err != nil || got != 1 {
+		t.Fatalf("Get(a) = %d, %v", got, err)
+	}
+	cache.Set(ctx, "c", 3, 0)
+
+	tests := []struct {
+		key     string
+		want    int
+		wantErr error
+	}{
+		{"a", 1, nil},
+		{"b", 0, ErrCacheMiss},
+		{"c", 3, nil},
+	}
+	for _, tt := range tests {
+		got = 0
+		if err := cache.Get(ctx, tt.key, &got); !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("Get(%s) = %d, %v, want %d, %v", tt.key, got, err, tt.want, tt.wantErr)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+
+	cache.Set(ctx, "short", "x", 20*time.Millisecond)
+	cache.Set(ctx, "long", "y", time.Minute)
+	time.Sleep(40 * time.Millisecond)
+
+	var got string
+	if err := cache.Get(ctx, "short", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Get(short) error = %v, want ErrCacheMiss", err)
+	}
+	if err := cache.Get(ctx, "long", &got); err != nil || got != "y" {
+		t.Errorf("Get(long) = %q, %v", got, err)
+	}
+}
+
+func TestMemoryCache_RejectsOtherTypes(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+	cache.Set(ctx, "key", cacheEntry{}, 0)
+
+	var got snapshot
+	if err := cache.Get(ctx, "key", &got); err == nil || errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Get() error = %v, want a type error", err)
+	}
+}
+
+func TestMemoryCache_Lock(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+
+	token, err := cache.Lock(ctx, "lock", time.Minute)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := cache.Lock(ctx, "lock", time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Errorf("second Lock() error = %v, want ErrLockHeld", err)
+	}
+
+	cache.Unlock(ctx, "lock", "someone else")
+	if _, err := cache.Lock(ctx, "lock", time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Errorf("Lock() after foreign Unlock() error = %v, want ErrLockHeld", err)
+	}
+
+	cache.Unlock(ctx, "lock", token)
+	if _, err := cache.Lock(ctx, "lock", time.Minute); err != nil {
+		t.Errorf("Lock() after Unlock() error = %v", err)
+	}
+}
+
+func TestMemoryCache_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+
+	cache.Set(ctx, "a", 1, 0)
+	cache.Set(ctx, "b", 2, 0)
+	cache.Set(ctx, "c", 3, 0)
+	cache.Tag(ctx, "a", []string{"provider:x", "type:video"}, 0)
+	cache.Tag(ctx, "b", []string{"provider:y", "type:video"}, 0)
+	cache.Tag(ctx, "missing", []string{"provider:x"}, 0)
+
+	if dropped, _ := cache.Invalidate(ctx, "provider:x"); dropped != 1 {
+		t.Errorf("Invalidate(provider:x) = %d, want 1", dropped)
+	}
+	if dropped, _ := cache.Invalidate(ctx, "type:video"); dropped != 1 {
+		t.Errorf("Invalidate(type:video) = %d, want 1", dropped)
+	}
+
+	var got int
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Get(ctx, key, &got); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("Get(%s) error = %v, want ErrCacheMiss", key, err)
+		}
+	}
+	if err := cache.Get(ctx, "c", &got); err != nil {
+		t.Errorf("untagged Get(c) error = %v", err)
+	}
+}
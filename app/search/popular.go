@@ -0,0 +1,216 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/query"
+
+	"go.uber.org/zap"
+)
+
+// QueryLog counts searches over a sliding window, so the most frequent ones
+// can be kept warm.
+type QueryLog interface {
+	Record(ctx context.Context, search string) error
+	Top(ctx context.Context, n int) ([]string, error)
+}
+
+// PopularSearch is a first-page search as recorded in the query log. Its
+// defaults are already resolved, so replaying it hits the same cache key.
+type PopularSearch struct {
+	Query   string              `json:"query"`
+	Filter  domain.SearchFilter `json:"filter"`
+	SortBy  string              `json:"sort_by"`
+	Page    int                 `json:"page"`
+	PerPage int                 `json:"per_page"`
+	Scorer  string              `json:"scorer"`
+	Facets  domain.FacetRequest `json:"facets"`
+	Dedup   *bool               `json:"dedup,omitempty"`
+}
+
+func (p PopularSearch) params() SearchParams {
+	params := SearchParams{
+		Query:   p.Query,
+		Filter:  p.Filter,
+		SortBy:  p.SortBy,
+		Page:    p.Page,
+		PerPage: p.PerPage,
+		Scorer:  p.Scorer,
+		Facets:  p.Facets,
+		Dedup:   p.Dedup,
+	}
+	if p.Dedup != nil {
+		params.Filter.Dedup = *p.Dedup
+	}
+	return params
+}
+
+// recordSearch counts a first page in the query log. Later pages and cursor
+// requests are not worth warming.
+func (s *Service) recordSearch(ctx context.Context, params SearchParams) {
+	if s.config.QueryLog == nil || params.Cursor != "" || params.Page > 1 {
+		return
+	}
+
+	dedup := params.Filter.Dedup
+	data, err := json.Marshal(PopularSearch{
+		Query:   params.Query,
+		Filter:  params.Filter,
+		SortBy:  params.SortBy,
+		Page:    params.Page,
+		PerPage: params.PerPage,
+		Scorer:  params.Scorer,
+		Facets:  params.Facets,
+		Dedup:   &dedup,
+	})
+	if err == nil {
+		err = s.config.QueryLog.Record(ctx, string(data))
+	}
+	if err != nil {
+		s.logger.Warn("failed to record search", zap.Error(err), zap.String("query", params.Query))
+	}
+}
+
+func (s *Service) PopularSearches(ctx context.Context, n int) ([]PopularSearch, error) {
+	if s.config.QueryLog == nil {
+		return nil, nil
+	}
+
+	top, err := s.config.QueryLog.Top(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read popular searches: %w", err)
+	}
+
+	searches := make([]PopularSearch, 0, len(top))
+	for _, data := range top {
+		var popular PopularSearch
+		if err := json.Unmarshal([]byte(data), &popular); err != nil {
+			s.logger.Warn("skipping unreadable popular search", zap.Error(err))
+			continue
+		}
+		searches = append(searches, popular)
+	}
+	return searches, nil
+}
+
+// Warm refreshes the cached result of a popular search unless it stays fresh
+// for longer than horizon. It reports whether the search was executed.
+func (s *Service) Warm(ctx context.Context, popular PopularSearch, horizon time.Duration) (bool, error) {
+	params := popular.params()
+
+	strategy, err := s.strategies.Get(params.Scorer)
+	if err != nil {
+		return false, err
+	}
+	params.parsed, err = query.Parse(params.Query)
+	if err != nil {
+		return false, err
+	}
+
+	key := s.generateCacheKey(params)
+	if entry, ok := s.readCache(ctx, key); ok && entry.FreshUntil.After(time.Now().Add(horizon)) {
+		return false, nil
+	}
+
+	_, err, _ = s.flight.Do(key, func() (any, error) {
+		return s.fill(ctx, key, params, strategy, false)
+	})
+	if errors.Is(err, errRefreshInProgress) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// MemoryQueryLog is a QueryLog for a single instance. Like the Redis one, it
+// counts searches in buckets of window and ranks them over the current and
+// the previous bucket. A bucket is trimmed back to its maxSearches most
+// frequent searches once it grows a tenth past that.
+type MemoryQueryLog struct {
+	mu          sync.Mutex
+	window      time.Duration
+	maxSearches int
+	start       time.Time
+	current     map[string]float64
+	previous    map[string]float64
+}
+
+func NewMemoryQueryLog(window time.Duration, maxSearches int) *MemoryQueryLog {
+	if window <= 0 {
+		window = time.Hour
+	}
+	if maxSearches <= 0 {
+		maxSearches = 10000
+	}
+	return &MemoryQueryLog{
+		window:      window,
+		maxSearches: maxSearches,
+		start:       time.Now().Truncate(window),
+		current:     make(map[string]float64),
+	}
+}
+
+func (l *MemoryQueryLog) Record(ctx context.Context, search string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotate(time.Now())
+	l.current[search]++
+	if len(l.current) > l.maxSearches+l.maxSearches/10 {
+		for _, dropped := range rankCounts(l.current)[l.maxSearches:] {
+			delete(l.current, dropped)
+		}
+	}
+	return nil
+}
+
+func (l *MemoryQueryLog) Top(ctx context.Context, n int) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotate(time.Now())
+	counts := make(map[string]float64, len(l.current)+len(l.previous))
+	for search, count := range l.previous {
+		counts[search] += count
+	}
+	for search, count := range l.current {
+		counts[search] += count
+	}
+
+	top := rankCounts(counts)
+	return top[:min(n, len(top))], nil
+}
+
+// rankCounts orders searches from the most to the least frequent.
+func rankCounts(counts map[string]float64) []string {
+	ranked := make([]string, 0, len(counts))
+	for search := range counts {
+		ranked = append(ranked, search)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if counts[ranked[i]] != counts[ranked[j]] {
+			return counts[ranked[i]] > counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
+
+func (l *MemoryQueryLog) rotate(now time.Time) {
+	switch elapsed := now.Sub(l.start); {
+	case elapsed >= 2*l.window:
+		l.previous = nil
+		l.current = make(map[string]float64)
+		l.start = now.Truncate(l.window)
+	case elapsed >= l.window:
+		l.previous = l.current
+		l.current = make(map[string]float64)
+		l.start = l.start.Add(l.window)
+	}
+}
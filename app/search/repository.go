@@ -4,11 +4,21 @@ import (
 	"context"
 
 	"search-engine/domain"
+
+	"github.com/google/uuid"
 )
 
 type Repository interface {
-	Search(ctx context.Context, query string, tags []string, contentTypes []string, sortBy string, page, perPage int) ([]domain.Content, int64, error)
+	Search(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, page, perPage int) ([]domain.Content, int64, error)
+	Facets(ctx context.Context, query string, filter domain.SearchFilter, req domain.FacetRequest) (*domain.Facets, error)
+	SearchKeyset(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, keyset domain.Keyset, direction domain.CursorDirection, limit int) ([]domain.Content, int64, error)
 	SearchByProvider(ctx context.Context, provider string, query string, page, perPage int) ([]domain.Content, error)
+	FuzzySearch(ctx context.Context, query string, filter domain.SearchFilter, limit int) ([]domain.Content, error)
+	SearchTerms(ctx context.Context, limit int) (map[string]int, error)
+	Highlight(ctx context.Context, ids []uuid.UUID, tsquery string) (map[uuid.UUID]string, error)
 	Upsert(ctx context.Context, content *domain.Content) error
+	// UpsertBatch returns how many of the contents were inserted or changed.
+	UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error)
 	GetByID(ctx context.Context, id string) (*domain.Content, error)
+	GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Content, error)
 }
@@ -3,46 +3,202 @@ package search
 import (
 	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"search-engine/domain"
-	"search-engine/infra/provider"
-	"search-engine/infra/redis"
+	"search-engine/domain/dedup"
+	"search-engine/domain/fuzzy"
+	"search-engine/domain/highlight"
+	"search-engine/domain/query"
 	"search-engine/domain/scoring"
+	"search-engine/infra/provider"
+	"search-engine/pkg/cursor"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+type Mode string
+
+const (
+	ModeLive     Mode = "live"
+	ModeDatabase Mode = "database"
+	ModeHybrid   Mode = "hybrid"
+)
+
+func (m Mode) IsValid() bool {
+	return m == ModeLive || m == ModeDatabase || m == ModeHybrid
+}
+
+type EngagementSignal interface {
+	Boosts(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID]float64, error)
+}
+
+// FuzzyConfig controls the typo-tolerant fallback used when exact matching
+// finds fewer than MinResults hits. MaxResults caps the candidates it reads.
+// The dictionary is reloaded from the database once it is DictionaryRefresh
+// old, so its term frequencies follow the stored contents.
+type FuzzyConfig struct {
+	Enabled           bool
+	MinResults        int
+	MaxResults        int
+	DictionarySize    int
+	DictionaryRefresh time.Duration
+}
+
+// CursorConfig controls cursor pagination. Secret signs the cursors. Live and
+// fuzzy result lists are kept as snapshots for SnapshotTTL, with up to
+// SnapshotSize results read from each provider.
+type CursorConfig struct {
+	Secret       string
+	SnapshotTTL  time.Duration
+	SnapshotSize int
+}
+
+// ClusterStore persists duplicate clusters. Assign puts a stored content into
+// the cluster of a duplicate published within window, or a new one.
+type ClusterStore interface {
+	Assign(ctx context.Context, content *domain.Content, key dedup.Key, window time.Duration) error
+	Alternates(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID][]domain.Alternate, error)
+}
+
+// DedupConfig controls duplicate removal. Enabled is the default of the
+// per-request switch. Live results are clustered in memory; database results
+// rely on the persisted clusters, which Clusters reads alternates from.
+type DedupConfig struct {
+	Enabled      bool
+	Window       time.Duration
+	CanonicalURL bool
+	Clusters     ClusterStore
+}
+
+// CacheConfig controls how cached results are refreshed. Entries are fresh
+// for Config.CacheTTL and are then served stale for up to StaleTTL while a
+// single refresh runs in the background. Identical searches missing the cache
+// at the same time share one execution: within an instance through
+// singleflight, across instances through a Redis lock held for up to LockTTL.
+type CacheConfig struct {
+	StaleTTL time.Duration
+	LockTTL  time.Duration
+}
+
+type Config struct {
+	Mode         Mode
+	CacheTTL     time.Duration
+	MinResults   int
+	MaxStaleness time.Duration
+	Strategies   *scoring.Strategies
+	Engagement   EngagementSignal
+	Fuzzy        FuzzyConfig
+	Dictionary   *fuzzy.Dictionary
+	Highlighter  highlight.Highlighter
+	Cursor       CursorConfig
+	Dedup        DedupConfig
+	Cache        CacheConfig
+	QueryLog     QueryLog
+}
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrCursorExpired = errors.New("cursor expired, restart the search")
 )
 
 type Service struct {
 	repo            Repository
 	providerManager *provider.Manager
-	cache           *redis.RedisCache
+	cache           Cache
 	logger          *zap.Logger
-	scorer          *scoring.Scorer
+	strategies      *scoring.Strategies
 	cacheTTL        time.Duration
+	cursors         *cursor.Signer
+	deduper         *dedup.Deduper
+	config          Config
+	flight          singleflight.Group
+	refreshing      sync.Map
+	metrics         cacheMetrics
+
+	dictionaryLoadedAt  atomic.Int64
+	dictionaryReloading atomic.Bool
 }
 
-func NewService(repo Repository, pm *provider.Manager, cache *redis.RedisCache, logger *zap.Logger) *Service {
+func NewService(repo Repository, pm *provider.Manager, cache Cache, logger *zap.Logger, config Config) *Service {
+	if !config.Mode.IsValid() {
+		config.Mode = ModeLive
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 5 * time.Minute
+	}
+	if config.Strategies == nil {
+		config.Strategies = scoring.DefaultStrategies()
+	}
+	if config.Fuzzy.MinResults <= 0 {
+		config.Fuzzy.MinResults = 1
+	}
+	if config.Fuzzy.MaxResults <= 0 {
+		config.Fuzzy.MaxResults = 200
+	}
+	if config.Fuzzy.DictionarySize <= 0 {
+		config.Fuzzy.DictionarySize = 50000
+	}
+	if config.Fuzzy.DictionaryRefresh <= 0 {
+		config.Fuzzy.DictionaryRefresh = time.Hour
+	}
+	if config.Dictionary == nil {
+		config.Dictionary = fuzzy.NewDictionary(config.Fuzzy.DictionarySize)
+	}
+	config.Highlighter = highlight.NewHighlighter(config.Highlighter.PreTag, config.Highlighter.PostTag)
+	if config.Cursor.SnapshotSize <= 0 {
+		config.Cursor.SnapshotSize = 100
+	}
+	if config.Dedup.Window <= 0 {
+		config.Dedup.Window = 24 * time.Hour
+	}
+	if config.Cache.StaleTTL < 0 {
+		config.Cache.StaleTTL = 0
+	}
+	if config.Cache.LockTTL <= 0 {
+		config.Cache.LockTTL = 10 * time.Second
+	}
+	// Cached pages hand out snapshot cursors, so snapshots must outlive them,
+	// stale ones included.
+	if config.Cursor.SnapshotTTL < config.CacheTTL+config.Cache.StaleTTL {
+		config.Cursor.SnapshotTTL = config.CacheTTL + config.Cache.StaleTTL
+	}
+
 	return &Service{
 		repo:            repo,
 		providerManager: pm,
 		cache:           cache,
 		logger:          logger,
-		scorer:          scoring.NewScorer(),
-		cacheTTL:        5 * time.Minute,
+		strategies:      config.Strategies,
+		cacheTTL:        config.CacheTTL,
+		cursors:         cursor.NewSigner(config.Cursor.Secret),
+		deduper:         dedup.New(config.Dedup.Window, config.Dedup.CanonicalURL),
+		config:          config,
 	}
 }
 
 type SearchParams struct {
-	Query        string
-	Tags         []string
-	ContentTypes []string
-	SortBy       string
-	Page         int
-	PerPage      int
+	Query   string
+	Filter  domain.SearchFilter
+	SortBy  string
+	Page    int
+	PerPage int
+	Scorer  string
+	Facets  domain.FacetRequest
+	Cursor  string
+	// Dedup overrides the configured default when set.
+	Dedup *bool
+
+	parsed *query.Query
+	cursor *domain.Cursor
 }
 
 type SearchResult struct {
@@ -51,37 +207,430 @@ type SearchResult struct {
 	Page       int
 	PerPage    int
 	TotalPages int
+	Source     Mode
+	Scorer     string
+	Facets     *domain.Facets
+	Suggestion string
+	NextCursor string
+	PrevCursor string
+	// TotalEstimated is set when Total is extrapolated from a partial read of
+	// a provider's results.
+	TotalEstimated bool
 }
 
 func (s *Service) Search(ctx context.Context, params SearchParams) (*SearchResult, error) {
-	cacheKey := s.generateCacheKey(params)
+	strategy, err := s.strategies.Get(params.Scorer)
+	if err != nil {
+		return nil, err
+	}
+	params.Scorer = strategy.Name()
+
+	params.parsed, err = query.Parse(params.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Filter.Dedup = s.config.Dedup.Enabled
+	if params.Dedup != nil {
+		params.Filter.Dedup = *params.Dedup
+	}
+
+	if params.Cursor != "" {
+		params.cursor, err = s.decodeCursor(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.recordSearch(ctx, params)
+
+	return s.cachedSearch(ctx, params, strategy)
+}
+
+// execute runs a search against the configured source, bypassing the cache.
+func (s *Service) execute(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	var result *SearchResult
+	var err error
+
+	switch {
+	case params.cursor != nil && params.cursor.Snapshot != "":
+		result, err = s.searchSnapshot(ctx, params)
+	case params.cursor != nil:
+		result, err = s.searchDatabaseKeyset(ctx, params, strategy)
+	case s.config.Mode == ModeDatabase:
+		result, err = s.searchDatabase(ctx, params, strategy)
+	case s.config.Mode == ModeHybrid:
+		result, err = s.searchHybrid(ctx, params, strategy)
+	default:
+		result, err = s.searchLive(ctx, params, strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Scorer = strategy.Name()
+	if params.parsed.IsSimple() {
+		s.reloadStaleDictionary()
+		result.Suggestion = s.config.Dictionary.Suggest(params.Query)
+	}
+	return result, nil
+}
+
+func (s *Service) searchDatabase(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	contents, total, err := s.repo.Search(ctx, params.Query, params.Filter, params.SortBy, params.Page, params.PerPage)
+	if err != nil {
+		return nil, fmt.Errorf("database search failed: %w", err)
+	}
+
+	if s.useFuzzy(params, total) {
+		result, err := s.searchDatabaseFuzzy(ctx, params, strategy)
+		if err != nil {
+			s.logger.Warn("fuzzy database search failed", zap.Error(err))
+		} else if result.Total > total {
+			return result, nil
+		}
+	}
+
+	// Cursors carry the stored order, so they are taken before re-ranking.
+	var next, prev string
+	if len(contents) > 0 {
+		if int64(params.Page*params.PerPage) < total {
+			next = s.keysetCursor(params, domain.CursorNext, contents[len(contents)-1])
+		}
+		if params.Page > 1 {
+			prev = s.keysetCursor(params, domain.CursorPrev, contents[0])
+		}
+	}
+
+	contents, facets, err := s.finishDatabasePage(ctx, contents, params, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Items:      contents,
+		Total:      total,
+		Page:       params.Page,
+		PerPage:    params.PerPage,
+		TotalPages: calculateTotalPages(total, params.PerPage),
+		Source:     ModeDatabase,
+		Facets:     facets,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+// searchDatabaseKeyset reads the page next to a keyset cursor. The page number
+// is unknown, so it is left out of the result.
+func (s *Service) searchDatabaseKeyset(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	c := params.cursor
+	contents, total, err := s.repo.SearchKeyset(ctx, params.Query, params.Filter, params.SortBy, c.Keyset, c.Direction, params.PerPage+1)
+	if err != nil {
+		return nil, fmt.Errorf("database search failed: %w", err)
+	}
 
-	var cachedResult SearchResult
-	if s.cache != nil {
-		if err := s.cache.Get(ctx, cacheKey, &cachedResult); err == nil {
-			s.logger.Debug("cache hit", zap.String("cache_key", cacheKey))
-			return &cachedResult, nil
+	// The extra row only tells whether another page follows in the cursor's
+	// direction; the opposite direction always has the page we came from.
+	more := len(contents) > params.PerPage
+	if more && c.Direction == domain.CursorPrev {
+		contents = contents[1:]
+	} else if more {
+		contents = contents[:params.PerPage]
+	}
+
+	var next, prev string
+	if len(contents) > 0 {
+		if more || c.Direction == domain.CursorPrev {
+			next = s.keysetCursor(params, domain.CursorNext, contents[len(contents)-1])
+		}
+		if more || c.Direction == domain.CursorNext {
+			prev = s.keysetCursor(params, domain.CursorPrev, contents[0])
 		}
 	}
 
+	contents, facets, err := s.finishDatabasePage(ctx, contents, params, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Items:      contents,
+		Total:      total,
+		PerPage:    params.PerPage,
+		TotalPages: calculateTotalPages(total, params.PerPage),
+		Source:     ModeDatabase,
+		Facets:     facets,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+func (s *Service) finishDatabasePage(ctx context.Context, contents []domain.Content, params SearchParams, strategy scoring.Strategy) ([]domain.Content, *domain.Facets, error) {
+	// Stored scores come from the default strategy; any other strategy can
+	// only re-rank the page that was read.
+	rescored := s.rescore(contents, strategy)
+	boosted := s.applyEngagement(ctx, contents)
+	if rescored || boosted {
+		contents = s.applyFiltersAndSorting(contents, params)
+	}
+
+	var facets *domain.Facets
+	if params.Facets.Enabled() {
+		var err error
+		facets, err = s.repo.Facets(ctx, params.Query, params.Filter, params.Facets)
+		if err != nil {
+			return nil, nil, fmt.Errorf("database facets failed: %w", err)
+		}
+	}
+
+	s.applyAlternates(ctx, contents, params)
+	s.applyHighlights(ctx, contents, params, true)
+	return contents, facets, nil
+}
+
+// searchDatabaseFuzzy reads up to MaxResults trigram matches and pages them
+// in memory, like live results.
+func (s *Service) searchDatabaseFuzzy(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	contents, err := s.repo.FuzzySearch(ctx, params.Query, params.Filter, s.config.Fuzzy.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rescore(contents, strategy)
+	s.applyEngagement(ctx, contents)
+	contents = s.applyFiltersAndSorting(contents, params)
+
+	var facets *domain.Facets
+	if params.Facets.Enabled() {
+		facets = domain.ComputeFacets(contents, params.Facets)
+	}
+
+	total := int64(len(contents))
+	paginatedContents, next, prev := s.pageInMemory(ctx, contents, total, false, params, ModeDatabase)
+	s.applyAlternates(ctx, paginatedContents, params)
+	s.applyHighlights(ctx, paginatedContents, params, true)
+
+	return &SearchResult{
+		Items:      paginatedContents,
+		Total:      total,
+		Page:       params.Page,
+		PerPage:    params.PerPage,
+		TotalPages: calculateTotalPages(total, params.PerPage),
+		Source:     ModeDatabase,
+		Facets:     facets,
+		NextCursor: next,
+		PrevCursor: prev,
+	}, nil
+}
+
+// useFuzzy reports whether typo-tolerant matching should be tried. Only plain
+// word queries are corrected; operators and field filters stay exact.
+func (s *Service) useFuzzy(params SearchParams, hits int64) bool {
+	return s.config.Fuzzy.Enabled &&
+		!params.parsed.IsEmpty() &&
+		params.parsed.IsSimple() &&
+		hits < int64(s.config.Fuzzy.MinResults)
+}
+
+func (s *Service) LoadDictionary(ctx context.Context) error {
+	s.dictionaryLoadedAt.Store(time.Now().UnixNano())
+	terms, err := s.repo.SearchTerms(ctx, s.config.Fuzzy.DictionarySize)
+	if err != nil {
+		return fmt.Errorf("failed to load search terms: %w", err)
+	}
+	s.config.Dictionary.Load(terms)
+	return nil
+}
+
+// reloadStaleDictionary reloads a dictionary loaded with LoadDictionary in the
+// background once the last load, successful or not, is DictionaryRefresh old.
+func (s *Service) reloadStaleDictionary() {
+	loadedAt := s.dictionaryLoadedAt.Load()
+	if loadedAt == 0 || time.Since(time.Unix(0, loadedAt)) < s.config.Fuzzy.DictionaryRefresh ||
+		!s.dictionaryReloading.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer s.dictionaryReloading.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := s.LoadDictionary(ctx); err != nil {
+			s.logger.Warn("failed to reload dictionary", zap.Error(err))
+		}
+	}()
+}
+
+func (s *Service) searchHybrid(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
+	result, err := s.searchDatabase(ctx, params, strategy)
+	if err != nil {
+		s.logger.Warn("hybrid search database lookup failed, using live providers", zap.Error(err))
+		return s.searchLive(ctx, params, strategy)
+	}
+
+	if result.Total < int64(s.config.MinResults) {
+		s.logger.Debug("hybrid search found too few database results, using live providers",
+			zap.String("query", params.Query),
+			zap.Int64("total", result.Total),
+			zap.Int("min_results", s.config.MinResults),
+		)
+		return s.searchLive(ctx, params, strategy)
+	}
+
+	if s.isStale(result.Items) {
+		s.logger.Debug("hybrid search database results are stale, using live providers",
+			zap.String("query", params.Query),
+			zap.Duration("max_staleness", s.config.MaxStaleness),
+		)
+		return s.searchLive(ctx, params, strategy)
+	}
+
+	return result, nil
+}
+
+func (s *Service) rescore(contents []domain.Content, strategy scoring.Strategy) bool {
+	if strategy.Name() == s.strategies.Default().Name() {
+		return false
+	}
+
+	for i := range contents {
+		breakdown := strategy.Breakdown(contents[i].ToProviderContent())
+		contents[i].Breakdown = breakdown
+		contents[i].Score = breakdown.Total
+	}
+	return true
+}
+
+// applyEngagement adds the click-through signal to each score. It is best
+// effort: without engagement data results keep their provider-based scores.
+func (s *Service) applyEngagement(ctx context.Context, contents []domain.Content) bool {
+	if s.config.Engagement == nil || len(contents) == 0 {
+		return false
+	}
+
+	ids := make([]uuid.UUID, len(contents))
+	for i, content := range contents {
+		ids[i] = content.ID
+	}
+
+	boosts, err := s.config.Engagement.Boosts(ctx, ids)
+	if err != nil {
+		s.logger.Warn("failed to load engagement signal", zap.Error(err))
+		return false
+	}
+
+	for i := range contents {
+		boost := math.Round(boosts[contents[i].ID]*100) / 100
+		contents[i].Breakdown.ClickBoost = boost
+		contents[i].Breakdown.Total = math.Round((contents[i].Score+boost)*100) / 100
+		contents[i].Score = contents[i].Breakdown.Total
+	}
+	return true
+}
+
+// applyAlternates lists the duplicates hidden by the database queries. It is
+// best effort: without cluster data results are returned without alternates.
+func (s *Service) applyAlternates(ctx context.Context, contents []domain.Content, params SearchParams) {
+	if !params.Filter.Dedup || s.config.Dedup.Clusters == nil || len(contents) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(contents))
+	for i, content := range contents {
+		ids[i] = content.ID
+	}
+
+	alternates, err := s.config.Dedup.Clusters.Alternates(ctx, ids)
+	if err != nil {
+		s.logger.Warn("failed to load duplicate alternates", zap.Error(err))
+		return
+	}
+
+	for i := range contents {
+		contents[i].Alternates = alternates[contents[i].ID]
+	}
+}
+
+// applyHighlights marks the query words in each title and the matching tags.
+// Database titles are marked by ts_headline; live results, and database
+// results when that fails, use the same tokenizer in memory.
+func (s *Service) applyHighlights(ctx context.Context, contents []domain.Content, params SearchParams, fromDatabase bool) {
+	terms := highlight.TermsFromQuery(params.parsed)
+	if terms.IsEmpty() || len(contents) == 0 {
+		return
+	}
+
+	var headlines map[uuid.UUID]string
+	if fromDatabase && len(terms.Words) > 0 {
+		ids := make([]uuid.UUID, len(contents))
+		for i, content := range contents {
+			ids[i] = content.ID
+		}
+
+		var err error
+		headlines, err = s.repo.Highlight(ctx, ids, terms.TSQuery())
+		if err != nil {
+			s.logger.Warn("failed to highlight database results", zap.Error(err))
+		}
+	}
+
+	for i := range contents {
+		marked, ok := headlines[contents[i].ID]
+		if !ok {
+			marked = terms.Mark(contents[i].Title)
+		}
+		contents[i].Highlights = s.config.Highlighter.Highlights(marked, terms.MarkTags(contents[i].Tags))
+	}
+}
+
+func (s *Service) isStale(contents []domain.Content) bool {
+	if s.config.MaxStaleness <= 0 {
+		return false
+	}
+
+	threshold := time.Now().Add(-s.config.MaxStaleness)
+	for _, content := range contents {
+		if content.UpdatedAt.Before(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) searchLive(ctx context.Context, params SearchParams, strategy scoring.Strategy) (*SearchResult, error) {
 	s.logger.Info("fetching from providers with pagination",
 		zap.String("query", params.Query),
 		zap.Int("page", params.Page),
 		zap.Int("per_page", params.PerPage),
 	)
 
-	providerResults := s.providerManager.SearchAllWithPagination(ctx, params.Query, params.Page, params.PerPage)
+	// Every provider is asked for everything up to the requested page, so the
+	// global page is filled whichever providers its results come from. With a
+	// cache the window covers a whole snapshot, so cursors can page past it
+	// without refetching. Providers only understand plain text, so structured
+	// queries push down what they can and the rest is matched locally.
+	window := params.Page * params.PerPage
+	if s.cache != nil && window < s.config.Cursor.SnapshotSize {
+		window = s.config.Cursor.SnapshotSize
+	}
+	text := params.parsed.ProviderText()
+	providerResults := s.providerManager.SearchAllWithPagination(ctx, text, 1, window)
 
-	var allContents []domain.Content
+	sources := make([]*liveSource, 0, len(providerResults))
+	hits := 0
 
 	for _, result := range providerResults {
+		source := &liveSource{provider: result.Provider}
+		sources = append(sources, source)
+
 		if result.Error != nil {
 			s.logger.Warn("provider failed, falling back to database",
 				zap.String("provider", result.Provider),
 				zap.Error(result.Error),
 			)
 
-			dbContents, err := s.repo.SearchByProvider(ctx, result.Provider, params.Query, params.Page, params.PerPage)
+			dbContents, err := s.repo.SearchByProvider(ctx, result.Provider, params.Query, 1, window)
 			if err != nil {
 				s.logger.Error("database fallback also failed",
 					zap.String("provider", result.Provider),
@@ -95,149 +644,403 @@ func (s *Service) Search(ctx context.Context, params SearchParams) (*SearchResul
 				zap.Int("count", len(dbContents)),
 			)
 
-			allContents = append(allContents, dbContents...)
+			s.rescore(dbContents, strategy)
+			source.matched = dbContents
+			source.fetched = len(dbContents)
+			source.reported = len(dbContents)
+			source.estimated = len(dbContents) >= window
 		} else {
-			for _, pc := range result.Contents {
-				score := s.scorer.CalculateScore(pc)
-
-				content := domain.Content{
-					ID:          domain.NewUUID(),
-					ExternalID:  pc.ExternalID,
-					Provider:    result.Provider,
-					Title:       pc.Title,
-					Type:        domain.ContentType(pc.Type),
-					PublishedAt: pc.PublishedAt,
-					Views:       pc.Views,
-					Likes:       pc.Likes,
-					Reactions:   pc.Reactions,
-					ReadingTime: pc.ReadingTime,
-					Score:       score,
-					Tags:        pc.Tags,
-					RawData:     pc.RawData,
-					CreatedAt:   time.Now(),
-					UpdatedAt:   time.Now(),
-				}
+			s.collectLive(source, result, params, strategy)
 
-				allContents = append(allContents, content)
+			// Results dropped by local matching leave the window short;
+			// read further pages until it is filled or the provider runs out.
+			for refill := 0; refill < maxLiveRefills && source.short(params, window); refill++ {
+				more := s.providerManager.SearchProviderWithPagination(ctx, result.Provider, text, source.fetched/window+1, window)
+				if more.Error != nil || len(more.Contents) == 0 {
+					break
+				}
+				s.collectLive(source, more, params, strategy)
 			}
-
-			go s.persistContentsToDatabase(context.Background(), result.Contents, result.Provider)
 		}
+		hits += len(source.matched)
 	}
 
-	filteredContents := s.applyFiltersAndSorting(allContents, params)
+	useFuzzy := s.useFuzzy(params, int64(hits))
 
-	paginatedContents, total := s.paginateResults(filteredContents, params.Page, params.PerPage)
+	var all []domain.Content
+	for _, source := range sources {
+		if useFuzzy {
+			source.matched = append(source.matched, source.approximate...)
+		}
+		all = append(all, source.matched...)
+	}
+	s.applyEngagement(ctx, all)
 
-	totalPages := int(total) / params.PerPage
-	if int(total)%params.PerPage != 0 {
-		totalPages++
+	lists := make([][]domain.Content, len(sources))
+	var total int64
+	var estimated bool
+	offset := 0
+	for i, source := range sources {
+		// Engagement was applied to the copies in all.
+		list := all[offset : offset+len(source.matched)]
+		offset += len(source.matched)
+
+		lists[i] = s.applyFiltersAndSorting(list, params)
+		sourceTotal, sourceEstimated := source.total(len(lists[i]))
+		total += sourceTotal
+		estimated = estimated || sourceEstimated
+	}
+	merged := domain.MergeSorted(lists, params.SortBy)
+	if params.Filter.Dedup {
+		var removed int
+		merged, removed = s.deduper.Dedup(merged)
+		total -= int64(removed)
 	}
 
-	result := &SearchResult{
-		Items:      paginatedContents,
-		Total:      total,
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		TotalPages: totalPages,
+	var facets *domain.Facets
+	if params.Facets.Enabled() {
+		facets = domain.ComputeFacets(merged, params.Facets)
 	}
 
-	if s.cache != nil {
-		if err := s.cache.Set(ctx, cacheKey, result, s.cacheTTL); err != nil {
-			s.logger.Warn("failed to cache result",
-				zap.Error(err),
-				zap.String("cache_key", cacheKey),
-			)
+	paginatedContents, next, prev := s.pageInMemory(ctx, merged, total, estimated, params, ModeLive)
+	s.applyHighlights(ctx, paginatedContents, params, false)
+
+	return &SearchResult{
+		Items:          paginatedContents,
+		Total:          total,
+		TotalEstimated: estimated,
+		Page:           params.Page,
+		PerPage:        params.PerPage,
+		TotalPages:     calculateTotalPages(total, params.PerPage),
+		Source:         ModeLive,
+		Facets:         facets,
+		NextCursor:     next,
+		PrevCursor:     prev,
+	}, nil
+}
+
+// maxLiveRefills bounds the extra pages read from one provider per search.
+const maxLiveRefills = 3
+
+// liveSource is what one provider contributed to a live search.
+type liveSource struct {
+	provider    string
+	matched     []domain.Content
+	approximate []domain.Content
+	fetched     int
+	reported    int
+	estimated   bool
+}
+
+func (s *Service) collectLive(source *liveSource, result provider.ProviderResult, params SearchParams, strategy scoring.Strategy) {
+	for _, pc := range result.Contents {
+		content := domain.NewContentFromProvider(pc, result.Provider, strategy.Breakdown(pc))
+		if params.parsed.Match(content) {
+			source.matched = append(source.matched, content)
+		} else if params.parsed.IsSimple() && fuzzy.MatchText(params.Query, content.Title) {
+			source.approximate = append(source.approximate, content)
 		}
 	}
 
-	return result, nil
+	source.fetched += len(result.Contents)
+	source.reported = max(source.reported, source.fetched)
+	if result.Pagination != nil {
+		source.reported = max(source.reported, result.Pagination.Total)
+	}
+
+	go s.persistContentsToDatabase(context.Background(), result.Contents, result.Provider)
 }
 
-func (s *Service) generateCacheKey(params SearchParams) string {
-	keyData := fmt.Sprintf("q=%s&tags=%v&types=%v&sort=%s&page=%d&per_page=%d",
-		params.Query,
-		params.Tags,
-		params.ContentTypes,
-		params.SortBy,
-		params.Page,
-		params.PerPage,
-	)
+// short reports whether the provider has more results while fewer than
+// window of the fetched ones matched locally.
+func (src *liveSource) short(params SearchParams, window int) bool {
+	if src.fetched >= src.reported {
+		return false
+	}
+	kept := 0
+	for _, content := range src.matched {
+		if params.Filter.Match(content) {
+			kept++
+		}
+	}
+	return kept < window
+}
 
-	hash := md5.Sum([]byte(keyData))
-	return fmt.Sprintf("search:%x", hash)
+// total is the number of the provider's results that match, given kept of
+// the fetched ones did. When the provider has more results than were fetched
+// and some were dropped locally, the rest is extrapolated at the same rate.
+func (src *liveSource) total(kept int) (int64, bool) {
+	if src.fetched >= src.reported {
+		return int64(kept), src.estimated
+	}
+	if kept == src.fetched {
+		return int64(src.reported), src.estimated
+	}
+	rest := (src.reported - src.fetched) * kept / src.fetched
+	return int64(kept + rest), true
 }
 
-func (s *Service) mergeAndScoreResults(providerResults []provider.ProviderResult) []domain.Content {
-	var allContents []domain.Content
+// snapshot is a sorted in-memory result list kept for cursor pagination.
+type snapshot struct {
+	Items          []domain.Content        `json:"items"`
+	Breakdowns     []domain.ScoreBreakdown `json:"breakdowns,omitempty"`
+	Total          int64                   `json:"total"`
+	TotalEstimated bool                    `json:"total_estimated"`
+	Source         Mode                    `json:"source"`
+}
 
-	for _, result := range providerResults {
-		if result.Error != nil {
-			s.logger.Warn("provider search failed",
-				zap.String("provider", result.Provider),
-				zap.Error(result.Error),
-			)
-			continue
+// breakdownsOf lists the score breakdowns of contents. They are left out of
+// the contents' JSON, so cached copies store them alongside.
+func breakdownsOf(contents []domain.Content) []domain.ScoreBreakdown {
+	breakdowns := make([]domain.ScoreBreakdown, len(contents))
+	for i, content := range contents {
+		breakdowns[i] = content.Breakdown
+	}
+	return breakdowns
+}
+
+// restoreBreakdowns copies contents with their stored breakdowns put back.
+// The copy keeps contents shared with an in-process cache untouched.
+func restoreBreakdowns(contents []domain.Content, breakdowns []domain.ScoreBreakdown) []domain.Content {
+	restored := append([]domain.Content{}, contents...)
+	if len(breakdowns) == len(restored) {
+		for i := range restored {
+			restored[i].Breakdown = breakdowns[i]
 		}
+	}
+	return restored
+}
 
-		for _, providerContent := range result.Contents {
-			content := domain.Content{
-				ID:          domain.NewUUID(),
-				ExternalID:  providerContent.ExternalID,
-				Provider:    result.Provider,
-				Title:       providerContent.Title,
-				Type:        domain.ContentType(providerContent.Type),
-				PublishedAt: providerContent.PublishedAt,
-				Views:       providerContent.Views,
-				Likes:       providerContent.Likes,
-				Reactions:   providerContent.Reactions,
-				ReadingTime: providerContent.ReadingTime,
-				Tags:        providerContent.Tags,
-				RawData:     providerContent.RawData,
-				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Now(),
-			}
+func snapshotKey(id string) string {
+	return "search:snapshot:" + id
+}
 
-			content.Score = s.scorer.CalculateScore(providerContent)
-			allContents = append(allContents, content)
-		}
+// pageInMemory pages a sorted result list. When there is more than one page
+// the list is stored as a snapshot, and the cursors page through exactly
+// these results instead of fetching them again.
+func (s *Service) pageInMemory(ctx context.Context, contents []domain.Content, total int64, estimated bool, params SearchParams, source Mode) ([]domain.Content, string, string) {
+	page, _ := s.paginateResults(contents, params.Page, params.PerPage)
+
+	start := (params.Page - 1) * params.PerPage
+	if s.cache == nil || (start == 0 && len(contents) <= params.PerPage) {
+		return page, "", ""
 	}
 
-	return allContents
+	snap := snapshot{Items: contents, Breakdowns: breakdownsOf(contents), Total: total, TotalEstimated: estimated, Source: source}
+	id := uuid.NewString()
+	if err := s.cache.Set(ctx, snapshotKey(id), snap, s.config.Cursor.SnapshotTTL); err != nil {
+		s.logger.Warn("failed to store result snapshot", zap.Error(err))
+		return page, "", ""
+	}
+
+	next, prev := s.snapshotCursors(params, id, start, len(contents))
+	return page, next, prev
 }
 
-func (s *Service) applyFiltersAndSorting(contents []domain.Content, params SearchParams) []domain.Content {
-	if len(params.ContentTypes) > 0 {
-		typeMap := make(map[string]bool)
-		for _, ct := range params.ContentTypes {
-			typeMap[ct] = true
-		}
+func (s *Service) searchSnapshot(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	if s.cache == nil {
+		return nil, ErrCursorExpired
+	}
 
-		var filtered []domain.Content
-		for _, content := range contents {
-			if typeMap[string(content.Type)] {
-				filtered = append(filtered, content)
-			}
+	var snap snapshot
+	if err := s.cache.Get(ctx, snapshotKey(params.cursor.Snapshot), &snap); err != nil {
+		if errors.Is(err, ErrCacheMiss) {
+			return nil, ErrCursorExpired
 		}
-		contents = filtered
+		return nil, fmt.Errorf("failed to load result snapshot: %w", err)
 	}
 
-	if len(params.Tags) > 0 {
-		tagMap := make(map[string]bool)
-		for _, tag := range params.Tags {
-			tagMap[strings.ToLower(tag)] = true
-		}
+	start := min(params.cursor.Offset, len(snap.Items))
+	end := min(start+params.PerPage, len(snap.Items))
+	var breakdowns []domain.ScoreBreakdown
+	if len(snap.Breakdowns) == len(snap.Items) {
+		breakdowns = snap.Breakdowns[start:end]
+	}
+	page := restoreBreakdowns(snap.Items[start:end], breakdowns)
+
+	var facets *domain.Facets
+	if params.Facets.Enabled() {
+		facets = domain.ComputeFacets(snap.Items, params.Facets)
+	}
+	s.applyHighlights(ctx, page, params, false)
+
+	next, prev := s.snapshotCursors(params, params.cursor.Snapshot, start, len(snap.Items))
+	return &SearchResult{
+		Items:          page,
+		Total:          snap.Total,
+		TotalEstimated: snap.TotalEstimated,
+		Page:           start/params.PerPage + 1,
+		PerPage:        params.PerPage,
+		TotalPages:     calculateTotalPages(snap.Total, params.PerPage),
+		Source:         snap.Source,
+		Facets:         facets,
+		NextCursor:     next,
+		PrevCursor:     prev,
+	}, nil
+}
+
+func (s *Service) snapshotCursors(params SearchParams, id string, start, size int) (string, string) {
+	var next, prev string
+	if start+params.PerPage < size {
+		next = s.encodeCursor(domain.Cursor{
+			Search:    s.searchFingerprint(params),
+			Direction: domain.CursorNext,
+			Snapshot:  id,
+			Offset:    start + params.PerPage,
+		})
+	}
+	if start > 0 {
+		prev = s.encodeCursor(domain.Cursor{
+			Search:    s.searchFingerprint(params),
+			Direction: domain.CursorPrev,
+			Snapshot:  id,
+			Offset:    max(start-params.PerPage, 0),
+		})
+	}
+	return next, prev
+}
+
+func (s *Service) keysetCursor(params SearchParams, direction domain.CursorDirection, content domain.Content) string {
+	return s.encodeCursor(domain.Cursor{
+		Search:    s.searchFingerprint(params),
+		Direction: direction,
+		Keyset:    domain.KeysetOf(content),
+	})
+}
+
+func (s *Service) encodeCursor(c domain.Cursor) string {
+	token, err := s.cursors.Encode(c)
+	if err != nil {
+		s.logger.Warn("failed to encode cursor", zap.Error(err))
+		return ""
+	}
+	return token
+}
+
+// decodeCursor verifies the cursor and that it was issued for the same query,
+// filters, sort and page size.
+func (s *Service) decodeCursor(params SearchParams) (*domain.Cursor, error) {
+	var c domain.Cursor
+	if err := s.cursors.Decode(params.Cursor, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Search != s.searchFingerprint(params) {
+		return nil, ErrInvalidCursor
+	}
+	if c.Direction != domain.CursorNext && c.Direction != domain.CursorPrev {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func (s *Service) GetContent(ctx context.Context, id string, includeRaw bool) (*domain.ContentDetail, error) {
+	content, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.contentDetail(content, includeRaw), nil
+}
+
+func (s *Service) GetContentByExternalID(ctx context.Context, providerName, externalID string, includeRaw bool) (*domain.ContentDetail, error) {
+	content, err := s.repo.GetByExternalID(ctx, providerName, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.contentDetail(content, includeRaw), nil
+}
+
+func (s *Service) contentDetail(content *domain.Content, includeRaw bool) *domain.ContentDetail {
+	detail := &domain.ContentDetail{ContentWithScore: s.withScore(*content, s.strategies.Default())}
+	if includeRaw && len(content.RawData) > 0 {
+		detail.RawData = content.RawData
+	}
+	return detail
+}
+
+func (s *Service) Explain(contents []domain.Content, scorer string) ([]domain.ContentWithScore, error) {
+	strategy, err := s.strategies.Get(scorer)
+	if err != nil {
+		return nil, err
+	}
 
+	explained := make([]domain.ContentWithScore, len(contents))
+	for i, content := range contents {
+		explained[i] = s.withScore(content, strategy)
+	}
+	return explained, nil
+}
+
+func (s *Service) ExplainScore(content domain.ProviderContent, scorer string) (domain.ScoreBreakdown, error) {
+	strategy, err := s.strategies.Get(scorer)
+	if err != nil {
+		return domain.ScoreBreakdown{}, err
+	}
+	return strategy.Breakdown(content), nil
+}
+
+func (s *Service) withScore(content domain.Content, strategy scoring.Strategy) domain.ContentWithScore {
+	// Rows stored before score components were persisted carry no breakdown,
+	// so it is recomputed from the content itself.
+	if !content.Breakdown.Explains(content.Score) {
+		content.Breakdown = strategy.Breakdown(content.ToProviderContent())
+	}
+	return domain.NewContentWithScore(content)
+}
+
+func calculateTotalPages(total int64, perPage int) int {
+	totalPages := int(total) / perPage
+	if int(total)%perPage != 0 {
+		totalPages++
+	}
+	return totalPages
+}
+
+// searchKey identifies an ordered result list, independent of the page.
+func (s *Service) searchKey(params SearchParams) string {
+	filter := params.Filter
+	return fmt.Sprintf("q=%s&tags=%v:%s&exclude_tags=%v&types=%v&providers=%v&published=%s:%s&min=%d:%d:%d&max_reading_time=%d&dedup=%t&sort=%s&per_page=%d&scorer=%s",
+		params.Query,
+		filter.Tags,
+		filter.TagMode,
+		filter.ExcludeTags,
+		filter.ContentTypes,
+		filter.Providers,
+		filter.PublishedFrom.Format(time.RFC3339Nano),
+		filter.PublishedTo.Format(time.RFC3339Nano),
+		filter.MinViews,
+		filter.MinLikes,
+		filter.MinReactions,
+		filter.MaxReadingTime,
+		filter.Dedup,
+		params.SortBy,
+		params.PerPage,
+		params.Scorer,
+	)
+}
+
+func (s *Service) searchFingerprint(params SearchParams) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(s.searchKey(params))))
+}
+
+func (s *Service) generateCacheKey(params SearchParams) string {
+	keyData := fmt.Sprintf("%s&page=%d&cursor=%s&facets=%v:%s:%d",
+		s.searchKey(params),
+		params.Page,
+		params.Cursor,
+		params.Facets.Fields,
+		params.Facets.Interval,
+		params.Facets.Size,
+	)
+
+	hash := md5.Sum([]byte(keyData))
+	return fmt.Sprintf("search:%x", hash)
+}
+
+func (s *Service) applyFiltersAndSorting(contents []domain.Content, params SearchParams) []domain.Content {
+	if !params.Filter.IsEmpty() {
 		var filtered []domain.Content
 		for _, content := range contents {
-			hasMatchingTag := false
-			for _, contentTag := range content.Tags {
-				if tagMap[strings.ToLower(contentTag)] {
-					hasMatchingTag = true
-					break
-				}
-			}
-			if hasMatchingTag {
+			if params.Filter.Match(content) {
 				filtered = append(filtered, content)
 			}
 		}
@@ -245,18 +1048,7 @@ func (s *Service) applyFiltersAndSorting(contents []domain.Content, params Searc
 	}
 
 	sort.Slice(contents, func(i, j int) bool {
-		switch params.SortBy {
-		case "popularity":
-			if contents[i].Type == "video" && contents[j].Type == "video" {
-				return contents[i].Views > contents[j].Views
-			}
-			if contents[i].Type == "text" && contents[j].Type == "text" {
-				return contents[i].Reactions > contents[j].Reactions
-			}
-			return contents[i].Score > contents[j].Score
-		default:
-			return contents[i].Score > contents[j].Score
-		}
+		return domain.RanksBefore(contents[i], contents[j], params.SortBy)
 	})
 
 	return contents
@@ -291,27 +1083,9 @@ func (s *Service) persistContentsToDatabase(ctx context.Context, providerContent
 
 	successCount := 0
 	for _, pc := range providerContents {
-		score := s.scorer.CalculateScore(pc)
-
-		content := &domain.Content{
-			ID:          domain.NewUUID(),
-			ExternalID:  pc.ExternalID,
-			Provider:    providerName,
-			Title:       pc.Title,
-			Type:        domain.ContentType(pc.Type),
-			PublishedAt: pc.PublishedAt,
-			Views:       pc.Views,
-			Likes:       pc.Likes,
-			Reactions:   pc.Reactions,
-			ReadingTime: pc.ReadingTime,
-			Score:       score,
-			Tags:        pc.Tags,
-			RawData:     pc.RawData,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
+		content := domain.NewContentFromProvider(pc, providerName, s.strategies.Default().Breakdown(pc))
 
-		if err := s.repo.Upsert(ctx, content); err != nil {
+		if err := s.repo.Upsert(ctx, &content); err != nil {
 			s.logger.Error("failed to upsert content",
 				zap.Error(err),
 				zap.String("provider", providerName),
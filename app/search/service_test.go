@@ -0,0 +1,894 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/dedup"
+	"search-engine/domain/fuzzy"
+	"search-engine/domain/highlight"
+	"search-engine/domain/query"
+	"search-engine/domain/scoring"
+	"search-engine/infra/provider"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	contents    []domain.Content
+	fuzzy       []domain.Content
+	terms       map[string]int
+	headlines   map[uuid.UUID]string
+	searchCalls int
+}
+
+// The fake keeps contents in result order.
+func (r *fakeRepository) Search(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, page, perPage int) ([]domain.Content, int64, error) {
+	r.searchCalls++
+	start := min((page-1)*perPage, len(r.contents))
+	end := min(start+perPage, len(r.contents))
+	return append([]domain.Content{}, r.contents[start:end]...), int64(len(r.contents)), nil
+}
+
+func (r *fakeRepository) SearchKeyset(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, keyset domain.Keyset, direction domain.CursorDirection, limit int) ([]domain.Content, int64, error) {
+	position := -1
+	for i, content := range r.contents {
+		if content.ID == keyset.ID {
+			position = i
+		}
+	}
+
+	start, end := position+1, min(position+1+limit, len(r.contents))
+	if direction == domain.CursorPrev {
+		start, end = max(position-limit, 0), position
+	}
+	return append([]domain.Content{}, r.contents[start:end]...), int64(len(r.contents)), nil
+}
+
+func (r *fakeRepository) Facets(ctx context.Context, query string, filter domain.SearchFilter, req domain.FacetRequest) (*domain.Facets, error) {
+	return domain.ComputeFacets(r.contents, req), nil
+}
+
+func (r *fakeRepository) SearchByProvider(ctx context.Context, provider string, query string, page, perPage int) ([]domain.Content, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) FuzzySearch(ctx context.Context, query string, filter domain.SearchFilter, limit int) ([]domain.Content, error) {
+	return r.fuzzy, nil
+}
+
+func (r *fakeRepository) SearchTerms(ctx context.Context, limit int) (map[string]int, error) {
+	return r.terms, nil
+}
+
+func (r *fakeRepository) Highlight(ctx context.Context, ids []uuid.UUID, tsquery string) (map[uuid.UUID]string, error) {
+	return r.headlines, nil
+}
+
+func (r *fakeRepository) Upsert(ctx context.Context, content *domain.Content) error {
+	return nil
+}
+
+func (r *fakeRepository) UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error) {
+	return len(contents), nil
+}
+
+func (r *fakeRepository) GetByID(ctx context.Context, id string) (*domain.Content, error) {
+	for i := range r.contents {
+		if r.contents[i].ID.String() == id {
+			return &r.contents[i], nil
+		}
+	}
+	return nil, domain.ErrContentNotFound
+}
+
+func (r *fakeRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Content, error) {
+	for i := range r.contents {
+		if r.contents[i].Provider == provider && r.contents[i].ExternalID == externalID {
+			return &r.contents[i], nil
+		}
+	}
+	return nil, domain.ErrContentNotFound
+}
+
+func newTestService(repo Repository, config Config) *Service {
+	return NewService(repo, provider.NewManager(time.Second), nil, zap.NewNop(), config)
+}
+
+func TestService_SearchModes(t *testing.T) {
+	fresh := domain.Content{ID: domain.NewUUID(), Title: "Go", UpdatedAt: time.Now()}
+	stale := domain.Content{ID: domain.NewUUID(), Title: "Go", UpdatedAt: time.Now().Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name     string
+		config   Config
+		contents []domain.Content
+		source   Mode
+	}{
+		{"live mode ignores database", Config{Mode: ModeLive}, []domain.Content{fresh}, ModeLive},
+		{"database mode", Config{Mode: ModeDatabase}, []domain.Content{stale}, ModeDatabase},
+		{"hybrid serves fresh database results", Config{Mode: ModeHybrid, MinResults: 1, MaxStaleness: time.Hour}, []domain.Content{fresh}, ModeDatabase},
+		{"hybrid falls back when too few", Config{Mode: ModeHybrid, MinResults: 2}, []domain.Content{fresh}, ModeLive},
+		{"hybrid falls back when stale", Config{Mode: ModeHybrid, MinResults: 1, MaxStaleness: time.Hour}, []domain.Content{fresh, stale}, ModeLive},
+		{"invalid mode defaults to live", Config{Mode: "bogus"}, []domain.Content{fresh}, ModeLive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{contents: tt.contents}
+			service := newTestService(repo, tt.config)
+
+			result, err := service.Search(context.Background(), SearchParams{Query: "go", Page: 1, PerPage: 20})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			if result.Source != tt.source {
+				t.Errorf("Source = %q, want %q", result.Source, tt.source)
+			}
+		})
+	}
+}
+
+func TestService_GetContent(t *testing.T) {
+	content := domain.Content{
+		ID:          domain.NewUUID(),
+		ExternalID:  "v1",
+		Provider:    "provider1",
+		Title:       "Go",
+		Type:        domain.ContentTypeVideo,
+		PublishedAt: time.Now(),
+		Views:       1000,
+		Likes:       50,
+		RawData:     []byte(`{"id":"v1"}`),
+	}
+	service := newTestService(&fakeRepository{contents: []domain.Content{content}}, Config{})
+
+	detail, err := service.GetContent(context.Background(), content.ID.String(), false)
+	if err != nil {
+		t.Fatalf("GetContent() error = %v", err)
+	}
+	if detail.ScoreBreakdown.TypeMultiplier == 0 || detail.Metadata.Views != 1000 {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+	if detail.RawData != nil {
+		t.Errorf("RawData = %s, want omitted", detail.RawData)
+	}
+
+	detail, err = service.GetContentByExternalID(context.Background(), "provider1", "v1", true)
+	if err != nil {
+		t.Fatalf("GetContentByExternalID() error = %v", err)
+	}
+	if string(detail.RawData) != `{"id":"v1"}` {
+		t.Errorf("RawData = %s, want raw provider payload", detail.RawData)
+	}
+
+	if _, err := service.GetContentByExternalID(context.Background(), "provider1", "missing", false); !errors.Is(err, domain.ErrContentNotFound) {
+		t.Errorf("GetContentByExternalID(missing) error = %v, want ErrContentNotFound", err)
+	}
+}
+
+func TestService_Explain(t *testing.T) {
+	service := newTestService(&fakeRepository{}, Config{})
+
+	persisted := domain.Content{
+		Type:      domain.ContentTypeText,
+		Score:     12,
+		Breakdown: domain.ScoreBreakdown{BaseScore: 10, TypeMultiplier: 1, FreshnessScore: 2, Total: 12},
+	}
+	cached := domain.Content{
+		Type:        domain.ContentTypeText,
+		ReadingTime: 10,
+		PublishedAt: time.Now(),
+	}
+
+	explained, err := service.Explain([]domain.Content{persisted, cached}, "")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+
+	if explained[0].ScoreBreakdown != persisted.Breakdown {
+		t.Errorf("persisted breakdown = %+v, want %+v", explained[0].ScoreBreakdown, persisted.Breakdown)
+	}
+	if explained[1].ScoreBreakdown.TypeMultiplier != 1 || explained[1].ScoreBreakdown.BaseScore != 10 {
+		t.Errorf("recomputed breakdown = %+v", explained[1].ScoreBreakdown)
+	}
+	if explained[1].Metadata.ReadingTime != 10 {
+		t.Errorf("Metadata = %+v, want reading time 10", explained[1].Metadata)
+	}
+}
+
+func TestService_SearchWithScorer(t *testing.T) {
+	heavy := scoring.NewWeightedScorer("video_heavy", scoring.Weights{VideoMultiplier: scoring.Weight(10)})
+	strategies, err := scoring.NewStrategies(scoring.DefaultStrategy, heavy)
+	if err != nil {
+		t.Fatalf("NewStrategies() error = %v", err)
+	}
+
+	video := domain.Content{ID: domain.NewUUID(), Type: domain.ContentTypeVideo, Views: 10000, Score: 1}
+	text := domain.Content{ID: domain.NewUUID(), Type: domain.ContentTypeText, ReadingTime: 5, Score: 5}
+	repo := &fakeRepository{contents: []domain.Content{text, video}}
+	service := newTestService(repo, Config{Mode: ModeDatabase, Strategies: strategies})
+
+	result, err := service.Search(context.Background(), SearchParams{Page: 1, PerPage: 20, Scorer: "video_heavy"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Items[0].ID != video.ID || result.Items[0].Score != 100 {
+		t.Errorf("Items[0] = %+v, want rescored video first", result.Items[0])
+	}
+	if result.Scorer != "video_heavy" {
+		t.Errorf("Scorer = %q, want video_heavy", result.Scorer)
+	}
+
+	if _, err := service.Search(context.Background(), SearchParams{Page: 1, PerPage: 20, Scorer: "missing"}); !errors.Is(err, scoring.ErrUnknownStrategy) {
+		t.Errorf("Search(missing scorer) error = %v, want ErrUnknownStrategy", err)
+	}
+}
+
+type fakeEngagement map[uuid.UUID]float64
+
+func (e fakeEngagement) Boosts(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	return e, nil
+}
+
+func TestService_SearchAppliesEngagement(t *testing.T) {
+	popular := domain.Content{ID: domain.NewUUID(), Type: domain.ContentTypeText, Score: 5, Breakdown: domain.ScoreBreakdown{TypeMultiplier: 1, Total: 5}}
+	clicked := domain.Content{ID: domain.NewUUID(), Type: domain.ContentTypeText, Score: 4, Breakdown: domain.ScoreBreakdown{TypeMultiplier: 1, Total: 4}}
+
+	repo := &fakeRepository{contents: []domain.Content{popular, clicked}}
+	service := newTestService(repo, Config{Mode: ModeDatabase, Engagement: fakeEngagement{clicked.ID: 2.5}})
+
+	result, err := service.Search(context.Background(), SearchParams{Page: 1, PerPage: 20})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	first := result.Items[0]
+	if first.ID != clicked.ID || first.Score != 6.5 || first.Breakdown.ClickBoost != 2.5 {
+		t.Errorf("Items[0] = %+v, want clicked content boosted to 6.5", first)
+	}
+}
+
+// jsonCache stores values as JSON like the Redis cache, which drops the
+// fields hidden from JSON.
+type jsonCache struct {
+	*MemoryCache
+}
+
+func (c jsonCache) Get(ctx context.Context, key string, dest interface{}) error {
+	var data []byte
+	if err := c.MemoryCache.Get(ctx, key, &data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c jsonCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.MemoryCache.Set(ctx, key, data, ttl)
+}
+
+func TestService_CachedResultsKeepClickBoost(t *testing.T) {
+	clicked := domain.Content{ID: domain.NewUUID(), Type: domain.ContentTypeText, ReadingTime: 4, Score: 4, Breakdown: domain.ScoreBreakdown{BaseScore: 4, TypeMultiplier: 1, Total: 4}}
+
+	repo := &fakeRepository{contents: []domain.Content{clicked}}
+	service := NewService(repo, provider.NewManager(time.Second), jsonCache{NewMemoryCache(10)}, zap.NewNop(), Config{
+		Mode:       ModeDatabase,
+		CacheTTL:   time.Minute,
+		Engagement: fakeEngagement{clicked.ID: 2.5},
+	})
+
+	params := SearchParams{Page: 1, PerPage: 20}
+	if _, err := service.Search(context.Background(), params); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	cached, err := service.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if stats := service.CacheStats(); stats.Hits != 1 {
+		t.Fatalf("CacheStats() = %+v, want one hit", stats)
+	}
+
+	explained, err := service.Explain(cached.Items, "")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	breakdown := explained[0].ScoreBreakdown
+	if breakdown.ClickBoost != 2.5 || breakdown.Total != 6.5 || breakdown.Total != cached.Items[0].Score {
+		t.Errorf("breakdown = %+v, want click boost 2.5 adding up to score %v", breakdown, cached.Items[0].Score)
+	}
+}
+
+func TestService_SearchRejectsInvalidQuery(t *testing.T) {
+	service := newTestService(&fakeRepository{}, Config{Mode: ModeDatabase})
+
+	_, err := service.Search(context.Background(), SearchParams{Query: "go AND (rust", Page: 1, PerPage: 20})
+
+	var syntaxErr *query.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Search() error = %v, want SyntaxError", err)
+	}
+	if syntaxErr.Position != 13 {
+		t.Errorf("Position = %d, want 13", syntaxErr.Position)
+	}
+}
+
+func TestService_FuzzyFallback(t *testing.T) {
+	typo := domain.Content{ID: domain.NewUUID(), Title: "Golang Tutorial", Type: domain.ContentTypeText, Score: 3}
+
+	tests := []struct {
+		name      string
+		fuzzy     FuzzyConfig
+		query     string
+		wantItems int
+	}{
+		{"fallback finds typo matches", FuzzyConfig{Enabled: true}, "golang tutorail", 1},
+		{"disabled", FuzzyConfig{}, "golang tutorail", 0},
+		{"structured queries stay exact", FuzzyConfig{Enabled: true}, "golang OR tutorail", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{fuzzy: []domain.Content{typo}, terms: map[string]int{"golang": 2, "tutorial": 1}}
+			service := newTestService(repo, Config{Mode: ModeDatabase, Fuzzy: tt.fuzzy})
+			if err := service.LoadDictionary(context.Background()); err != nil {
+				t.Fatalf("LoadDictionary() error = %v", err)
+			}
+
+			result, err := service.Search(context.Background(), SearchParams{Query: tt.query, Page: 1, PerPage: 20})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(result.Items) != tt.wantItems || result.Total != int64(tt.wantItems) {
+				t.Errorf("Items = %d, Total = %d, want %d", len(result.Items), result.Total, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestService_Suggestion(t *testing.T) {
+	dictionary := fuzzy.NewDictionary(0)
+	repo := NewIndexingRepository(&fakeRepository{}, DictionaryIndexer(dictionary))
+	if err := repo.Upsert(context.Background(), &domain.Content{Title: "Kubernetes in Production", Tags: []string{"devops"}}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	service := newTestService(repo, Config{Mode: ModeDatabase, Dictionary: dictionary})
+
+	result, err := service.Search(context.Background(), SearchParams{Query: "kubernets devosp", Page: 1, PerPage: 20})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Suggestion != "kubernetes devops" {
+		t.Errorf("Suggestion = %q, want %q", result.Suggestion, "kubernetes devops")
+	}
+}
+
+func TestService_Highlights(t *testing.T) {
+	stored := domain.Content{ID: domain.NewUUID(), Title: "Go Tips", Tags: []string{"golang", "tips"}}
+	unmarked := domain.Content{ID: domain.NewUUID(), Title: "Testing in Go", Tags: []string{"testing"}}
+
+	repo := &fakeRepository{
+		contents:  []domain.Content{stored, unmarked},
+		headlines: map[uuid.UUID]string{stored.ID: highlight.MarkStart + "Go" + highlight.MarkStop + " Tips"},
+	}
+	service := newTestService(repo, Config{Mode: ModeDatabase, Highlighter: highlight.Highlighter{PreTag: "<b>", PostTag: "</b>"}})
+
+	result, err := service.Search(context.Background(), SearchParams{Query: "go tag:golang", Page: 1, PerPage: 20})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	want := &domain.Highlights{Title: "<b>Go</b> Tips", Tags: []string{"<b>golang</b>"}}
+	if !reflect.DeepEqual(result.Items[0].Highlights, want) {
+		t.Errorf("Items[0].Highlights = %+v, want %+v", result.Items[0].Highlights, want)
+	}
+
+	// Titles missing from the database headlines fall back to in-memory marking.
+	want = &domain.Highlights{Title: "Testing in <b>Go</b>"}
+	if !reflect.DeepEqual(result.Items[1].Highlights, want) {
+		t.Errorf("Items[1].Highlights = %+v, want %+v", result.Items[1].Highlights, want)
+	}
+}
+
+func TestService_RangeFilters(t *testing.T) {
+	now := time.Now()
+	recent := domain.Content{ID: domain.NewUUID(), Title: "Go video", Provider: "provider1", Type: domain.ContentTypeVideo, PublishedAt: now.Add(-24 * time.Hour), Views: 20000, Score: 1}
+	old := domain.Content{ID: domain.NewUUID(), Title: "Go video", Provider: "provider1", Type: domain.ContentTypeVideo, PublishedAt: now.Add(-30 * 24 * time.Hour), Views: 50000, Score: 2}
+	unpopular := domain.Content{ID: domain.NewUUID(), Title: "Go video", Provider: "provider2", Type: domain.ContentTypeVideo, PublishedAt: now, Views: 10, Score: 3}
+
+	service := newTestService(&fakeRepository{}, Config{})
+	params := SearchParams{Filter: domain.SearchFilter{
+		ContentTypes:  []string{"video"},
+		PublishedFrom: now.Add(-7 * 24 * time.Hour),
+		MinViews:      10000,
+	}}
+
+	got := service.applyFiltersAndSorting([]domain.Content{recent, old, unpopular}, params)
+	if len(got) != 1 || got[0].ID != recent.ID {
+		t.Errorf("applyFiltersAndSorting() = %+v, want only the recent popular video", got)
+	}
+
+	key := service.generateCacheKey(params)
+	params.Filter.MinViews = 20000
+	if service.generateCacheKey(params) == key {
+		t.Error("generateCacheKey() ignores range filters")
+	}
+}
+
+func TestService_CursorPagination(t *testing.T) {
+	var contents []domain.Content
+	for i := 0; i < 5; i++ {
+		contents = append(contents, domain.Content{ID: domain.NewUUID(), Title: "Go", Score: 10})
+	}
+	service := newTestService(&fakeRepository{contents: contents}, Config{Mode: ModeDatabase, Cursor: CursorConfig{Secret: "secret"}})
+
+	search := func(cursor string) *SearchResult {
+		t.Helper()
+		result, err := service.Search(context.Background(), SearchParams{Query: "go", Page: 1, PerPage: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", cursor, err)
+		}
+		return result
+	}
+	ids := func(result *SearchResult) []uuid.UUID {
+		var got []uuid.UUID
+		for _, item := range result.Items {
+			got = append(got, item.ID)
+		}
+		return got
+	}
+
+	first := search("")
+	if first.PrevCursor != "" || first.NextCursor == "" {
+		t.Fatalf("first page cursors = %q / %q", first.PrevCursor, first.NextCursor)
+	}
+
+	second := search(first.NextCursor)
+	if !reflect.DeepEqual(ids(second), []uuid.UUID{contents[2].ID, contents[3].ID}) {
+		t.Errorf("second page = %v", ids(second))
+	}
+
+	last := search(second.NextCursor)
+	if !reflect.DeepEqual(ids(last), []uuid.UUID{contents[4].ID}) || last.NextCursor != "" {
+		t.Errorf("last page = %v, next cursor %q", ids(last), last.NextCursor)
+	}
+
+	back := search(second.PrevCursor)
+	if !reflect.DeepEqual(ids(back), ids(first)) || back.PrevCursor != "" || back.NextCursor == "" {
+		t.Errorf("previous page = %v, cursors %q / %q", ids(back), back.PrevCursor, back.NextCursor)
+	}
+}
+
+func TestService_RejectsInvalidCursors(t *testing.T) {
+	contents := []domain.Content{{ID: domain.NewUUID()}, {ID: domain.NewUUID()}}
+	service := newTestService(&fakeRepository{contents: contents}, Config{Mode: ModeDatabase})
+
+	first, err := service.Search(context.Background(), SearchParams{Query: "go", Page: 1, PerPage: 1})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params SearchParams
+	}{
+		{"tampered", SearchParams{Query: "go", Cursor: first.NextCursor + "x"}},
+		{"other query", SearchParams{Query: "rust", Cursor: first.NextCursor}},
+		{"other page size", SearchParams{Query: "go", PerPage: 2, Cursor: first.NextCursor}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Page = 1
+			if tt.params.PerPage == 0 {
+				tt.params.PerPage = 1
+			}
+			if _, err := service.Search(context.Background(), tt.params); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Search() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+// pagedProvider serves videos with the given view counts, in that order,
+// through upstream pagination.
+type pagedProvider struct {
+	name  string
+	views []int
+}
+
+func (p *pagedProvider) Name() string                          { return p.name }
+func (p *pagedProvider) HealthCheck(ctx context.Context) error { return nil }
+
+func (p *pagedProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{ServerSidePagination: true}
+}
+
+func (p *pagedProvider) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
+	resp, err := p.SearchWithPagination(ctx, query, 1, len(p.views))
+	return resp.Contents, err
+}
+
+func (p *pagedProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*provider.SearchResponse, error) {
+	var contents []domain.ProviderContent
+	for i := (page - 1) * perPage; i < min(page*perPage, len(p.views)); i++ {
+		contents = append(contents, domain.ProviderContent{
+			ExternalID:  fmt.Sprintf("%s-%d", p.name, i),
+			Title:       "Go",
+			Type:        "video",
+			PublishedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Views:       p.views[i],
+		})
+	}
+	return &provider.SearchResponse{
+		Contents:   contents,
+		Pagination: provider.PaginationInfo{CurrentPage: page, PerPage: perPage, Total: len(p.views)},
+	}, nil
+}
+
+func TestService_LivePagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		providers     map[string][]int
+		page, perPage int
+		filter        domain.SearchFilter
+		wantViews     []int
+		wantTotal     int64
+		wantEstimated bool
+	}{
+		{
+			name:      "global page across providers",
+			providers: map[string][]int{"a": {100, 80, 60, 40, 20}, "b": {90, 70, 50, 30, 10}},
+			page:      2, perPage: 2,
+			wantViews: []int{80, 70},
+			wantTotal: 10,
+		},
+		{
+			name:      "page past a provider's results",
+			providers: map[string][]int{"a": {100}, "b": {90, 70, 50, 30, 10}},
+			page:      3, perPage: 2,
+			wantViews: []int{30, 10},
+			wantTotal: 6,
+		},
+		{
+			name:      "filtered results refill and estimate",
+			providers: map[string][]int{"a": {100, 80, 60, 40, 20}, "b": {90, 10, 70, 5, 50}},
+			page:      1, perPage: 2,
+			filter:    domain.SearchFilter{MinViews: 45},
+			wantViews: []int{100, 90},
+			wantTotal: 7, wantEstimated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := provider.NewManager(time.Second)
+			for name, views := range tt.providers {
+				manager.Register(&pagedProvider{name: name, views: views})
+			}
+			service := NewService(&fakeRepository{}, manager, nil, zap.NewNop(), Config{Mode: ModeLive})
+
+			result, err := service.Search(context.Background(), SearchParams{
+				Query: "go", Filter: tt.filter, SortBy: "popularity", Page: tt.page, PerPage: tt.perPage,
+			})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			var views []int
+			for _, item := range result.Items {
+				views = append(views, item.Views)
+			}
+			if !reflect.DeepEqual(views, tt.wantViews) {
+				t.Errorf("views = %v, want %v", views, tt.wantViews)
+			}
+			if result.Total != tt.wantTotal || result.TotalEstimated != tt.wantEstimated {
+				t.Errorf("total = %d (estimated %v), want %d (estimated %v)", result.Total, result.TotalEstimated, tt.wantTotal, tt.wantEstimated)
+			}
+		})
+	}
+}
+
+type fakeClusters map[uuid.UUID][]domain.Alternate
+
+func (f fakeClusters) Assign(ctx context.Context, content *domain.Content, key dedup.Key, window time.Duration) error {
+	return nil
+}
+
+func (f fakeClusters) Alternates(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID][]domain.Alternate, error) {
+	return f, nil
+}
+
+func TestService_Dedup(t *testing.T) {
+	off := false
+	manager := provider.NewManager(time.Second)
+	manager.Register(&pagedProvider{name: "a", views: []int{100}})
+	manager.Register(&pagedProvider{name: "b", views: []int{90}})
+
+	stored := domain.Content{ID: domain.NewUUID(), Title: "Go"}
+	clusters := fakeClusters{stored.ID: {{Provider: "b", ExternalID: "x"}}}
+
+	tests := []struct {
+		name           string
+		mode           Mode
+		dedup          *bool
+		wantItems      int
+		wantAlternates []domain.Alternate
+	}{
+		{"live duplicates collapse", ModeLive, nil, 1, []domain.Alternate{{Provider: "b", ExternalID: "b-0"}}},
+		{"live switched off per request", ModeLive, &off, 2, nil},
+		{"database alternates", ModeDatabase, nil, 1, []domain.Alternate{{Provider: "b", ExternalID: "x"}}},
+		{"database switched off per request", ModeDatabase, &off, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{contents: []domain.Content{stored}}
+			service := NewService(repo, manager, nil, zap.NewNop(), Config{
+				Mode:  tt.mode,
+				Dedup: DedupConfig{Enabled: true, Clusters: clusters},
+			})
+
+			result, err := service.Search(context.Background(), SearchParams{
+				Query: "go", SortBy: "popularity", Page: 1, PerPage: 10, Dedup: tt.dedup,
+			})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+
+			if len(result.Items) != tt.wantItems || result.Total != int64(tt.wantItems) {
+				t.Fatalf("items = %d, total = %d, want %d", len(result.Items), result.Total, tt.wantItems)
+			}
+			if got := result.Items[0].Alternates; !reflect.DeepEqual(got, tt.wantAlternates) {
+				t.Errorf("Alternates = %v, want %v", got, tt.wantAlternates)
+			}
+		})
+	}
+}
+
+// slowProvider counts its searches and holds each one for delay, so
+// concurrent callers overlap.
+type slowProvider struct {
+	pagedProvider
+	delay time.Duration
+	calls atomic.Int64
+}
+
+func (p *slowProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*provider.SearchResponse, error) {
+	p.calls.Add(1)
+	time.Sleep(p.delay)
+	return p.pagedProvider.SearchWithPagination(ctx, query, page, perPage)
+}
+
+func TestService_CoalescesConcurrentSearches(t *testing.T) {
+	const callers = 5
+
+	slow := &slowProvider{pagedProvider: pagedProvider{name: "a", views: []int{100, 90}}, delay: 200 * time.Millisecond}
+	manager := provider.NewManager(time.Second)
+	manager.Register(slow)
+	service := NewService(&fakeRepository{}, manager, nil, zap.NewNop(), Config{Mode: ModeLive})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.Search(context.Background(), SearchParams{Query: "go", SortBy: "popularity", Page: 1, PerPage: 10})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+	if calls := slow.calls.Load(); calls != 1 {
+		t.Errorf("provider calls = %d, want 1", calls)
+	}
+	stats := service.CacheStats()
+	if stats.Misses != callers || stats.Coalesced != callers-1 {
+		t.Errorf("CacheStats() = %+v, want %d misses and %d coalesced", stats, callers, callers-1)
+	}
+}
+
+func TestService_ServesFromMemoryCache(t *testing.T) {
+	counting := &slowProvider{pagedProvider: pagedProvider{name: "a", views: []int{100, 90}}}
+	manager := provider.NewManager(time.Second)
+	manager.Register(counting)
+	service := NewService(&fakeRepository{}, manager, NewMemoryCache(10), zap.NewNop(), Config{Mode: ModeLive})
+
+	params := SearchParams{Query: "go", SortBy: "popularity", Page: 1, PerPage: 10}
+	first, err := service.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	second, err := service.Search(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if calls := counting.calls.Load(); calls != 1 {
+		t.Errorf("provider calls = %d, want 1", calls)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached result = %+v, want %+v", second, first)
+	}
+	if stats := service.CacheStats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("CacheStats() = %+v, want 1 hit and 1 miss", stats)
+	}
+}
+
+func TestService_PurgeCache(t *testing.T) {
+	manager := provider.NewManager(time.Second)
+	manager.Register(&pagedProvider{name: "a", views: []int{100}})
+	manager.Register(&pagedProvider{name: "b", views: []int{90}})
+	service := NewService(&fakeRepository{}, manager, NewMemoryCache(10), zap.NewNop(), Config{Mode: ModeLive})
+
+	searches := []SearchParams{
+		{Query: "go", SortBy: "popularity", Page: 1, PerPage: 10},
+		{Query: "go", Filter: domain.SearchFilter{Providers: []string{"b"}}, SortBy: "popularity", Page: 1, PerPage: 10},
+		{Query: "go", Filter: domain.SearchFilter{ContentTypes: []string{"video"}}, SortBy: "popularity", Page: 1, PerPage: 10},
+	}
+	for _, params := range searches {
+		if _, err := service.Search(context.Background(), params); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		purge CachePurge
+		want  int
+	}{
+		{"provider keeps searches restricted to others", CachePurge{Provider: "a"}, 2},
+		{"query is normalized", CachePurge{Query: "GO!"}, 1},
+		{"nothing left", CachePurge{Tag: "type:video"}, 0},
+	}
+	for _, tt := range tests {
+		purged, err := service.PurgeCache(context.Background(), tt.purge)
+		if err != nil || purged != tt.want {
+			t.Errorf("%s: PurgeCache() = %d, %v, want %d", tt.name, purged, err, tt.want)
+		}
+	}
+}
+
+func TestService_InvalidateProviderFilterKeepsUnfilteredSearches(t *testing.T) {
+	manager := provider.NewManager(time.Second)
+	manager.Register(&pagedProvider{name: "a", views: []int{100}})
+	service := NewService(&fakeRepository{}, manager, NewMemoryCache(10), zap.NewNop(), Config{Mode: ModeLive})
+
+	searches := []SearchParams{
+		{Query: "go", SortBy: "popularity", Page: 1, PerPage: 10},
+		{Query: "go", Filter: domain.SearchFilter{Providers: []string{"a"}}, SortBy: "popularity", Page: 1, PerPage: 10},
+	}
+	for _, params := range searches {
+		if _, err := service.Search(context.Background(), params); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	if err := service.InvalidateProviderFilter(context.Background(), "a"); err != nil {
+		t.Fatalf("InvalidateProviderFilter() error = %v", err)
+	}
+	if purged, _ := service.PurgeCache(context.Background(), CachePurge{Tag: "provider:*"}); purged != 1 {
+		t.Errorf("unfiltered searches left = %d, want 1", purged)
+	}
+}
+
+func TestService_WarmPopularSearches(t *testing.T) {
+	counting := &slowProvider{pagedProvider: pagedProvider{name: "a", views: []int{100, 90}}}
+	manager := provider.NewManager(time.Second)
+	manager.Register(counting)
+	service := NewService(&fakeRepository{}, manager, NewMemoryCache(10), zap.NewNop(), Config{
+		Mode:     ModeLive,
+		QueryLog: NewMemoryQueryLog(time.Hour, 100),
+	})
+
+	searches := []SearchParams{
+		{Query: "go", SortBy: "popularity", Page: 1, PerPage: 1},
+		{Query: "go", SortBy: "popularity", Page: 1, PerPage: 1},
+		{Query: "go", SortBy: "popularity", Page: 2, PerPage: 1},
+		{Query: "rust", SortBy: "popularity", Page: 1, PerPage: 1},
+	}
+	for _, params := range searches {
+		if _, err := service.Search(context.Background(), params); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	popular, err := service.PopularSearches(context.Background(), 1)
+	if err != nil || len(popular) != 1 || popular[0].Query != "go" || popular[0].Page != 1 {
+		t.Fatalf("PopularSearches() = %+v, %v, want the first page of go", popular, err)
+	}
+
+	calls := counting.calls.Load()
+	tests := []struct {
+		name    string
+		horizon time.Duration
+		want    bool
+	}{
+		{"fresh entry is left alone", time.Minute, false},
+		{"entry expiring within the horizon is refreshed", time.Hour, true},
+	}
+	for _, tt := range tests {
+		warmed, err := service.Warm(context.Background(), popular[0], tt.horizon)
+		if err != nil || warmed != tt.want {
+			t.Errorf("%s: Warm() = %v, %v, want %v", tt.name, warmed, err, tt.want)
+		}
+	}
+	if got := counting.calls.Load() - calls; got != 1 {
+		t.Errorf("provider calls while warming = %d, want 1", got)
+	}
+
+	if _, err := service.Search(context.Background(), searches[0]); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if stats := service.CacheStats(); stats.Hits != 2 {
+		t.Errorf("CacheStats() = %+v, want the warmed page served from the cache", stats)
+	}
+}
+
+func TestMemoryQueryLog_KeepsMostFrequentSearches(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryQueryLog(time.Hour, 10)
+
+	for i := 0; i < 3; i++ {
+		log.Record(ctx, "popular")
+	}
+	for i := 0; i < 20; i++ {
+		log.Record(ctx, fmt.Sprintf("rare %02d", i))
+	}
+
+	if len(log.current) > 11 {
+		t.Errorf("searches kept = %d, want at most 11", len(log.current))
+	}
+	if top, _ := log.Top(ctx, 1); len(top) != 1 || top[0] != "popular" {
+		t.Errorf("Top(1) = %v, want [popular]", top)
+	}
+}
+
+func TestService_PopularSearchesKeepDedup(t *testing.T) {
+	manager := provider.NewManager(time.Second)
+	manager.Register(&pagedProvider{name: "a", views: []int{100, 90}})
+	service := NewService(&fakeRepository{}, manager, NewMemoryCache(10), zap.NewNop(), Config{
+		Mode:     ModeLive,
+		QueryLog: NewMemoryQueryLog(time.Hour, 100),
+		Dedup:    DedupConfig{Enabled: true},
+	})
+
+	dedup := false
+	search := SearchParams{Query: "go", SortBy: "popularity", Page: 1, PerPage: 1, Dedup: &dedup}
+	if _, err := service.Search(context.Background(), search); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	popular, err := service.PopularSearches(context.Background(), 1)
+	if err != nil || len(popular) != 1 || popular[0].Dedup == nil || *popular[0].Dedup {
+		t.Fatalf("PopularSearches() = %+v, %v, want dedup disabled", popular, err)
+	}
+	if params := popular[0].params(); params.Filter.Dedup || params.Dedup == nil || *params.Dedup {
+		t.Errorf("params() = %+v, want dedup disabled", params)
+	}
+	if warmed, err := service.Warm(context.Background(), popular[0], 0); err != nil || warmed {
+		t.Errorf("Warm() = %v, %v, want the cached page without dedup left alone", warmed, err)
+	}
+}
@@ -0,0 +1,61 @@
+package suggest
+
+import (
+	"search-engine/domain"
+	"search-engine/pkg/apierror"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const maxQueryLength = 100
+
+type Handler struct {
+	service *Service
+	logger  *zap.Logger
+}
+
+func NewHandler(service *Service, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Suggest(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	q := c.Query("q")
+	if len(q) > maxQueryLength {
+		return h.errorResponse(c, apierror.NewValidationError("q must be at most 100 characters"), requestID)
+	}
+
+	limit := c.QueryInt("limit")
+	if limit < 0 {
+		return h.errorResponse(c, apierror.NewValidationError("limit must be positive"), requestID)
+	}
+
+	suggestions, err := h.service.Suggest(c.Context(), q, limit)
+	if err != nil {
+		h.logger.Error("suggest failed",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+		return h.errorResponse(c, apierror.ErrInternalServer, requestID)
+	}
+
+	return c.JSON(domain.NewSuccessResponse(
+		fiber.Map{"suggestions": suggestions},
+		&domain.Meta{RequestID: requestID},
+	))
+}
+
+func (h *Handler) errorResponse(c *fiber.Ctx, apiErr *apierror.APIError, requestID string) error {
+	response := domain.NewErrorResponse(apiErr.Code, apiErr.Message, requestID)
+	return c.Status(apiErr.StatusCode).JSON(response)
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App) {
+	v1 := app.Group("/api/v1")
+	v1.Get("/suggest", h.Suggest)
+}
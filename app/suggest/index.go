@@ -0,0 +1,201 @@
+package suggest
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"search-engine/domain"
+	"search-engine/domain/fuzzy"
+)
+
+// Index is a prefix index of title and tag completions. Adding the same
+// suggestion again keeps the highest popularity seen, so re-indexing a
+// content is idempotent.
+type Index interface {
+	Add(ctx context.Context, suggestions []domain.Suggestion) error
+	Complete(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+}
+
+// Normalize lowercases text and collapses everything that is not a letter or
+// digit into single spaces, so "Go: Tips" and "go tips" share a key.
+func Normalize(text string) string {
+	return strings.Join(fuzzy.Tokenize(text), " ")
+}
+
+// Keys returns the prefix keys a suggestion is indexed under: its normalized
+// text from every word start, so "go" completes "Advanced Go Patterns" too.
+func Keys(text string) []string {
+	words := fuzzy.Tokenize(text)
+	keys := make([]string, len(words))
+	for i := range words {
+		keys[i] = strings.Join(words[i:], " ")
+	}
+	return keys
+}
+
+// ID identifies a suggestion regardless of the casing of its text.
+func ID(s domain.Suggestion) string {
+	return string(s.Type) + "\x00" + Normalize(s.Text)
+}
+
+// Rank orders suggestions by popularity, then by shorter and alphabetically
+// smaller text, and keeps the first limit.
+func Rank(suggestions []domain.Suggestion, limit int) []domain.Suggestion {
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if len(a.Text) != len(b.Text) {
+			return len(a.Text) < len(b.Text)
+		}
+		return a.Text < b.Text
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// MemoryIndex keeps the prefix keys in a radix tree, so adding a key costs
+// its length and a completion only visits the keys under the prefix.
+type MemoryIndex struct {
+	mu      sync.RWMutex
+	root    radixNode
+	entries map[string]*domain.Suggestion
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{entries: make(map[string]*domain.Suggestion)}
+}
+
+func (m *MemoryIndex) Add(ctx context.Context, suggestions []domain.Suggestion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range suggestions {
+		id := ID(s)
+		if existing, ok := m.entries[id]; ok {
+			existing.Text = s.Text
+			if s.Popularity > existing.Popularity {
+				existing.Popularity = s.Popularity
+			}
+			continue
+		}
+
+		entry := s
+		m.entries[id] = &entry
+		for _, key := range Keys(s.Text) {
+			m.root.insert(key, id)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Complete(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	prefix = Normalize(prefix)
+	if prefix == "" {
+		return []domain.Suggestion{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	matches := []domain.Suggestion{}
+	m.root.find(prefix).walk(func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			matches = append(matches, *m.entries[id])
+		}
+	})
+
+	return Rank(matches, limit), nil
+}
+
+// radixNode is reached by its label from its parent and holds the ids of the
+// suggestions whose key ends at it.
+type radixNode struct {
+	label    string
+	children map[byte]*radixNode
+	ids      []string
+}
+
+func (n *radixNode) insert(key, id string) {
+	for key != "" {
+		child, ok := n.children[key[0]]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[byte]*radixNode)
+			}
+			n.children[key[0]] = &radixNode{label: key, ids: []string{id}}
+			return
+		}
+
+		common := commonPrefixLen(child.label, key)
+		if common < len(child.label) {
+			split := &radixNode{
+				label:    child.label[:common],
+				children: map[byte]*radixNode{child.label[common]: child},
+			}
+			child.label = child.label[common:]
+			n.children[key[0]] = split
+			child = split
+		}
+		n, key = child, key[common:]
+	}
+
+	if !slices.Contains(n.ids, id) {
+		n.ids = append(n.ids, id)
+	}
+}
+
+// find returns the node holding every key that starts with prefix, or nil.
+func (n *radixNode) find(prefix string) *radixNode {
+	for prefix != "" {
+		child, ok := n.children[prefix[0]]
+		switch {
+		case !ok:
+			return nil
+		case strings.HasPrefix(prefix, child.label):
+			n, prefix = child, prefix[len(child.label):]
+		case strings.HasPrefix(child.label, prefix):
+			return child
+		default:
+			return nil
+		}
+	}
+	return n
+}
+
+func (n *radixNode) walk(fn func(id string)) {
+	if n == nil {
+		return
+	}
+	for _, id := range n.ids {
+		fn(id)
+	}
+	for _, child := range n.children {
+		child.walk(fn)
+	}
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+func (m *MemoryIndex) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
@@ -0,0 +1,130 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"search-engine/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Repository interface {
+	ListForSuggest(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error)
+}
+
+type Config struct {
+	DefaultLimit int
+	MaxLimit     int
+	BatchSize    int
+}
+
+type Service struct {
+	index  Index
+	repo   Repository
+	config Config
+	logger *zap.Logger
+}
+
+func NewService(index Index, repo Repository, config Config, logger *zap.Logger) *Service {
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = 10
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = 50
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+
+	return &Service{
+		index:  index,
+		repo:   repo,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Suggestions returns the title and tag entries a content contributes.
+// Popularity is the same views + likes + reactions sum used for sorting by
+// popularity.
+func Suggestions(content domain.Content) []domain.Suggestion {
+	popularity := int64(content.Views + content.Likes + content.Reactions)
+
+	suggestions := make([]domain.Suggestion, 0, len(content.Tags)+1)
+	if Normalize(content.Title) != "" {
+		suggestions = append(suggestions, domain.Suggestion{Text: content.Title, Type: domain.SuggestionTitle, Popularity: popularity})
+	}
+	for _, tag := range content.Tags {
+		if Normalize(tag) != "" {
+			suggestions = append(suggestions, domain.Suggestion{Text: tag, Type: domain.SuggestionTag, Popularity: popularity})
+		}
+	}
+	return suggestions
+}
+
+// Build indexes every stored content, reading the table in id order.
+func (s *Service) Build(ctx context.Context) (int, error) {
+	start := time.Now()
+	indexed := 0
+	afterID := uuid.Nil
+
+	for {
+		contents, err := s.repo.ListForSuggest(ctx, afterID, s.config.BatchSize)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to list contents for suggest: %w", err)
+		}
+		if len(contents) == 0 {
+			break
+		}
+
+		var suggestions []domain.Suggestion
+		for _, content := range contents {
+			suggestions = append(suggestions, Suggestions(content)...)
+		}
+		if err := s.index.Add(ctx, suggestions); err != nil {
+			return indexed, fmt.Errorf("failed to index suggestions: %w", err)
+		}
+
+		indexed += len(contents)
+		afterID = contents[len(contents)-1].ID
+		if len(contents) < s.config.BatchSize {
+			break
+		}
+	}
+
+	s.logger.Info("suggest index built",
+		zap.Int("contents", indexed),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return indexed, nil
+}
+
+// IndexContents keeps the index current as contents are upserted. It is best
+// effort: a failure only delays suggestions until the next build.
+func (s *Service) IndexContents(ctx context.Context, contents []*domain.Content) {
+	var suggestions []domain.Suggestion
+	for _, content := range contents {
+		suggestions = append(suggestions, Suggestions(*content)...)
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+
+	if err := s.index.Add(ctx, suggestions); err != nil {
+		s.logger.Warn("failed to index suggestions", zap.Error(err))
+	}
+}
+
+func (s *Service) Suggest(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	if limit <= 0 {
+		limit = s.config.DefaultLimit
+	}
+	if limit > s.config.MaxLimit {
+		limit = s.config.MaxLimit
+	}
+
+	return s.index.Complete(ctx, prefix, limit)
+}
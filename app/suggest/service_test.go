@@ -0,0 +1,106 @@
+package suggest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"search-engine/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	contents []domain.Content
+}
+
+func (r *fakeRepository) ListForSuggest(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error) {
+	var batch []domain.Content
+	for _, content := range r.contents {
+		if content.ID.String() > afterID.String() && len(batch) < limit {
+			batch = append(batch, content)
+		}
+	}
+	return batch, nil
+}
+
+func texts(suggestions []domain.Suggestion) []string {
+	result := []string{}
+	for _, s := range suggestions {
+		result = append(result, string(s.Type)+":"+s.Text)
+	}
+	return result
+}
+
+func TestMemoryIndex_Complete(t *testing.T) {
+	index := NewMemoryIndex()
+	err := index.Add(context.Background(), []domain.Suggestion{
+		{Text: "Go Concurrency Patterns", Type: domain.SuggestionTitle, Popularity: 500},
+		{Text: "Advanced Go", Type: domain.SuggestionTitle, Popularity: 900},
+		{Text: "golang", Type: domain.SuggestionTag, Popularity: 100},
+		{Text: "Rust Basics", Type: domain.SuggestionTitle, Popularity: 1000},
+		{Text: "Golang", Type: domain.SuggestionTag, Popularity: 50},
+	})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"go", 10, []string{"title:Advanced Go", "title:Go Concurrency Patterns", "tag:Golang"}},
+		{"GO C", 10, []string{"title:Go Concurrency Patterns"}},
+		{"go", 2, []string{"title:Advanced Go", "title:Go Concurrency Patterns"}},
+		{"patterns", 10, []string{"title:Go Concurrency Patterns"}},
+		{"gol", 10, []string{"tag:Golang"}},
+		{"go concurrency patterns", 10, []string{"title:Go Concurrency Patterns"}},
+		{"go concurrency patternss", 10, []string{}},
+		{"python", 10, []string{}},
+		{"  ", 10, []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := index.Complete(context.Background(), tt.prefix, tt.limit)
+		if err != nil {
+			t.Fatalf("Complete(%q) error = %v", tt.prefix, err)
+		}
+		if !reflect.DeepEqual(texts(got), tt.want) {
+			t.Errorf("Complete(%q) = %v, want %v", tt.prefix, texts(got), tt.want)
+		}
+	}
+
+	if index.Len() != 4 {
+		t.Errorf("Len() = %d, want 4 (tags differing only in case share an entry)", index.Len())
+	}
+}
+
+func TestService_BuildAndIndex(t *testing.T) {
+	repo := &fakeRepository{contents: []domain.Content{
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Title: "Docker Basics", Views: 100, Tags: []string{"devops"}},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Title: "Docker Compose", Views: 300},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), Title: "Kubernetes", Views: 50, Tags: []string{"devops", "docker"}},
+	}}
+	service := NewService(NewMemoryIndex(), repo, Config{BatchSize: 2}, zap.NewNop())
+
+	indexed, err := service.Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if indexed != 3 {
+		t.Errorf("Build() indexed = %d, want 3", indexed)
+	}
+
+	service.IndexContents(context.Background(), []*domain.Content{{Title: "Docker Swarm", Likes: 200}})
+
+	got, err := service.Suggest(context.Background(), "dock", 0)
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+	want := []string{"title:Docker Compose", "title:Docker Swarm", "title:Docker Basics", "tag:docker"}
+	if !reflect.DeepEqual(texts(got), want) {
+		t.Errorf("Suggest() = %v, want %v", texts(got), want)
+	}
+}
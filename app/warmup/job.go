@@ -0,0 +1,257 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"search-engine/app/search"
+
+	"go.uber.org/zap"
+)
+
+type Searcher interface {
+	PopularSearches(ctx context.Context, n int) ([]search.PopularSearch, error)
+	Warm(ctx context.Context, popular search.PopularSearch, horizon time.Duration) (bool, error)
+}
+
+// Breakers reports how many more provider failures the circuit breakers
+// absorb before one opens, or a negative number when there are none.
+type Breakers interface {
+	CircuitHeadroom() int
+}
+
+type Config struct {
+	Interval    time.Duration
+	TopN        int
+	Concurrency int
+	Breakers    Breakers
+}
+
+type RunStats struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Candidates int       `json:"candidates"`
+	Warmed     int       `json:"warmed"`
+	Fresh      int       `json:"fresh"`
+	Failed     int       `json:"failed"`
+	Skipped    int       `json:"skipped"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type Status struct {
+	Interval string    `json:"interval"`
+	Running  bool      `json:"running"`
+	LastRun  *RunStats `json:"last_run,omitempty"`
+}
+
+var ErrRunInProgress = errors.New("warmup run already in progress")
+
+// Job re-executes the most frequent searches before their cached results go
+// stale, once at start and then every interval.
+type Job struct {
+	searcher Searcher
+	config   Config
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+	lastRun *RunStats
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewJob(searcher Searcher, config Config, logger *zap.Logger) *Job {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.TopN <= 0 {
+		config.TopN = 50
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+
+	return &Job{
+		searcher: searcher,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+func (j *Job) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	j.mu.Lock()
+	j.cancel = cancel
+	j.mu.Unlock()
+
+	j.wg.Add(1)
+	go j.loop(ctx)
+
+	j.logger.Info("warmup job started",
+		zap.Duration("interval", j.config.Interval),
+		zap.Int("top_n", j.config.TopN),
+	)
+}
+
+func (j *Job) Stop() {
+	j.mu.Lock()
+	cancel := j.cancel
+	j.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	j.wg.Wait()
+
+	j.logger.Info("warmup job stopped")
+}
+
+// loop warms right away, so a fresh deploy serves popular searches from the
+// cache, and then on every tick.
+func (j *Job) loop(ctx context.Context) {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunNow(ctx); err != nil && !errors.Is(err, ErrRunInProgress) {
+			j.logger.Warn("warmup run failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Job) RunNow(ctx context.Context) (*RunStats, error) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return nil, ErrRunInProgress
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	stats := j.run(ctx)
+
+	j.mu.Lock()
+	j.running = false
+	j.lastRun = &stats
+	j.mu.Unlock()
+
+	if stats.Error != "" {
+		return &stats, errors.New(stats.Error)
+	}
+	return &stats, nil
+}
+
+func (j *Job) run(ctx context.Context) RunStats {
+	stats := RunStats{StartedAt: time.Now()}
+	defer func() {
+		stats.FinishedAt = time.Now()
+		duration := stats.FinishedAt.Sub(stats.StartedAt)
+		stats.DurationMs = duration.Milliseconds()
+
+		j.logger.Info("warmup run finished",
+			zap.Int("candidates", stats.Candidates),
+			zap.Int("warmed", stats.Warmed),
+			zap.Int("fresh", stats.Fresh),
+			zap.Int("failed", stats.Failed),
+			zap.Int("skipped", stats.Skipped),
+			zap.Duration("duration", duration),
+			zap.String("error", stats.Error),
+		)
+	}()
+
+	popular, err := j.searcher.PopularSearches(ctx, j.config.TopN)
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	stats.Candidates = len(popular)
+
+	// Entries that would go stale before the next run, with half an interval
+	// to spare for the run itself, are refreshed now.
+	horizon := j.config.Interval + j.config.Interval/2
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		inFlight atomic.Int64
+		slots    = make(chan struct{}, j.config.Concurrency)
+	)
+	for i, p := range popular {
+		slots <- struct{}{}
+		if ctx.Err() != nil || !j.canWarm(int(inFlight.Load())) {
+			<-slots
+			mu.Lock()
+			stats.Skipped = len(popular) - i
+			mu.Unlock()
+			if ctx.Err() == nil {
+				j.logger.Warn("warmup stopped to spare provider circuit breakers")
+			}
+			break
+		}
+
+		inFlight.Add(1)
+		wg.Add(1)
+		go func(p search.PopularSearch) {
+			defer func() {
+				inFlight.Add(-1)
+				<-slots
+				wg.Done()
+			}()
+
+			warmed, err := j.searcher.Warm(ctx, p, horizon)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				stats.Failed++
+				j.logger.Warn("warmup search failed", zap.String("query", p.Query), zap.Error(err))
+			case warmed:
+				stats.Warmed++
+			default:
+				stats.Fresh++
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	return stats
+}
+
+// canWarm starts another search only if every circuit breaker would stay
+// closed even if it and all searches in flight failed.
+func (j *Job) canWarm(inFlight int) bool {
+	if j.config.Breakers == nil {
+		return true
+	}
+	headroom := j.config.Breakers.CircuitHeadroom()
+	return headroom < 0 || headroom > inFlight+1
+}
+
+func (j *Job) Status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := Status{
+		Interval: j.config.Interval.String(),
+		Running:  j.running,
+	}
+	if j.lastRun != nil {
+		last := *j.lastRun
+		status.LastRun = &last
+	}
+	return status
+}
@@ -0,0 +1,131 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"search-engine/app/search"
+
+	"go.uber.org/zap"
+)
+
+// fakeSearcher warms every search except the fresh ones. Failing warms use up
+// the breaker headroom, as failed provider calls would.
+type fakeSearcher struct {
+	popular  []search.PopularSearch
+	fresh    map[string]bool
+	fail     bool
+	breakers *fakeBreakers
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (s *fakeSearcher) PopularSearches(ctx context.Context, n int) ([]search.PopularSearch, error) {
+	return s.popular[:min(n, len(s.popular))], nil
+}
+
+func (s *fakeSearcher) Warm(ctx context.Context, popular search.PopularSearch, horizon time.Duration) (bool, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+
+	if s.fail {
+		s.breakers.headroom--
+		return false, errors.New("provider down")
+	}
+	return !s.fresh[popular.Query], nil
+}
+
+type fakeBreakers struct {
+	headroom int
+}
+
+func (b *fakeBreakers) CircuitHeadroom() int {
+	return b.headroom
+}
+
+func popularSearches(n int) []search.PopularSearch {
+	popular := make([]search.PopularSearch, n)
+	for i := range popular {
+		popular[i] = search.PopularSearch{Query: fmt.Sprintf("q%d", i), Page: 1, PerPage: 10}
+	}
+	return popular
+}
+
+func TestJob_RunNow(t *testing.T) {
+	tests := []struct {
+		name        string
+		searcher    *fakeSearcher
+		headroom    int
+		concurrency int
+		topN        int
+		want        RunStats
+	}{
+		{
+			name:        "warms stale searches",
+			searcher:    &fakeSearcher{popular: popularSearches(4), fresh: map[string]bool{"q2": true}},
+			headroom:    -1,
+			concurrency: 2,
+			topN:        10,
+			want:        RunStats{Candidates: 4, Warmed: 3, Fresh: 1},
+		},
+		{
+			name:        "only top n",
+			searcher:    &fakeSearcher{popular: popularSearches(6)},
+			headroom:    -1,
+			concurrency: 3,
+			topN:        5,
+			want:        RunStats{Candidates: 5, Warmed: 5},
+		},
+		{
+			name:        "stops before a breaker opens",
+			searcher:    &fakeSearcher{popular: popularSearches(5), fail: true},
+			headroom:    3,
+			concurrency: 1,
+			topN:        10,
+			want:        RunStats{Candidates: 5, Failed: 2, Skipped: 3},
+		},
+		{
+			name:        "open breaker",
+			searcher:    &fakeSearcher{popular: popularSearches(3)},
+			headroom:    0,
+			concurrency: 2,
+			topN:        10,
+			want:        RunStats{Candidates: 3, Skipped: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakers := &fakeBreakers{headroom: tt.headroom}
+			tt.searcher.breakers = breakers
+			job := NewJob(tt.searcher, Config{TopN: tt.topN, Concurrency: tt.concurrency, Breakers: breakers}, zap.NewNop())
+
+			stats, err := job.RunNow(context.Background())
+			if err != nil {
+				t.Fatalf("RunNow() error = %v", err)
+			}
+
+			got := RunStats{Candidates: stats.Candidates, Warmed: stats.Warmed, Fresh: stats.Fresh, Failed: stats.Failed, Skipped: stats.Skipped}
+			if got != tt.want {
+				t.Errorf("stats = %+v, want %+v", got, tt.want)
+			}
+			if tt.searcher.peak > tt.concurrency {
+				t.Errorf("%d searches ran at once, want at most %d", tt.searcher.peak, tt.concurrency)
+			}
+		})
+	}
+}
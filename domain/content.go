@@ -1,11 +1,17 @@
 package domain
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrContentNotFound = errors.New("content not found")
+
 type ContentType string
 
 const (
@@ -32,21 +38,116 @@ type Content struct {
 	ReadingTime int      `json:"reading_time,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
 
-	Score float64 `json:"score"`
+	Score      float64        `json:"score"`
+	Breakdown  ScoreBreakdown `json:"-"`
+	Highlights *Highlights    `json:"highlights,omitempty"`
+	Alternates []Alternate    `json:"alternates,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// contentNamespace seeds ContentID so the same provider item gets the same
+// ID whether it is served live or read back from the database.
+var contentNamespace = uuid.MustParse("8d95b0a6-e7e4-4220-bd19-0ea73ab821dd")
+
 func NewUUID() uuid.UUID {
 	return uuid.New()
 }
 
+func ContentID(provider, externalID string) uuid.UUID {
+	return uuid.NewSHA1(contentNamespace, []byte(provider+"/"+externalID))
+}
+
+// NormalizeTags lowercases and trims tags and drops empty and repeated ones.
+// Contents and filters both carry normalized tags, so they compare exactly,
+// as the database does.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func NewContentFromProvider(pc ProviderContent, provider string, breakdown ScoreBreakdown) Content {
+	now := time.Now()
+
+	return Content{
+		ID:          ContentID(provider, pc.ExternalID),
+		ExternalID:  pc.ExternalID,
+		Provider:    provider,
+		Title:       pc.Title,
+		Type:        ContentType(pc.Type),
+		PublishedAt: pc.PublishedAt,
+		Views:       pc.Views,
+		Likes:       pc.Likes,
+		Reactions:   pc.Reactions,
+		ReadingTime: pc.ReadingTime,
+		Score:       breakdown.Total,
+		Breakdown:   breakdown,
+		Tags:        NormalizeTags(pc.Tags),
+		RawData:     pc.RawData,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (c Content) ToProviderContent() ProviderContent {
+	return ProviderContent{
+		ExternalID:  c.ExternalID,
+		Title:       c.Title,
+		Type:        string(c.Type),
+		PublishedAt: c.PublishedAt,
+		Views:       c.Views,
+		Likes:       c.Likes,
+		ReadingTime: c.ReadingTime,
+		Reactions:   c.Reactions,
+		Tags:        c.Tags,
+		RawData:     c.RawData,
+	}
+}
+
+// Highlights carries the title with matched words wrapped in the configured
+// tags and the tags that matched the query.
+type Highlights struct {
+	Title string   `json:"title,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Alternate is a duplicate of a result published by another provider, or
+// under another external ID.
+type Alternate struct {
+	Provider   string `json:"provider"`
+	ExternalID string `json:"external_id"`
+}
+
 type ScoreBreakdown struct {
 	BaseScore       float64 `json:"base_score"`
 	TypeMultiplier  float64 `json:"type_multiplier"`
 	FreshnessScore  float64 `json:"freshness_score"`
 	EngagementScore float64 `json:"engagement_score"`
+	ClickBoost      float64 `json:"click_boost,omitempty"`
+	Total           float64 `json:"total"`
+}
+
+// Explains reports whether the components add up to score, within the
+// rounding of each. Contents whose components were never stored, like rows
+// written before they were persisted, have a breakdown that does not.
+func (b ScoreBreakdown) Explains(score float64) bool {
+	if b == (ScoreBreakdown{}) {
+		return false
+	}
+	tolerance := 0.01 * (math.Abs(b.TypeMultiplier) + 3)
+	sum := b.BaseScore*b.TypeMultiplier + b.FreshnessScore + b.EngagementScore + b.ClickBoost
+	return math.Abs(sum-score) <= tolerance && math.Abs(b.Total-score) <= tolerance
 }
 
 type ContentWithScore struct {
@@ -55,6 +156,19 @@ type ContentWithScore struct {
 	Metadata       ContentMetadata `json:"metadata"`
 }
 
+func NewContentWithScore(c Content) ContentWithScore {
+	return ContentWithScore{
+		Content:        c,
+		ScoreBreakdown: c.Breakdown,
+		Metadata: ContentMetadata{
+			Views:       c.Views,
+			Likes:       c.Likes,
+			Reactions:   c.Reactions,
+			ReadingTime: c.ReadingTime,
+		},
+	}
+}
+
 type ContentMetadata struct {
 	Views       int `json:"views,omitempty"`
 	Likes       int `json:"likes,omitempty"`
@@ -62,6 +176,11 @@ type ContentMetadata struct {
 	ReadingTime int `json:"reading_time,omitempty"`
 }
 
+type ContentDetail struct {
+	ContentWithScore
+	RawData json.RawMessage `json:"raw_data,omitempty"`
+}
+
 type ProviderContent struct {
 	ExternalID  string    `validate:"required,min=1"`
 	Title       string    `validate:"required,min=1,max=500"`
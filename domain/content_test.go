@@ -26,6 +26,31 @@ func TestContentType_IsValid(t *testing.T) {
 	}
 }
 
+func TestScoreBreakdown_Explains(t *testing.T) {
+	tests := []struct {
+		name      string
+		breakdown ScoreBreakdown
+		score     float64
+		want      bool
+	}{
+		{"stored components", ScoreBreakdown{BaseScore: 10, TypeMultiplier: 1.5, FreshnessScore: 2, EngagementScore: 0.5, Total: 17.5}, 17.5, true},
+		{"rounded components", ScoreBreakdown{BaseScore: 3.33, TypeMultiplier: 1.5, FreshnessScore: 1.67, Total: 6.67}, 6.67, true},
+		{"click boost", ScoreBreakdown{BaseScore: 10, TypeMultiplier: 1, FreshnessScore: 2, ClickBoost: 2.5, Total: 14.5}, 14.5, true},
+		{"zero type multiplier", ScoreBreakdown{BaseScore: 10, FreshnessScore: 2, EngagementScore: 1, Total: 3}, 3, true},
+		{"components not stored", ScoreBreakdown{Total: 12}, 12, false},
+		{"total differs from score", ScoreBreakdown{BaseScore: 10, TypeMultiplier: 1, Total: 10}, 12, false},
+		{"empty", ScoreBreakdown{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.breakdown.Explains(tt.score); got != tt.want {
+				t.Errorf("Explains(%v) = %v, want %v", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestValidateProviderContent(t *testing.T) {
 	validContent := ProviderContent{
 		ExternalID:  "test-123",
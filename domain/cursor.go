@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CursorDirection string
+
+const (
+	CursorNext CursorDirection = "next"
+	CursorPrev CursorDirection = "prev"
+)
+
+// Keyset is the position of a content in the result order: the sort key
+// followed by the score, published_at and id tiebreakers.
+type Keyset struct {
+	Popularity  int64     `json:"pop,omitempty"`
+	Score       float64   `json:"s"`
+	PublishedAt time.Time `json:"p"`
+	ID          uuid.UUID `json:"id"`
+}
+
+func KeysetOf(content Content) Keyset {
+	return Keyset{
+		Popularity:  int64(content.Views + content.Likes + content.Reactions),
+		Score:       content.Score,
+		PublishedAt: content.PublishedAt,
+		ID:          content.ID,
+	}
+}
+
+// Cursor is the pagination state behind next_cursor and prev_cursor. Database
+// results are paged by keyset; in-memory results (live providers, fuzzy
+// matches) by offset into a stored snapshot. Search binds the cursor to the
+// query, filters and sort it was issued for.
+type Cursor struct {
+	Search    string          `json:"q"`
+	Direction CursorDirection `json:"d"`
+	Keyset    Keyset          `json:"k"`
+	Snapshot  string          `json:"snap,omitempty"`
+	Offset    int             `json:"o,omitempty"`
+}
+
+// RanksBefore reports whether a is ordered before b. Popularity sorting
+// compares views between videos and reactions between texts; every other
+// case, and ties, fall back to score, published_at and id, all descending.
+func RanksBefore(a, b Content, sortBy string) bool {
+	if sortBy == "popularity" {
+		switch {
+		case a.Type == ContentTypeVideo && b.Type == ContentTypeVideo && a.Views != b.Views:
+			return a.Views > b.Views
+		case a.Type == ContentTypeText && b.Type == ContentTypeText && a.Reactions != b.Reactions:
+			return a.Reactions > b.Reactions
+		}
+	}
+
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if !a.PublishedAt.Equal(b.PublishedAt) {
+		return a.PublishedAt.After(b.PublishedAt)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) > 0
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRanksBefore(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	low := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	high := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	tests := []struct {
+		name   string
+		a, b   Content
+		sortBy string
+		want   bool
+	}{
+		{"higher score", Content{Score: 2}, Content{Score: 1}, "relevant_score", true},
+		{"newer on tie", Content{Score: 1, PublishedAt: day.Add(time.Hour)}, Content{Score: 1, PublishedAt: day}, "relevant_score", true},
+		{"higher id on full tie", Content{Score: 1, PublishedAt: day, ID: high}, Content{Score: 1, PublishedAt: day, ID: low}, "relevant_score", true},
+		{"lower id on full tie", Content{Score: 1, PublishedAt: day, ID: low}, Content{Score: 1, PublishedAt: day, ID: high}, "relevant_score", false},
+		{"popular videos by views", Content{Type: ContentTypeVideo, Views: 10, Score: 1}, Content{Type: ContentTypeVideo, Views: 5, Score: 9}, "popularity", true},
+		{"equal views fall back to score", Content{Type: ContentTypeVideo, Views: 5, Score: 1}, Content{Type: ContentTypeVideo, Views: 5, Score: 9}, "popularity", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RanksBefore(tt.a, tt.b, tt.sortBy); got != tt.want {
+				t.Errorf("RanksBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
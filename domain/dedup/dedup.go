@@ -0,0 +1,232 @@
+// Package dedup groups the same content published by several providers, or
+// by one provider under several external IDs.
+package dedup
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/fuzzy"
+)
+
+// urlFields are the raw data fields a canonical URL is read from, in order of
+// preference. Matching is case-insensitive, so RSS and Atom items encoded
+// from Go structs ("Link", "Links") are covered too.
+var urlFields = []string{"canonical_url", "canonical", "url", "link", "links"}
+
+// Key is what two contents are compared by.
+type Key struct {
+	Title        string
+	CanonicalURL string
+	PublishedAt  time.Time
+}
+
+// Deduper treats two contents as duplicates when their normalized titles are
+// equal and they were published within Window of each other, or, when
+// canonical URLs are enabled, when both point at the same URL.
+type Deduper struct {
+	window       time.Duration
+	canonicalURL bool
+}
+
+func New(window time.Duration, canonicalURL bool) *Deduper {
+	return &Deduper{window: window, canonicalURL: canonicalURL}
+}
+
+func (d *Deduper) Window() time.Duration {
+	return d.window
+}
+
+func (d *Deduper) Key(content domain.Content) Key {
+	key := Key{Title: TitleKey(content.Title), PublishedAt: content.PublishedAt}
+	if d.canonicalURL {
+		key.CanonicalURL = CanonicalURL(content.RawData)
+	}
+	return key
+}
+
+func (d *Deduper) Same(a, b Key) bool {
+	if a.CanonicalURL != "" && a.CanonicalURL == b.CanonicalURL {
+		return true
+	}
+	if a.Title == "" || a.Title != b.Title {
+		return false
+	}
+	gap := a.PublishedAt.Sub(b.PublishedAt)
+	return gap <= d.window && gap >= -d.window
+}
+
+// Dedup keeps the best-scored content of each cluster and lists the others
+// as its alternates. Representatives keep their relative order, so a sorted
+// list stays sorted. It returns the number of contents removed.
+func (d *Deduper) Dedup(contents []domain.Content) ([]domain.Content, int) {
+	idx := clusterIndex{byTitle: make(map[string][]*cluster), byURL: make(map[string]*cluster)}
+	var clusters []*cluster
+
+	for i, content := range contents {
+		key := d.Key(content)
+
+		c := idx.find(d, key)
+		if c == nil {
+			c = &cluster{}
+			clusters = append(clusters, c)
+		}
+		c.keys = append(c.keys, key)
+		c.members = append(c.members, i)
+		idx.add(key, c)
+	}
+
+	if len(clusters) == len(contents) {
+		return contents, 0
+	}
+
+	alternates := make(map[int][]domain.Alternate, len(clusters))
+	for _, c := range clusters {
+		best := c.members[0]
+		for _, member := range c.members[1:] {
+			if contents[member].Score > contents[best].Score {
+				best = member
+			}
+		}
+
+		alternates[best] = nil
+		for _, member := range c.members {
+			if member != best {
+				alternates[best] = append(alternates[best], domain.Alternate{
+					Provider:   contents[member].Provider,
+					ExternalID: contents[member].ExternalID,
+				})
+			}
+		}
+	}
+
+	deduped := make([]domain.Content, 0, len(clusters))
+	for i, content := range contents {
+		others, ok := alternates[i]
+		if !ok {
+			continue
+		}
+		if len(others) > 0 {
+			content.Alternates = append(content.Alternates, others...)
+		}
+		deduped = append(deduped, content)
+	}
+	return deduped, len(contents) - len(deduped)
+}
+
+type cluster struct {
+	keys    []Key
+	members []int
+}
+
+type clusterIndex struct {
+	byTitle map[string][]*cluster
+	byURL   map[string]*cluster
+}
+
+func (idx clusterIndex) find(d *Deduper, key Key) *cluster {
+	if c, ok := idx.byURL[key.CanonicalURL]; ok && key.CanonicalURL != "" {
+		return c
+	}
+	for _, c := range idx.byTitle[key.Title] {
+		for _, other := range c.keys {
+			if d.Same(key, other) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (idx clusterIndex) add(key Key, c *cluster) {
+	if key.CanonicalURL != "" {
+		idx.byURL[key.CanonicalURL] = c
+	}
+	for _, existing := range idx.byTitle[key.Title] {
+		if existing == c {
+			return
+		}
+	}
+	idx.byTitle[key.Title] = append(idx.byTitle[key.Title], c)
+}
+
+// TitleKey normalizes a title for comparison: case, punctuation and spacing
+// are ignored.
+func TitleKey(title string) string {
+	return strings.Join(fuzzy.Tokenize(title), " ")
+}
+
+// CanonicalURL reads the content URL from raw provider data and normalizes
+// it, so http/https, "www.", trailing slashes, fragments and utm_*
+// parameters do not tell two copies apart. It returns "" when there is none.
+func CanonicalURL(raw []byte) string {
+	var fields map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &fields) != nil {
+		return ""
+	}
+
+	for _, name := range urlFields {
+		for field, value := range fields {
+			if !strings.EqualFold(field, name) {
+				continue
+			}
+			if normalized := normalizeURL(urlValue(value)); normalized != "" {
+				return normalized
+			}
+		}
+	}
+	return ""
+}
+
+// urlValue accepts a plain string or a list of Atom style links, preferring
+// the alternate link.
+func urlValue(value json.RawMessage) string {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		return s
+	}
+
+	var links []map[string]string
+	if json.Unmarshal(value, &links) != nil {
+		return ""
+	}
+	for _, link := range links {
+		var href, rel string
+		for k, v := range link {
+			switch strings.ToLower(k) {
+			case "href":
+				href = v
+			case "rel":
+				rel = v
+			}
+		}
+		if href != "" && (rel == "" || rel == "alternate") {
+			return href
+		}
+	}
+	return ""
+}
+
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	query := u.Query()
+	for param := range query {
+		if strings.HasPrefix(strings.ToLower(param), "utm_") {
+			query.Del(param)
+		}
+	}
+
+	normalized := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
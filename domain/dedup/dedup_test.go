@@ -0,0 +1,123 @@
+package dedup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+)
+
+func TestTitleKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Go Tips & Tricks", "go tips  tricks!", true},
+		{"Go: Concurrency", "Go - Concurrency", true},
+		{"Go Tips", "Go Tricks", false},
+	}
+
+	for _, tt := range tests {
+		if got := TitleKey(tt.a) == TitleKey(tt.b); got != tt.same {
+			t.Errorf("TitleKey(%q) == TitleKey(%q) = %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"url field", `{"url": "https://www.Example.com/videos/1/?utm_source=feed"}`, "example.com/videos/1"},
+		{"rss link", `{"Link": "http://example.com/videos/1#top"}`, "example.com/videos/1"},
+		{"atom links", `{"Links": [{"Href": "https://example.com/feed", "Rel": "self"}, {"Href": "https://example.com/videos/1", "Rel": "alternate"}]}`, "example.com/videos/1"},
+		{"canonical preferred", `{"link": "https://a.com/1", "canonical_url": "https://b.com/1"}`, "b.com/1"},
+		{"keeps other query params", `{"url": "https://example.com/watch?v=abc&utm_medium=x"}`, "example.com/watch?v=abc"},
+		{"not a url", `{"url": "video-1"}`, ""},
+		{"no json", `<item/>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalURL([]byte(tt.raw)); got != tt.want {
+				t.Errorf("CanonicalURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduper_Dedup(t *testing.T) {
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	content := func(provider, id, title string, published time.Time, score float64, raw string) domain.Content {
+		return domain.Content{Provider: provider, ExternalID: id, Title: title, PublishedAt: published, Score: score, RawData: []byte(raw)}
+	}
+
+	tests := []struct {
+		name         string
+		canonicalURL bool
+		contents     []domain.Content
+		want         []string
+		alternates   [][]domain.Alternate
+	}{
+		{
+			name: "same title within window",
+			contents: []domain.Content{
+				content("a", "1", "Go Tips", day, 5, ""),
+				content("b", "x", "go tips!", day.Add(3*time.Hour), 9, ""),
+				content("a", "2", "Rust Tips", day, 4, ""),
+			},
+			want:       []string{"x", "2"},
+			alternates: [][]domain.Alternate{{{Provider: "a", ExternalID: "1"}}, nil},
+		},
+		{
+			name: "same title too far apart",
+			contents: []domain.Content{
+				content("a", "1", "Weekly News", day, 5, ""),
+				content("b", "x", "Weekly News", day.Add(72*time.Hour), 9, ""),
+			},
+			want:       []string{"1", "x"},
+			alternates: [][]domain.Alternate{nil, nil},
+		},
+		{
+			name:         "same canonical url",
+			canonicalURL: true,
+			contents: []domain.Content{
+				content("a", "1", "Go Tips", day, 5, `{"url": "https://example.com/go"}`),
+				content("b", "x", "Go tips and tricks", day.Add(72*time.Hour), 1, `{"link": "http://www.example.com/go/"}`),
+			},
+			want:       []string{"1"},
+			alternates: [][]domain.Alternate{{{Provider: "b", ExternalID: "x"}}},
+		},
+		{
+			name: "canonical url ignored when disabled",
+			contents: []domain.Content{
+				content("a", "1", "Go Tips", day, 5, `{"url": "https://example.com/go"}`),
+				content("b", "x", "Go tips and tricks", day, 1, `{"url": "https://example.com/go"}`),
+			},
+			want:       []string{"1", "x"},
+			alternates: [][]domain.Alternate{nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deduped, removed := New(24*time.Hour, tt.canonicalURL).Dedup(tt.contents)
+
+			var ids []string
+			var alternates [][]domain.Alternate
+			for _, c := range deduped {
+				ids = append(ids, c.ExternalID)
+				alternates = append(alternates, c.Alternates)
+			}
+			if !reflect.DeepEqual(ids, tt.want) || !reflect.DeepEqual(alternates, tt.alternates) {
+				t.Errorf("Dedup() = %v %v, want %v %v", ids, alternates, tt.want, tt.alternates)
+			}
+			if removed != len(tt.contents)-len(tt.want) {
+				t.Errorf("removed = %d, want %d", removed, len(tt.contents)-len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventImpression EventType = "impression"
+	EventClick      EventType = "click"
+)
+
+type Event struct {
+	RequestID string
+	ContentID uuid.UUID
+	Type      EventType
+	Position  int
+	Query     string
+	CreatedAt time.Time
+}
+
+type EventInput struct {
+	RequestID string `json:"request_id" validate:"required,max=100"`
+	ContentID string `json:"content_id" validate:"required,uuid"`
+	Type      string `json:"type" validate:"required,oneof=impression click"`
+	Position  int    `json:"position" validate:"gte=1"`
+	Query     string `json:"query" validate:"max=500"`
+}
+
+type EventsRequest struct {
+	Events []EventInput `json:"events" validate:"required,min=1,max=100,dive"`
+}
+
+func (r EventsRequest) ToEvents() []Event {
+	now := time.Now()
+
+	events := make([]Event, len(r.Events))
+	for i, input := range r.Events {
+		events[i] = Event{
+			RequestID: input.RequestID,
+			ContentID: uuid.MustParse(input.ContentID),
+			Type:      EventType(input.Type),
+			Position:  input.Position,
+			Query:     input.Query,
+			CreatedAt: now,
+		}
+	}
+	return events
+}
+
+// PositionPropensity estimates how likely a result at the given 1-based
+// position is to be looked at at all, so clicks on lower positions count for
+// more than clicks on the first result.
+func PositionPropensity(position int) float64 {
+	if position < 1 {
+		position = 1
+	}
+	return 1 / math.Log2(float64(position)+1)
+}
+
+type Engagement struct {
+	Impressions  int64
+	Clicks       int64
+	Examinations float64
+}
+
+// DebiasedCTR is clicks over expected examinations, smoothed towards priorCTR
+// with priorWeight pseudo-examinations so items with few impressions are not
+// boosted by a single lucky click.
+func (e Engagement) DebiasedCTR(priorCTR, priorWeight float64) float64 {
+	denominator := e.Examinations + priorWeight
+	if denominator <= 0 {
+		return 0
+	}
+	return (float64(e.Clicks) + priorCTR*priorWeight) / denominator
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPositionPropensity(t *testing.T) {
+	tests := []struct {
+		position int
+		want     float64
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 0.5},
+		{7, 1.0 / 3},
+	}
+
+	for _, tt := range tests {
+		if got := PositionPropensity(tt.position); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("PositionPropensity(%d) = %v, want %v", tt.position, got, tt.want)
+		}
+	}
+}
+
+func TestEngagement_DebiasedCTR(t *testing.T) {
+	tests := []struct {
+		name       string
+		engagement Engagement
+		want       float64
+	}{
+		{"no data falls back to prior", Engagement{}, 0.1},
+		{"clicks over examinations", Engagement{Impressions: 40, Clicks: 10, Examinations: 10}, 0.55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.engagement.DebiasedCTR(0.1, 10); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DebiasedCTR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEventsRequest(t *testing.T) {
+	valid := EventInput{RequestID: "req-1", ContentID: NewUUID().String(), Type: "click", Position: 1}
+
+	invalidType := valid
+	invalidType.Type = "view"
+	invalidPosition := valid
+	invalidPosition.Position = 0
+	invalidID := valid
+	invalidID.ContentID = "not-a-uuid"
+
+	tests := []struct {
+		name    string
+		req     EventsRequest
+		wantErr bool
+	}{
+		{"valid", EventsRequest{Events: []EventInput{valid}}, false},
+		{"empty", EventsRequest{}, true},
+		{"invalid type", EventsRequest{Events: []EventInput{invalidType}}, true},
+		{"invalid position", EventsRequest{Events: []EventInput{invalidPosition}}, true},
+		{"invalid content id", EventsRequest{Events: []EventInput{valid, invalidID}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEventsRequest(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEventsRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContentID_IsStable(t *testing.T) {
+	if ContentID("provider1", "v1") != ContentID("provider1", "v1") {
+		t.Error("ContentID() should be deterministic")
+	}
+	if ContentID("provider1", "v1") == ContentID("provider2", "v1") {
+		t.Error("ContentID() should differ across providers")
+	}
+}
@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	FacetType      = "type"
+	FacetTags      = "tags"
+	FacetProvider  = "provider"
+	FacetPublished = "published"
+	FacetViews     = "views"
+
+	FacetIntervalWeek  = "week"
+	FacetIntervalMonth = "month"
+
+	DefaultFacetSize = 10
+	MaxFacetSize     = 50
+)
+
+type ViewRange struct {
+	Label string
+	Min   int
+}
+
+// ViewRanges are the fixed view-count buckets, ordered by their lower bound.
+// The upper bound of a range is the lower bound of the next one.
+var ViewRanges = []ViewRange{
+	{Label: "0-999", Min: 0},
+	{Label: "1000-9999", Min: 1000},
+	{Label: "10000-99999", Min: 10000},
+	{Label: "100000+", Min: 100000},
+}
+
+func ViewRangeIndex(views int) int {
+	index := 0
+	for i, r := range ViewRanges {
+		if views >= r.Min {
+			index = i
+		}
+	}
+	return index
+}
+
+type FacetRequest struct {
+	Fields   []string
+	Interval string
+	Size     int
+}
+
+func (r FacetRequest) Enabled() bool {
+	return len(r.Fields) > 0
+}
+
+func (r FacetRequest) Has(field string) bool {
+	for _, f := range r.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FacetRequest) SetDefaults() {
+	if r.Interval == "" {
+		r.Interval = FacetIntervalMonth
+	}
+	if r.Size <= 0 {
+		r.Size = DefaultFacetSize
+	}
+	if r.Size > MaxFacetSize {
+		r.Size = MaxFacetSize
+	}
+}
+
+func (r FacetRequest) Validate() error {
+	for _, field := range r.Fields {
+		switch field {
+		case FacetType, FacetTags, FacetProvider, FacetPublished, FacetViews:
+		default:
+			return fmt.Errorf("unknown facet %q", field)
+		}
+	}
+	if r.Interval != FacetIntervalWeek && r.Interval != FacetIntervalMonth {
+		return fmt.Errorf("invalid facet interval %q, must be 'week' or 'month'", r.Interval)
+	}
+	return nil
+}
+
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type Facets struct {
+	Types     []FacetBucket `json:"type,omitempty"`
+	Tags      []FacetBucket `json:"tags,omitempty"`
+	Providers []FacetBucket `json:"provider,omitempty"`
+	Published []FacetBucket `json:"published,omitempty"`
+	Views     []FacetBucket `json:"views,omitempty"`
+}
+
+// TruncateToInterval returns the start of the week (Monday) or month that t
+// falls in, matching Postgres date_trunc.
+func TruncateToInterval(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if interval == FacetIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func FormatFacetDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func ComputeFacets(contents []Content, req FacetRequest) *Facets {
+	facets := &Facets{}
+
+	if req.Has(FacetType) {
+		counts := make(map[string]int64)
+		for _, content := range contents {
+			counts[string(content.Type)]++
+		}
+		facets.Types = countBuckets(counts, 0)
+	}
+
+	if req.Has(FacetTags) {
+		counts := make(map[string]int64)
+		for _, content := range contents {
+			for _, tag := range content.Tags {
+				counts[tag]++
+			}
+		}
+		facets.Tags = countBuckets(counts, req.Size)
+	}
+
+	if req.Has(FacetProvider) {
+		counts := make(map[string]int64)
+		for _, content := range contents {
+			counts[content.Provider]++
+		}
+		facets.Providers = countBuckets(counts, 0)
+	}
+
+	if req.Has(FacetPublished) {
+		counts := make(map[string]int64)
+		for _, content := range contents {
+			counts[FormatFacetDate(TruncateToInterval(content.PublishedAt, req.Interval))]++
+		}
+		facets.Published = make([]FacetBucket, 0, len(counts))
+		for value, count := range counts {
+			facets.Published = append(facets.Published, FacetBucket{Value: value, Count: count})
+		}
+		sort.Slice(facets.Published, func(i, j int) bool {
+			return facets.Published[i].Value < facets.Published[j].Value
+		})
+	}
+
+	if req.Has(FacetViews) {
+		counts := make([]int64, len(ViewRanges))
+		for _, content := range contents {
+			counts[ViewRangeIndex(content.Views)]++
+		}
+		for i, count := range counts {
+			if count > 0 {
+				facets.Views = append(facets.Views, FacetBucket{Value: ViewRanges[i].Label, Count: count})
+			}
+		}
+	}
+
+	return facets
+}
+
+// countBuckets orders buckets by count, then value, and keeps the first limit
+// of them when limit is positive.
+func countBuckets(counts map[string]int64, limit int) []FacetBucket {
+	buckets := make([]FacetBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, FacetBucket{Value: value, Count: count})
+	}
+	SortFacetBuckets(buckets)
+
+	if limit > 0 && len(buckets) > limit {
+		buckets = buckets[:limit]
+	}
+	return buckets
+}
+
+func SortFacetBuckets(buckets []FacetBucket) {
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputeFacets(t *testing.T) {
+	contents := []Content{
+		{Type: ContentTypeVideo, Provider: "provider1", Tags: []string{"go", "docker"}, Views: 500, PublishedAt: time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)},
+		{Type: ContentTypeVideo, Provider: "provider2", Tags: []string{"go"}, Views: 25000, PublishedAt: time.Date(2024, 3, 17, 10, 0, 0, 0, time.UTC)},
+		{Type: ContentTypeText, Provider: "provider2", Tags: []string{"rust"}, PublishedAt: time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)},
+	}
+
+	req := FacetRequest{Fields: []string{FacetType, FacetTags, FacetProvider, FacetPublished, FacetViews}, Interval: FacetIntervalWeek, Size: 2}
+	facets := ComputeFacets(contents, req)
+
+	want := &Facets{
+		Types:     []FacetBucket{{"video", 2}, {"text", 1}},
+		Tags:      []FacetBucket{{"go", 2}, {"docker", 1}},
+		Providers: []FacetBucket{{"provider2", 2}, {"provider1", 1}},
+		Published: []FacetBucket{{"2024-03-11", 2}, {"2024-04-01", 1}},
+		Views:     []FacetBucket{{"0-999", 2}, {"10000-99999", 1}},
+	}
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("ComputeFacets() = %+v, want %+v", facets, want)
+	}
+
+	monthly := ComputeFacets(contents, FacetRequest{Fields: []string{FacetPublished}, Interval: FacetIntervalMonth})
+	if len(monthly.Published) != 2 || monthly.Published[0] != (FacetBucket{"2024-03-01", 2}) || monthly.Types != nil {
+		t.Errorf("monthly facets = %+v", monthly)
+	}
+}
+
+func TestFacetRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     FacetRequest
+		wantErr bool
+	}{
+		{"valid", FacetRequest{Fields: []string{FacetType, FacetViews}}, false},
+		{"unknown facet", FacetRequest{Fields: []string{"color"}}, true},
+		{"invalid interval", FacetRequest{Fields: []string{FacetPublished}, Interval: "year"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.SetDefaults()
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+
+	filterDateLayout = "2006-01-02"
+)
+
+// SearchFilter narrows results independently of the query text. Zero values
+// disable a filter.
+type SearchFilter struct {
+	Tags           []string
+	TagMode        string
+	ExcludeTags    []string
+	ContentTypes   []string
+	Providers      []string
+	PublishedFrom  time.Time
+	PublishedTo    time.Time
+	MinViews       int
+	MinLikes       int
+	MinReactions   int
+	MaxReadingTime int
+	// Dedup hides all but the best-scored content of each duplicate cluster.
+	// It is applied to result lists, not by Match.
+	Dedup bool
+}
+
+// ParsePublishedBound parses a published_from or published_to value, either a
+// date or an RFC 3339 timestamp. A date used as an upper bound covers the
+// whole day.
+func ParsePublishedBound(value string, upper bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(filterDateLayout, value); err == nil {
+		if upper {
+			t = t.Add(24*time.Hour - time.Microsecond)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, must be YYYY-MM-DD or RFC 3339", value)
+	}
+	return t.UTC(), nil
+}
+
+func (f SearchFilter) Validate() error {
+	if f.TagMode != "" && f.TagMode != TagModeAny && f.TagMode != TagModeAll {
+		return fmt.Errorf("invalid tag_mode %q, must be 'any' or 'all'", f.TagMode)
+	}
+	if f.MinViews < 0 || f.MinLikes < 0 || f.MinReactions < 0 || f.MaxReadingTime < 0 {
+		return errors.New("min_views, min_likes, min_reactions and max_reading_time must not be negative")
+	}
+	if !f.PublishedFrom.IsZero() && !f.PublishedTo.IsZero() && f.PublishedFrom.After(f.PublishedTo) {
+		return errors.New("published_from must not be after published_to")
+	}
+	return nil
+}
+
+func (f SearchFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.ExcludeTags) == 0 && len(f.ContentTypes) == 0 && len(f.Providers) == 0 &&
+		f.PublishedFrom.IsZero() && f.PublishedTo.IsZero() &&
+		f.MinViews == 0 && f.MinLikes == 0 && f.MinReactions == 0 && f.MaxReadingTime == 0
+}
+
+// Match applies the filter in memory, with the same semantics as the
+// database queries. Tags are compared exactly, so both sides need
+// NormalizeTags.
+func (f SearchFilter) Match(content Content) bool {
+	if len(f.ContentTypes) > 0 && !contains(f.ContentTypes, string(content.Type)) {
+		return false
+	}
+	if len(f.Providers) > 0 && !contains(f.Providers, content.Provider) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		if f.TagMode == TagModeAll && !hasAllTags(content.Tags, f.Tags) {
+			return false
+		}
+		if f.TagMode != TagModeAll && !hasAnyTag(content.Tags, f.Tags) {
+			return false
+		}
+	}
+	if len(f.ExcludeTags) > 0 && hasAnyTag(content.Tags, f.ExcludeTags) {
+		return false
+	}
+
+	if !f.PublishedFrom.IsZero() && content.PublishedAt.Before(f.PublishedFrom) {
+		return false
+	}
+	if !f.PublishedTo.IsZero() && content.PublishedAt.After(f.PublishedTo) {
+		return false
+	}
+
+	return content.Views >= f.MinViews &&
+		content.Likes >= f.MinLikes &&
+		content.Reactions >= f.MinReactions &&
+		(f.MaxReadingTime == 0 || content.ReadingTime <= f.MaxReadingTime)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAllTags(tags []string, wanted []string) bool {
+	for _, w := range wanted {
+		if !hasAnyTag(tags, []string{w}) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyTag(tags []string, wanted []string) bool {
+	for _, tag := range tags {
+		if contains(wanted, tag) {
+			return true
+		}
+	}
+	return false
+}
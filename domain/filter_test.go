@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePublishedBound(t *testing.T) {
+	tests := []struct {
+		value   string
+		upper   bool
+		want    time.Time
+		wantErr bool
+	}{
+		{"", false, time.Time{}, false},
+		{"2024-03-01", false, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024-03-01", true, time.Date(2024, 3, 1, 23, 59, 59, 999999000, time.UTC), false},
+		{"2024-03-01T10:00:00+03:00", true, time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC), false},
+		{"last week", false, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePublishedBound(tt.value, tt.upper)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParsePublishedBound(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParsePublishedBound(%q, %v) = %v, want %v", tt.value, tt.upper, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRequest_Filter(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     SearchRequest
+		wantErr bool
+	}{
+		{"empty", SearchRequest{}, false},
+		{"same day range", SearchRequest{PublishedFrom: "2024-03-01", PublishedTo: "2024-03-01"}, false},
+		{"inverted range", SearchRequest{PublishedFrom: "2024-03-02", PublishedTo: "2024-03-01"}, true},
+		{"invalid date", SearchRequest{PublishedTo: "03/01/2024"}, true},
+		{"negative minimum", SearchRequest{MinViews: -1}, true},
+		{"negative reading time", SearchRequest{MaxReadingTime: -5}, true},
+		{"all tags", SearchRequest{Tags: []string{"go"}, TagMode: "all"}, false},
+		{"invalid tag mode", SearchRequest{Tags: []string{"go"}, TagMode: "some"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.req.Filter(); (err != nil) != tt.wantErr {
+				t.Errorf("Filter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchRequest_FilterNormalizesTags(t *testing.T) {
+	filter, err := SearchRequest{Tags: []string{" Go", "go", "Tutorial"}, ExcludeTags: []string{"BEGINNER", ""}}.Filter()
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if !reflect.DeepEqual(filter.Tags, []string{"go", "tutorial"}) || !reflect.DeepEqual(filter.ExcludeTags, []string{"beginner"}) {
+		t.Errorf("Filter() tags = %v, exclude = %v", filter.Tags, filter.ExcludeTags)
+	}
+}
+
+func TestSearchFilter_MatchTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		tags   []string
+		filter SearchFilter
+		want   bool
+	}{
+		{"any matches one", []string{"go"}, SearchFilter{Tags: []string{"go", "tutorial"}}, true},
+		{"all needs every tag", []string{"go"}, SearchFilter{Tags: []string{"go", "tutorial"}, TagMode: TagModeAll}, false},
+		{"all matches", []string{"Tutorial", "go", "web"}, SearchFilter{Tags: []string{"go", "tutorial"}, TagMode: TagModeAll}, true},
+		{"excluded", []string{"go", "tutorial", "beginner"}, SearchFilter{Tags: []string{"go", "tutorial"}, TagMode: TagModeAll, ExcludeTags: []string{"beginner"}}, false},
+		{"exclude only", []string{"go"}, SearchFilter{ExcludeTags: []string{"BEGINNER"}}, true},
+		{"exclude untagged", nil, SearchFilter{ExcludeTags: []string{"beginner"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(Content{Tags: NormalizeTags(tt.tags)}); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilter_Match(t *testing.T) {
+	content := Content{
+		Provider:    "provider1",
+		Type:        ContentTypeVideo,
+		Tags:        NormalizeTags([]string{"Go", "backend"}),
+		PublishedAt: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+		Views:       15000,
+		Likes:       300,
+		ReadingTime: 0,
+	}
+
+	tests := []struct {
+		name   string
+		filter SearchFilter
+		want   bool
+	}{
+		{"empty", SearchFilter{}, true},
+		{"normalized tag", SearchFilter{Tags: []string{"go"}}, true},
+		{"tags compare exactly", SearchFilter{Tags: []string{"Go"}}, false},
+		{"other type", SearchFilter{ContentTypes: []string{"text"}}, false},
+		{"provider", SearchFilter{Providers: []string{"provider2", "provider1"}}, true},
+		{"other provider", SearchFilter{Providers: []string{"provider2"}}, false},
+		{"within dates", SearchFilter{PublishedFrom: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), PublishedTo: time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC)}, true},
+		{"before range", SearchFilter{PublishedFrom: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)}, false},
+		{"enough views", SearchFilter{MinViews: 10000, MinLikes: 300}, true},
+		{"too few likes", SearchFilter{MinLikes: 301}, false},
+		{"too few reactions", SearchFilter{MinReactions: 1}, false},
+		{"reading time", SearchFilter{MaxReadingTime: 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(content); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
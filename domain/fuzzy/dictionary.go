@@ -0,0 +1,150 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Dictionary holds the indexed title terms and tags with how often each was
+// seen. It backs "did you mean" suggestions and is safe for concurrent use.
+// Frequencies come from Load; contents added in between only contribute terms
+// the dictionary does not know yet, so re-indexing a content never inflates
+// them. Terms are bucketed by length, which is all an edit distance search
+// needs to scan.
+type Dictionary struct {
+	mu       sync.RWMutex
+	maxTerms int
+	terms    map[string]int
+	byLength map[int][]string
+}
+
+// NewDictionary returns a dictionary holding at most maxTerms terms, or any
+// number of them when maxTerms is 0.
+func NewDictionary(maxTerms int) *Dictionary {
+	return &Dictionary{
+		maxTerms: maxTerms,
+		terms:    make(map[string]int),
+		byLength: make(map[int][]string),
+	}
+}
+
+// Load replaces the dictionary with terms and their frequencies, keeping the
+// most frequent ones when there are more than it may hold.
+func (d *Dictionary) Load(terms map[string]int) {
+	loaded := make(map[string]int, len(terms))
+	for term, frequency := range terms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" && frequency > 0 {
+			loaded[term] += frequency
+		}
+	}
+
+	keys := make([]string, 0, len(loaded))
+	for term := range loaded {
+		keys = append(keys, term)
+	}
+	if d.maxTerms > 0 && len(keys) > d.maxTerms {
+		sort.Slice(keys, func(i, j int) bool {
+			if loaded[keys[i]] != loaded[keys[j]] {
+				return loaded[keys[i]] > loaded[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		for _, term := range keys[d.maxTerms:] {
+			delete(loaded, term)
+		}
+		keys = keys[:d.maxTerms]
+	}
+
+	byLength := make(map[int][]string)
+	for _, term := range keys {
+		byLength[len([]rune(term))] = append(byLength[len([]rune(term))], term)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.terms, d.byLength = loaded, byLength
+}
+
+// AddContent indexes the words of a title and its tags.
+func (d *Dictionary) AddContent(title string, tags []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, word := range Tokenize(title) {
+		d.add(word)
+	}
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			d.add(tag)
+		}
+	}
+}
+
+func (d *Dictionary) add(term string) {
+	if _, ok := d.terms[term]; ok || d.maxTerms > 0 && len(d.terms) >= d.maxTerms {
+		return
+	}
+	d.terms[term] = 1
+	d.byLength[len([]rune(term))] = append(d.byLength[len([]rune(term))], term)
+}
+
+func (d *Dictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.terms)
+}
+
+// Suggest returns query with every unknown word replaced by its closest
+// dictionary term, or "" when nothing needs correcting.
+func (d *Dictionary) Suggest(query string) string {
+	words := Tokenize(query)
+	if len(words) == 0 {
+		return ""
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	changed := false
+	for i, word := range words {
+		if d.terms[word] > 0 {
+			continue
+		}
+		if correction, ok := d.closest(word); ok {
+			words[i] = correction
+			changed = true
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+// closest picks the term with the fewest edits, preferring more frequent and
+// then alphabetically smaller terms so suggestions are deterministic.
+func (d *Dictionary) closest(word string) (string, bool) {
+	maxEdits := MaxEdits(word)
+	if maxEdits == 0 {
+		return "", false
+	}
+
+	length := len([]rune(word))
+	best, bestDistance, bestFrequency := "", maxEdits+1, 0
+	for l := length - maxEdits; l <= length+maxEdits; l++ {
+		for _, term := range d.byLength[l] {
+			distance := Distance(word, term)
+			if distance > maxEdits {
+				continue
+			}
+			frequency := d.terms[term]
+			if distance < bestDistance ||
+				distance == bestDistance && (frequency > bestFrequency || frequency == bestFrequency && term < best) {
+				best, bestDistance, bestFrequency = term, distance, frequency
+			}
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,105 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize lowercases text and splits it into words on anything that is not
+// a letter or digit, matching the SQL term dictionary.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// MaxEdits is the number of typos tolerated in a word of the given length.
+// Short words must match exactly, otherwise almost anything would match.
+func MaxEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Distance returns the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and transpositions of adjacent
+// characters each cost one edit.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(rb)]
+}
+
+// MatchWord reports whether word is within the typo tolerance of candidate.
+func MatchWord(word, candidate string) bool {
+	maxEdits := MaxEdits(word)
+	if abs(len([]rune(word))-len([]rune(candidate))) > maxEdits {
+		return false
+	}
+	return Distance(word, candidate) <= maxEdits
+}
+
+// MatchText reports whether every word of query is within MaxEdits of some
+// word of text. The database fallback matches differently: it keeps titles
+// whose trigram word similarity to the whole query passes pg_trgm's
+// threshold, so the two can disagree on short words and multi-word queries.
+func MatchText(query, text string) bool {
+	words := Tokenize(query)
+	if len(words) == 0 {
+		return false
+	}
+
+	candidates := Tokenize(text)
+	for _, word := range words {
+		matched := false
+		for _, candidate := range candidates {
+			if MatchWord(word, candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package fuzzy
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "go", 2},
+		{"golang", "golang", 0},
+		{"tutorail", "tutorial", 1},
+		{"golnag", "golang", 1},
+		{"kitten", "sitting", 3},
+		{"dokcer", "docker", 1},
+		{"çalış", "calis", 3},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatchText(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		want  bool
+	}{
+		{"golang tutorail", "Golang Tutorial for Beginners", true},
+		{"golnag", "Advanced Golang Patterns", true},
+		{"go", "Advanced Golang Patterns", false},
+		{"rust tutorial", "Golang Tutorial", false},
+		{"kubernets", "Kubernetes in Production", true},
+		{"", "Anything", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchText(tt.query, tt.text); got != tt.want {
+			t.Errorf("MatchText(%q, %q) = %v, want %v", tt.query, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDictionary_Suggest(t *testing.T) {
+	dict := NewDictionary(0)
+	dict.Load(map[string]int{"tutorials": 1})
+	dict.AddContent("Golang Tutorial for Beginners", []string{"programming"})
+	dict.AddContent("Go Testing Tutorial", []string{"Testing"})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"golang tutorail", "golang tutorial"},
+		{"Golang Tutorial", ""},
+		{"programing", "programming"},
+		{"xyz", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := dict.Suggest(tt.query); got != tt.want {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDictionary_ReindexingKeepsFrequencies(t *testing.T) {
+	dict := NewDictionary(0)
+	dict.Load(map[string]int{"golang": 1})
+	for range 5 {
+		dict.AddContent("Golang", nil)
+	}
+
+	if got := dict.Suggest("gollang"); got != "golang" {
+		t.Errorf("Suggest(gollang) = %q, want golang", got)
+	}
+	if dict.terms["golang"] != 1 {
+		t.Errorf("frequency of golang = %d, want 1", dict.terms["golang"])
+	}
+}
+
+func TestDictionary_MaxTerms(t *testing.T) {
+	dict := NewDictionary(2)
+	dict.Load(map[string]int{"golang": 5, "rust": 3, "python": 1})
+
+	if _, ok := dict.terms["python"]; ok || dict.Len() != 2 {
+		t.Errorf("Load kept %v, want the 2 most frequent terms", dict.terms)
+	}
+
+	dict.AddContent("Kubernetes", nil)
+	if dict.Len() != 2 {
+		t.Errorf("Len() = %d after AddContent, want 2", dict.Len())
+	}
+}
@@ -0,0 +1,178 @@
+package highlight
+
+import (
+	"strings"
+	"unicode"
+
+	"search-engine/domain"
+	"search-engine/domain/fuzzy"
+	"search-engine/domain/query"
+)
+
+// Matches are marked with private-use characters, which never occur in
+// titles, and only turned into the configured tags when rendering. The
+// database path asks ts_headline for the same markers so both paths share
+// the rendering step.
+const (
+	MarkStart = "\uE000"
+	MarkStop  = "\uE001"
+)
+
+type Word struct {
+	Text   string
+	Prefix bool
+}
+
+// Terms are the positive parts of a query that can be highlighted. Words
+// under NOT are skipped, since a result can never contain them.
+type Terms struct {
+	Words []Word
+	Tags  []Word
+}
+
+func (t Terms) IsEmpty() bool {
+	return len(t.Words) == 0 && len(t.Tags) == 0
+}
+
+func TermsFromQuery(q *query.Query) Terms {
+	var terms Terms
+	collect(q.Root, &terms)
+	return terms
+}
+
+func collect(node query.Node, terms *Terms) {
+	switch n := node.(type) {
+	case *query.And:
+		for _, child := range n.Children {
+			collect(child, terms)
+		}
+	case *query.Or:
+		for _, child := range n.Children {
+			collect(child, terms)
+		}
+	case *query.Term:
+		switch n.Field {
+		case "", query.FieldTitle:
+			words := fuzzy.Tokenize(n.Value)
+			for i, word := range words {
+				// Only the last word of a prefix term is a prefix.
+				terms.Words = append(terms.Words, Word{Text: word, Prefix: n.Prefix && i == len(words)-1})
+			}
+		case query.FieldTag:
+			terms.Tags = append(terms.Tags, Word{Text: strings.ToLower(n.Value), Prefix: n.Prefix})
+		}
+	}
+}
+
+// TSQuery returns the words as a to_tsquery('simple', ...) expression that
+// matches any of them. Words are tokenized, so they contain no operators.
+func (t Terms) TSQuery() string {
+	parts := make([]string, 0, len(t.Words))
+	for _, word := range t.Words {
+		if word.Prefix {
+			parts = append(parts, word.Text+":*")
+		} else {
+			parts = append(parts, word.Text)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (t Terms) matchWord(token string) bool {
+	for _, word := range t.Words {
+		if token == word.Text || word.Prefix && strings.HasPrefix(token, word.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark wraps every word of text that matches a query word in the markers. It
+// tokenizes like fuzzy.Tokenize and the simple text search configuration.
+func (t Terms) Mark(text string) string {
+	var b strings.Builder
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		if t.matchWord(strings.ToLower(word)) {
+			b.WriteString(MarkStart + word + MarkStop)
+		} else {
+			b.WriteString(word)
+		}
+	}
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// MarkTags returns the matching tags, marked. A tag matches a tag: filter as
+// a whole, or a query word by any of its words.
+func (t Terms) MarkTags(tags []string) []string {
+	var marked []string
+	for _, tag := range tags {
+		lowered := strings.ToLower(tag)
+
+		whole := false
+		for _, term := range t.Tags {
+			if lowered == term.Text || term.Prefix && strings.HasPrefix(lowered, term.Text) {
+				whole = true
+				break
+			}
+		}
+
+		switch {
+		case whole:
+			marked = append(marked, MarkStart+tag+MarkStop)
+		case strings.Contains(t.Mark(tag), MarkStart):
+			marked = append(marked, t.Mark(tag))
+		}
+	}
+	return marked
+}
+
+type Highlighter struct {
+	PreTag  string
+	PostTag string
+}
+
+func NewHighlighter(preTag, postTag string) Highlighter {
+	if preTag == "" && postTag == "" {
+		preTag, postTag = "<em>", "</em>"
+	}
+	return Highlighter{PreTag: preTag, PostTag: postTag}
+}
+
+func (h Highlighter) Render(marked string) string {
+	return strings.NewReplacer(MarkStart, h.PreTag, MarkStop, h.PostTag).Replace(marked)
+}
+
+// Highlights builds the highlights of a content from its marked title, or
+// nil when neither the title nor any tag matched.
+func (h Highlighter) Highlights(markedTitle string, tags []string) *domain.Highlights {
+	highlights := &domain.Highlights{}
+	if strings.Contains(markedTitle, MarkStart) {
+		highlights.Title = h.Render(markedTitle)
+	}
+	for _, tag := range tags {
+		highlights.Tags = append(highlights.Tags, h.Render(tag))
+	}
+
+	if highlights.Title == "" && len(highlights.Tags) == 0 {
+		return nil
+	}
+	return highlights
+}
@@ -0,0 +1,77 @@
+package highlight
+
+import (
+	"reflect"
+	"testing"
+
+	"search-engine/domain"
+	"search-engine/domain/query"
+)
+
+func TestTerms(t *testing.T) {
+	q, err := query.Parse(`"clean code" prog* -java tag:golang title:Go-Tips`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	terms := TermsFromQuery(q)
+	if got, want := terms.TSQuery(), "clean | code | prog:* | go | tips"; got != want {
+		t.Errorf("TSQuery() = %q, want %q", got, want)
+	}
+	if want := []Word{{Text: "golang"}}; !reflect.DeepEqual(terms.Tags, want) {
+		t.Errorf("Tags = %+v, want %+v", terms.Tags, want)
+	}
+}
+
+func TestTerms_Mark(t *testing.T) {
+	q, err := query.Parse("go prog*")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	terms := TermsFromQuery(q)
+	h := NewHighlighter("", "")
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Go Programming: Go's Basics", "<em>Go</em> <em>Programming</em>: <em>Go</em>'s Basics"},
+		{"Golang Progress", "Golang <em>Progress</em>"},
+		{"Rust", "Rust"},
+	}
+
+	for _, tt := range tests {
+		if got := h.Render(terms.Mark(tt.title)); got != tt.want {
+			t.Errorf("Mark(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestHighlighter_Highlights(t *testing.T) {
+	q, err := query.Parse("docker tag:dev*")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	terms := TermsFromQuery(q)
+	h := NewHighlighter("[", "]")
+
+	tests := []struct {
+		name  string
+		title string
+		tags  []string
+		want  *domain.Highlights
+	}{
+		{"title and tags", "Docker Basics", []string{"DevOps", "docker compose", "linux"}, &domain.Highlights{Title: "[Docker] Basics", Tags: []string{"[DevOps]", "[docker] compose"}}},
+		{"tags only", "Containers", []string{"devops"}, &domain.Highlights{Tags: []string{"[devops]"}}},
+		{"no match", "Containers", []string{"linux"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.Highlights(terms.Mark(tt.title), terms.MarkTags(tt.tags))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlights() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
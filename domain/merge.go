@@ -0,0 +1,52 @@
+package domain
+
+import "container/heap"
+
+// MergeSorted merges lists that are each ordered by RanksBefore into one list
+// in the same order.
+func MergeSorted(lists [][]Content, sortBy string) []Content {
+	size := 0
+	h := &mergeHeap{sortBy: sortBy}
+	for _, list := range lists {
+		size += len(list)
+		if len(list) > 0 {
+			h.heads = append(h.heads, list)
+		}
+	}
+	heap.Init(h)
+
+	merged := make([]Content, 0, size)
+	for h.Len() > 0 {
+		list := h.heads[0]
+		merged = append(merged, list[0])
+		if len(list) > 1 {
+			h.heads[0] = list[1:]
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return merged
+}
+
+// mergeHeap holds the remaining part of each list, ordered by its first item.
+type mergeHeap struct {
+	heads  [][]Content
+	sortBy string
+}
+
+func (h *mergeHeap) Len() int { return len(h.heads) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	return RanksBefore(h.heads[i][0], h.heads[j][0], h.sortBy)
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+
+func (h *mergeHeap) Push(x any) { h.heads = append(h.heads, x.([]Content)) }
+
+func (h *mergeHeap) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeSorted(t *testing.T) {
+	scored := func(scores ...float64) []Content {
+		contents := make([]Content, len(scores))
+		for i, score := range scores {
+			contents[i] = Content{Score: score}
+		}
+		return contents
+	}
+	scores := func(contents []Content) []float64 {
+		out := make([]float64, len(contents))
+		for i, content := range contents {
+			out[i] = content.Score
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		lists [][]Content
+		want  []float64
+	}{
+		{"no lists", nil, []float64{}},
+		{"single list", [][]Content{scored(3, 2, 1)}, []float64{3, 2, 1}},
+		{"interleaved", [][]Content{scored(9, 5, 1), scored(8, 6, 2), scored(7)}, []float64{9, 8, 7, 6, 5, 2, 1}},
+		{"empty lists skipped", [][]Content{{}, scored(4, 2), nil, scored(3)}, []float64{4, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scores(MergeSorted(tt.lists, "relevant_score")); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FieldTitle     = "title"
+	FieldTag       = "tag"
+	FieldType      = "type"
+	FieldProvider  = "provider"
+	FieldViews     = "views"
+	FieldPublished = "published"
+)
+
+var knownFields = map[string]bool{
+	FieldTitle:     true,
+	FieldTag:       true,
+	FieldType:      true,
+	FieldProvider:  true,
+	FieldViews:     true,
+	FieldPublished: true,
+}
+
+const (
+	OpEq  = "="
+	OpGt  = ">"
+	OpGte = ">="
+	OpLt  = "<"
+	OpLte = "<="
+)
+
+type Node interface {
+	String() string
+}
+
+type And struct {
+	Children []Node
+}
+
+type Or struct {
+	Children []Node
+}
+
+type Not struct {
+	Child Node
+}
+
+// Term is a single search condition. Text terms without a field match the
+// title; views and published terms carry a comparison operator.
+type Term struct {
+	Field  string
+	Value  string
+	Phrase bool
+	Prefix bool
+	Op     string
+	Number int
+	Time   time.Time
+}
+
+func (n *And) String() string {
+	return "(" + joinNodes(n.Children, " AND ") + ")"
+}
+
+func (n *Or) String() string {
+	return "(" + joinNodes(n.Children, " OR ") + ")"
+}
+
+func (n *Not) String() string {
+	return "NOT " + n.Child.String()
+}
+
+func (t *Term) String() string {
+	value := t.Value
+	if t.Phrase {
+		value = `"` + value + `"`
+	}
+	if t.Prefix {
+		value += "*"
+	}
+	if t.Op != "" && t.Op != OpEq {
+		value = t.Op + value
+	}
+	if t.Field != "" {
+		return t.Field + ":" + value
+	}
+	return value
+}
+
+func (t *Term) isText() bool {
+	return t.Field == "" || t.Field == FieldTitle
+}
+
+func joinNodes(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
+
+// Query is a parsed search query. A nil Root matches everything.
+type Query struct {
+	Raw  string
+	Root Node
+}
+
+func (q *Query) IsEmpty() bool {
+	return q.Root == nil
+}
+
+// IsSimple reports whether the query is only plain words joined by AND, which the existing full-text path and provider filters already handle.
+func (q *Query) IsSimple() bool {
+	switch n := q.Root.(type) {
+	case nil:
+		return true
+	case *Term:
+		return n.isPlainWord()
+	case *And:
+		for _, child := range n.Children {
+			term, ok := child.(*Term)
+			if !ok || !term.isPlainWord() {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Term) isPlainWord() bool {
+	return t.Field == "" && !t.Phrase && !t.Prefix
+}
+
+// ProviderText returns text that is safe to push down to provider search.
+// Plain queries are passed as their words; otherwise only a single required
+// title term is pushed down, and anything more complex returns "" so
+// providers return everything and the matcher filters locally.
+func (q *Query) ProviderText() string {
+	if q.IsSimple() {
+		return plainText(q.Root)
+	}
+
+	var required []Node
+	switch n := q.Root.(type) {
+	case *Term:
+		required = []Node{n}
+	case *And:
+		required = n.Children
+	}
+
+	text := ""
+	for _, child := range required {
+		term, ok := child.(*Term)
+		if !ok || !term.isText() || term.Prefix {
+			continue
+		}
+		if text != "" {
+			return ""
+		}
+		text = term.Value
+	}
+	return text
+}
+
+func plainText(node Node) string {
+	switch n := node.(type) {
+	case *Term:
+		return n.Value
+	case *And:
+		words := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			words[i] = child.(*Term).Value
+		}
+		return strings.Join(words, " ")
+	default:
+		return ""
+	}
+}
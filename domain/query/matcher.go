@@ -0,0 +1,127 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"search-engine/domain"
+)
+
+// Match reports whether content satisfies the query. It is used to filter
+// provider results, which only understand plain text search.
+func (q *Query) Match(content domain.Content) bool {
+	if q.Root == nil {
+		return true
+	}
+	return matchNode(q.Root, content)
+}
+
+func matchNode(node Node, content domain.Content) bool {
+	switch n := node.(type) {
+	case *And:
+		for _, child := range n.Children {
+			if !matchNode(child, content) {
+				return false
+			}
+		}
+		return true
+	case *Or:
+		for _, child := range n.Children {
+			if matchNode(child, content) {
+				return true
+			}
+		}
+		return false
+	case *Not:
+		return !matchNode(n.Child, content)
+	case *Term:
+		return matchTerm(n, content)
+	default:
+		return false
+	}
+}
+
+func matchTerm(t *Term, content domain.Content) bool {
+	switch t.Field {
+	case "", FieldTitle:
+		return matchText(t, content.Title)
+	case FieldTag:
+		for _, tag := range content.Tags {
+			if matchValue(t, tag) {
+				return true
+			}
+		}
+		return false
+	case FieldType:
+		return matchValue(t, string(content.Type))
+	case FieldProvider:
+		return matchValue(t, content.Provider)
+	case FieldViews:
+		return compareInt(content.Views, t.Op, t.Number)
+	case FieldPublished:
+		return compareDate(content.PublishedAt, t.Op, t.Time)
+	default:
+		return false
+	}
+}
+
+func matchText(t *Term, title string) bool {
+	title = strings.ToLower(title)
+	value := strings.ToLower(t.Value)
+
+	if t.Prefix {
+		for _, word := range strings.FieldsFunc(title, isWordSeparator) {
+			if strings.HasPrefix(word, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(title, value)
+}
+
+func matchValue(t *Term, value string) bool {
+	if t.Prefix {
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(t.Value))
+	}
+	return strings.EqualFold(value, t.Value)
+}
+
+func isWordSeparator(r rune) bool {
+	return !(r == '_' || r == '\'' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127)
+}
+
+func compareInt(actual int, op string, expected int) bool {
+	switch op {
+	case OpGt:
+		return actual > expected
+	case OpGte:
+		return actual >= expected
+	case OpLt:
+		return actual < expected
+	case OpLte:
+		return actual <= expected
+	default:
+		return actual == expected
+	}
+}
+
+// compareDate compares at day granularity, so published:2024-01-01 matches
+// anything published that day.
+func compareDate(actual time.Time, op string, expected time.Time) bool {
+	actual = actual.UTC()
+	day := time.Date(actual.Year(), actual.Month(), actual.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch op {
+	case OpGt:
+		return day.After(expected)
+	case OpGte:
+		return !day.Before(expected)
+	case OpLt:
+		return day.Before(expected)
+	case OpLte:
+		return !day.After(expected)
+	default:
+		return day.Equal(expected)
+	}
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"search-engine/domain"
+)
+
+func TestQuery_Match(t *testing.T) {
+	content := domain.Content{
+		Title:       "Clean Code in Go",
+		Provider:    "provider1",
+		Type:        domain.ContentTypeVideo,
+		Tags:        []string{"Programming", "backend"},
+		Views:       1500,
+		PublishedAt: time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"", true},
+		{"clean go", true},
+		{"clean rust", false},
+		{"rust OR go", true},
+		{"go -code", false},
+		{"NOT (rust OR java)", true},
+		{`"clean code"`, true},
+		{`"code clean"`, false},
+		{"cle*", true},
+		{"lean*", false},
+		{"title:clean", true},
+		{"tag:programming", true},
+		{"tag:prog*", true},
+		{"tag:frontend", false},
+		{"type:video provider:provider1", true},
+		{"type:text", false},
+		{"views:>1000", true},
+		{"views:<=1000", false},
+		{"published:2024-03-15", true},
+		{"published:>2024-03-15", false},
+		{"published:>=2024-03-15", true},
+		{"published:<2024-03-16", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := q.Match(content); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
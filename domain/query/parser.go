@@ -0,0 +1,305 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenField
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+const dateLayout = "2006-01-02"
+
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return &Query{Raw: input}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Position: tok.pos, Message: "unexpected " + describe(tok)}
+	}
+
+	return &Query{Raw: input, Root: root}, nil
+}
+
+// tokenize splits the input into tokens. Positions are 1-based so they can be
+// shown to users as-is.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: pos})
+			i++
+		case r == '"':
+			value, next, err := readPhrase(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, value: value, pos: pos})
+			i = next
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '('):
+			tokens = append(tokens, token{kind: tokenNot, value: "-", pos: pos})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				if runes[i] == ':' && knownFields[strings.ToLower(string(runes[start:i]))] {
+					break
+				}
+				i++
+			}
+			word := string(runes[start:i])
+
+			if i < len(runes) && runes[i] == ':' {
+				tokens = append(tokens, token{kind: tokenField, value: strings.ToLower(word), pos: pos})
+				i++
+				continue
+			}
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, value: word, pos: pos})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, value: word, pos: pos})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, value: word, pos: pos})
+			default:
+				tokens = append(tokens, token{kind: tokenWord, value: word, pos: pos})
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+func readPhrase(runes []rune, start int) (string, int, error) {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '"' {
+			return string(runes[start+1 : i]), i + 1, nil
+		}
+	}
+	return "", 0, &SyntaxError{Position: start + 1, Message: "unterminated phrase"}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{left}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &Or{Children: children}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{left}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenPhrase, tokenField, tokenNot, tokenLParen:
+			// Adjacent terms are an implicit AND.
+		default:
+			if len(children) == 1 {
+				return left, nil
+			}
+			return &And{Children: children}, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Position: closing.pos, Message: "expected ')' but found " + describe(closing)}
+		}
+		return node, nil
+	case tokenWord:
+		return wordTerm("", tok.value), nil
+	case tokenPhrase:
+		return &Term{Value: tok.value, Phrase: true}, nil
+	case tokenField:
+		return p.parseField(tok)
+	default:
+		return nil, &SyntaxError{Position: tok.pos, Message: "expected a search term but found " + describe(tok)}
+	}
+}
+
+func (p *parser) parseField(field token) (Node, error) {
+	valueTok := p.next()
+	if valueTok.pos != field.pos+len([]rune(field.value))+1 || (valueTok.kind != tokenWord && valueTok.kind != tokenPhrase) {
+		return nil, &SyntaxError{Position: field.pos, Message: "missing value for field '" + field.value + "'"}
+	}
+
+	switch field.value {
+	case FieldViews, FieldPublished:
+		if valueTok.kind == tokenPhrase {
+			return nil, &SyntaxError{Position: valueTok.pos, Message: "field '" + field.value + "' does not accept phrases"}
+		}
+		return comparisonTerm(field.value, valueTok)
+	default:
+		if valueTok.kind == tokenPhrase {
+			return &Term{Field: field.value, Value: valueTok.value, Phrase: true}, nil
+		}
+		return wordTerm(field.value, valueTok.value), nil
+	}
+}
+
+func wordTerm(field, value string) *Term {
+	term := &Term{Field: field, Value: value}
+	if len(value) > 1 && strings.HasSuffix(value, "*") {
+		term.Value = strings.TrimSuffix(value, "*")
+		term.Prefix = true
+	}
+	return term
+}
+
+func comparisonTerm(field string, tok token) (Node, error) {
+	op, value := splitOperator(tok.value)
+	valuePos := tok.pos + len(op)
+	if op == "" {
+		op = OpEq
+	}
+
+	if value == "" {
+		return nil, &SyntaxError{Position: valuePos, Message: "missing value for field '" + field + "'"}
+	}
+
+	term := &Term{Field: field, Value: value, Op: op}
+	switch field {
+	case FieldViews:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, &SyntaxError{Position: valuePos, Message: "views must be a non-negative integer"}
+		}
+		term.Number = n
+	case FieldPublished:
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, &SyntaxError{Position: valuePos, Message: "published must be a date in YYYY-MM-DD format"}
+		}
+		term.Time = t
+	}
+	return term, nil
+}
+
+func splitOperator(value string) (string, string) {
+	for _, op := range []string{OpGte, OpLte, OpGt, OpLt, OpEq} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "", value
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenPhrase:
+		return `"` + tok.value + `"`
+	case tokenField:
+		return "'" + tok.value + ":'"
+	default:
+		return "'" + tok.value + "'"
+	}
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		simple bool
+	}{
+		{"empty", "   ", "", true},
+		{"plain words", "go programming", "(go AND programming)", true},
+		{"explicit and", "go AND docker", "(go AND docker)", true},
+		{"or binds looser than and", "go docker OR rust", "((go AND docker) OR rust)", false},
+		{"not keyword", "go NOT java", "(go AND NOT java)", false},
+		{"minus negation", "go -java", "(go AND NOT java)", false},
+		{"hyphenated word", "e-commerce", "e-commerce", true},
+		{"phrase", `"clean code"`, `"clean code"`, false},
+		{"prefix", "prog*", "prog*", false},
+		{"grouping", "(go OR rust) tag:backend", "((go OR rust) AND tag:backend)", false},
+		{"field phrase", `title:"clean code"`, `title:"clean code"`, false},
+		{"field is case insensitive", "Type:video", "type:video", false},
+		{"unknown field is a word", "http://example.com", "http://example.com", true},
+		{"views comparison", "views:>1000", "views:>1000", false},
+		{"views equality", "views:42", "views:42", false},
+		{"published comparison", "published:>=2024-01-01", "published:>=2024-01-01", false},
+		{"lowercase keywords are words", "go and docker", "(go AND and AND docker)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+
+			got := ""
+			if q.Root != nil {
+				got = q.Root.String()
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if q.IsSimple() != tt.simple {
+				t.Errorf("IsSimple() = %v, want %v", q.IsSimple(), tt.simple)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+	}{
+		{"unterminated phrase", `go "clean code`, 4},
+		{"missing closing paren", "(go OR rust", 12},
+		{"unexpected closing paren", "go)", 3},
+		{"dangling operator", "go AND", 7},
+		{"leading operator", "OR go", 1},
+		{"missing field value", "tag: go", 1},
+		{"invalid views", "views:>many", 8},
+		{"invalid date", "published:<2024-13-01", 12},
+		{"missing comparison value", "views:>=", 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want SyntaxError", tt.input, err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("Parse(%q) position = %d, want %d (%v)", tt.input, syntaxErr.Position, tt.position, err)
+			}
+		})
+	}
+}
+
+func TestQuery_ProviderText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"go programming", "go programming"},
+		{"go tag:backend views:>100", "go"},
+		{`"clean code" -draft`, "clean code"},
+		{"go OR rust", ""},
+		{"go  AND docker", "go docker"},
+		{"prog*", ""},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.input, err)
+		}
+		if got := q.ProviderText(); got != tt.want {
+			t.Errorf("ProviderText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
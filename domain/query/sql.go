@@ -0,0 +1,122 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SQL compiles the query into a predicate over the contents table. Arguments
+// are numbered from $offset+1 so the predicate can be combined with other
+// conditions. An empty query compiles to TRUE.
+func (q *Query) SQL(offset int) (string, []any) {
+	if q.Root == nil {
+		return "TRUE", nil
+	}
+
+	c := &sqlCompiler{offset: offset}
+	return c.node(q.Root), c.args
+}
+
+type sqlCompiler struct {
+	offset int
+	args   []any
+}
+
+func (c *sqlCompiler) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", c.offset+len(c.args))
+}
+
+func (c *sqlCompiler) node(node Node) string {
+	switch n := node.(type) {
+	case *And:
+		return c.join(n.Children, " AND ")
+	case *Or:
+		return c.join(n.Children, " OR ")
+	case *Not:
+		return "NOT " + c.node(n.Child)
+	case *Term:
+		return c.term(n)
+	default:
+		return "FALSE"
+	}
+}
+
+func (c *sqlCompiler) join(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = c.node(n)
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+func (c *sqlCompiler) term(t *Term) string {
+	switch t.Field {
+	case "", FieldTitle:
+		if t.Prefix {
+			lexeme := prefixLexeme(t.Value)
+			if lexeme == "" {
+				return "FALSE"
+			}
+			return fmt.Sprintf("to_tsvector('english', title) @@ to_tsquery('english', %s || ':*')", c.arg(lexeme))
+		}
+		value := t.Value
+		if t.Phrase {
+			value = `"` + value + `"`
+		}
+		return fmt.Sprintf("to_tsvector('english', title) @@ websearch_to_tsquery('english', %s)", c.arg(value))
+	case FieldTag:
+		if t.Prefix {
+			return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(tags) AS t WHERE lower(t) LIKE %s)", c.arg(likePrefix(t.Value)))
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(tags) AS t WHERE lower(t) = lower(%s))", c.arg(t.Value))
+	case FieldType, FieldProvider:
+		if t.Prefix {
+			return fmt.Sprintf("lower(%s) LIKE %s", t.Field, c.arg(likePrefix(t.Value)))
+		}
+		return fmt.Sprintf("lower(%s) = lower(%s)", t.Field, c.arg(t.Value))
+	case FieldViews:
+		return fmt.Sprintf("COALESCE(views, 0) %s %s", t.Op, c.arg(t.Number))
+	case FieldPublished:
+		return c.published(t)
+	default:
+		return "FALSE"
+	}
+}
+
+// published compares at day granularity to match the in-memory matcher.
+func (c *sqlCompiler) published(t *Term) string {
+	day := t.Time
+	nextDay := day.Add(24 * time.Hour)
+
+	switch t.Op {
+	case OpGt:
+		return "published_at >= " + c.arg(nextDay)
+	case OpGte:
+		return "published_at >= " + c.arg(day)
+	case OpLt:
+		return "published_at < " + c.arg(day)
+	case OpLte:
+		return "published_at < " + c.arg(nextDay)
+	default:
+		return fmt.Sprintf("(published_at >= %s AND published_at < %s)", c.arg(day), c.arg(nextDay))
+	}
+}
+
+func likePrefix(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(value))
+	return escaped + "%"
+}
+
+// prefixLexeme keeps only characters that are safe inside to_tsquery so user
+// input cannot inject tsquery operators.
+func prefixLexeme(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
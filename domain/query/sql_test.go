@@ -0,0 +1,79 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuery_SQL(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		offset   int
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "empty",
+			input:   "",
+			wantSQL: "TRUE",
+		},
+		{
+			name:     "boolean text",
+			input:    `(go OR "clean code") -java`,
+			wantSQL:  "((to_tsvector('english', title) @@ websearch_to_tsquery('english', $1) OR to_tsvector('english', title) @@ websearch_to_tsquery('english', $2)) AND NOT to_tsvector('english', title) @@ websearch_to_tsquery('english', $3))",
+			wantArgs: []any{"go", `"clean code"`, "java"},
+		},
+		{
+			name:     "prefix strips tsquery operators",
+			input:    "prog&!*",
+			wantSQL:  "to_tsvector('english', title) @@ to_tsquery('english', $1 || ':*')",
+			wantArgs: []any{"prog"},
+		},
+		{
+			name:     "fields with offset",
+			input:    "tag:go type:video views:>=100",
+			offset:   2,
+			wantSQL:  "(EXISTS (SELECT 1 FROM unnest(tags) AS t WHERE lower(t) = lower($3)) AND lower(type) = lower($4) AND COALESCE(views, 0) >= $5)",
+			wantArgs: []any{"go", "video", 100},
+		},
+		{
+			name:     "tag prefix escapes like wildcards",
+			input:    "tag:web_*",
+			wantSQL:  "EXISTS (SELECT 1 FROM unnest(tags) AS t WHERE lower(t) LIKE $1)",
+			wantArgs: []any{`web\_%`},
+		},
+		{
+			name:     "published day",
+			input:    "published:2024-01-01",
+			wantSQL:  "(published_at >= $1 AND published_at < $2)",
+			wantArgs: []any{day, day.AddDate(0, 0, 1)},
+		},
+		{
+			name:     "published after day",
+			input:    "published:>2024-01-01",
+			wantSQL:  "published_at >= $1",
+			wantArgs: []any{day.AddDate(0, 0, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+
+			sql, args := q.SQL(tt.offset)
+			if sql != tt.wantSQL {
+				t.Errorf("SQL() = %s, want %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQL() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
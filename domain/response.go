@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
@@ -18,20 +23,67 @@ type Meta struct {
 	PerPage    int    `json:"per_page,omitempty"`
 	Total      int64  `json:"total,omitempty"`
 	TotalPages int    `json:"total_pages,omitempty"`
+	Estimated  bool   `json:"total_estimated,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 	RequestID  string `json:"request_id"`
 }
 
 type SearchData struct {
-	Items []Content `json:"items"`
+	Items      []Content `json:"items"`
+	Facets     *Facets   `json:"facets,omitempty"`
+	Suggestion string    `json:"suggestion,omitempty"`
+}
+
+type ExplainedSearchData struct {
+	Items      []ContentWithScore `json:"items"`
+	Facets     *Facets            `json:"facets,omitempty"`
+	Suggestion string             `json:"suggestion,omitempty"`
 }
 
 type SearchRequest struct {
-	Query        string   `json:"query"`
-	Tags         []string `json:"tags"`
-	ContentTypes []string `json:"types"`
-	OrderBy      string   `json:"orderBy"`
-	Page         int      `json:"page"`
-	PerPage      int      `json:"perPage"`
+	Query          string   `json:"query"`
+	Tags           []string `json:"tags"`
+	TagMode        string   `json:"tag_mode"`
+	ExcludeTags    []string `json:"exclude_tags"`
+	ContentTypes   []string `json:"types"`
+	Providers      []string `json:"providers"`
+	PublishedFrom  string   `json:"published_from"`
+	PublishedTo    string   `json:"published_to"`
+	MinViews       int      `json:"min_views"`
+	MinLikes       int      `json:"min_likes"`
+	MinReactions   int      `json:"min_reactions"`
+	MaxReadingTime int      `json:"max_reading_time"`
+	OrderBy        string   `json:"orderBy"`
+	Page           int      `json:"page"`
+	PerPage        int      `json:"perPage"`
+	Cursor         string   `json:"cursor"`
+	Dedup          *bool    `json:"dedup"`
+	Explain        bool     `json:"explain"`
+	Scorer         string   `json:"scorer"`
+	Facets         []string `json:"facets"`
+	FacetInterval  string   `json:"facet_interval"`
+	FacetSize      int      `json:"facet_size"`
+}
+
+type ScoreExplainRequest struct {
+	Type        string    `json:"type" validate:"required,oneof=video text"`
+	PublishedAt time.Time `json:"published_at"`
+	Views       int       `json:"views" validate:"gte=0"`
+	Likes       int       `json:"likes" validate:"gte=0"`
+	ReadingTime int       `json:"reading_time" validate:"gte=0"`
+	Reactions   int       `json:"reactions" validate:"gte=0"`
+}
+
+func (r ScoreExplainRequest) ToProviderContent() ProviderContent {
+	return ProviderContent{
+		Type:        r.Type,
+		PublishedAt: r.PublishedAt,
+		Views:       r.Views,
+		Likes:       r.Likes,
+		ReadingTime: r.ReadingTime,
+		Reactions:   r.Reactions,
+	}
 }
 
 func (r *SearchRequest) SetDefaults() {
@@ -49,6 +101,38 @@ func (r *SearchRequest) SetDefaults() {
 	}
 }
 
+// Filter parses and validates the filters of the request.
+func (r SearchRequest) Filter() (SearchFilter, error) {
+	from, err := ParsePublishedBound(r.PublishedFrom, false)
+	if err != nil {
+		return SearchFilter{}, fmt.Errorf("published_from: %w", err)
+	}
+	to, err := ParsePublishedBound(r.PublishedTo, true)
+	if err != nil {
+		return SearchFilter{}, fmt.Errorf("published_to: %w", err)
+	}
+
+	tagMode := r.TagMode
+	if tagMode == "" {
+		tagMode = TagModeAny
+	}
+
+	filter := SearchFilter{
+		Tags:           NormalizeTags(r.Tags),
+		TagMode:        tagMode,
+		ExcludeTags:    NormalizeTags(r.ExcludeTags),
+		ContentTypes:   r.ContentTypes,
+		Providers:      r.Providers,
+		PublishedFrom:  from,
+		PublishedTo:    to,
+		MinViews:       r.MinViews,
+		MinLikes:       r.MinLikes,
+		MinReactions:   r.MinReactions,
+		MaxReadingTime: r.MaxReadingTime,
+	}
+	return filter, filter.Validate()
+}
+
 func NewSuccessResponse(data interface{}, meta *Meta) Response {
 	return Response{
 		Success: true,
@@ -2,33 +2,157 @@ package scoring
 
 import (
 	"math"
+	"sort"
 	"time"
 
 	"search-engine/domain"
 )
 
-type Scorer struct{}
+type FreshnessBucket struct {
+	MaxAge time.Duration
+	Score  float64
+}
+
+// Weights configure a weighted scorer. Nil weights take their default, so a
+// config block only needs to list the values it changes. A zero weight turns
+// its component off, and so does a zero divisor.
+type Weights struct {
+	VideoMultiplier       *float64
+	TextMultiplier        *float64
+	ViewsDivisor          *float64
+	LikesDivisor          *float64
+	ReadingTimeWeight     *float64
+	ReactionsDivisor      *float64
+	VideoEngagementWeight *float64
+	TextEngagementWeight  *float64
+	Freshness             []FreshnessBucket
+
+	// FreshnessHalfLife switches freshness from the step buckets to a
+	// continuous exponential decay starting at FreshnessMax.
+	FreshnessHalfLife time.Duration
+	FreshnessMax      *float64
+}
+
+// Weight returns a pointer to v for setting a single weight.
+func Weight(v float64) *float64 {
+	return &v
+}
+
+func DefaultWeights() Weights {
+	return Weights{
+		VideoMultiplier:       Weight(1.5),
+		TextMultiplier:        Weight(1.0),
+		ViewsDivisor:          Weight(1000),
+		LikesDivisor:          Weight(100),
+		ReadingTimeWeight:     Weight(1.0),
+		ReactionsDivisor:      Weight(50),
+		VideoEngagementWeight: Weight(10),
+		TextEngagementWeight:  Weight(5),
+		FreshnessMax:          Weight(5),
+		Freshness: []FreshnessBucket{
+			{MaxAge: 7 * 24 * time.Hour, Score: 5},
+			{MaxAge: 30 * 24 * time.Hour, Score: 3},
+			{MaxAge: 90 * 24 * time.Hour, Score: 1},
+		},
+	}
+}
+
+// weights are Weights with every default resolved.
+type weights struct {
+	VideoMultiplier       float64
+	TextMultiplier        float64
+	ViewsDivisor          float64
+	LikesDivisor          float64
+	ReadingTimeWeight     float64
+	ReactionsDivisor      float64
+	VideoEngagementWeight float64
+	TextEngagementWeight  float64
+	Freshness             []FreshnessBucket
+	FreshnessHalfLife     time.Duration
+	FreshnessMax          float64
+}
+
+func (w Weights) resolve() weights {
+	defaults := DefaultWeights()
+
+	value := func(weight, fallback *float64) float64 {
+		if weight != nil {
+			return *weight
+		}
+		return *fallback
+	}
+	resolved := weights{
+		VideoMultiplier:       value(w.VideoMultiplier, defaults.VideoMultiplier),
+		TextMultiplier:        value(w.TextMultiplier, defaults.TextMultiplier),
+		ViewsDivisor:          value(w.ViewsDivisor, defaults.ViewsDivisor),
+		LikesDivisor:          value(w.LikesDivisor, defaults.LikesDivisor),
+		ReadingTimeWeight:     value(w.ReadingTimeWeight, defaults.ReadingTimeWeight),
+		ReactionsDivisor:      value(w.ReactionsDivisor, defaults.ReactionsDivisor),
+		VideoEngagementWeight: value(w.VideoEngagementWeight, defaults.VideoEngagementWeight),
+		TextEngagementWeight:  value(w.TextEngagementWeight, defaults.TextEngagementWeight),
+		FreshnessHalfLife:     w.FreshnessHalfLife,
+		FreshnessMax:          value(w.FreshnessMax, defaults.FreshnessMax),
+	}
+
+	if len(w.Freshness) == 0 {
+		resolved.Freshness = defaults.Freshness
+	} else {
+		resolved.Freshness = append([]FreshnessBucket(nil), w.Freshness...)
+		sort.Slice(resolved.Freshness, func(i, j int) bool {
+			return resolved.Freshness[i].MaxAge < resolved.Freshness[j].MaxAge
+		})
+	}
+
+	return resolved
+}
+
+type Scorer struct {
+	name    string
+	weights weights
+}
 
 func NewScorer() *Scorer {
-	return &Scorer{}
+	return NewWeightedScorer(DefaultStrategy, DefaultWeights())
+}
+
+func NewWeightedScorer(name string, w Weights) *Scorer {
+	return &Scorer{
+		name:    name,
+		weights: w.resolve(),
+	}
+}
+
+func (s *Scorer) Name() string {
+	return s.name
 }
 
 func (s *Scorer) CalculateScore(content domain.ProviderContent) float64 {
+	return s.Breakdown(content).Total
+}
+
+func (s *Scorer) Breakdown(content domain.ProviderContent) domain.ScoreBreakdown {
 	baseScore := s.calculateBaseScore(content)
 	typeMultiplier := s.getTypeMultiplier(content.Type)
 	freshnessScore := s.calculateFreshnessScore(content.PublishedAt)
 	engagementScore := s.calculateEngagementScore(content)
 
 	finalScore := (baseScore * typeMultiplier) + freshnessScore + engagementScore
-	return roundTo2Decimals(finalScore)
+
+	return domain.ScoreBreakdown{
+		BaseScore:       roundTo2Decimals(baseScore),
+		TypeMultiplier:  typeMultiplier,
+		FreshnessScore:  roundTo2Decimals(freshnessScore),
+		EngagementScore: roundTo2Decimals(engagementScore),
+		Total:           roundTo2Decimals(finalScore),
+	}
 }
 
 func (s *Scorer) calculateBaseScore(content domain.ProviderContent) float64 {
 	switch content.Type {
 	case "video":
-		return float64(content.Views)/1000.0 + float64(content.Likes)/100.0
+		return divide(content.Views, s.weights.ViewsDivisor) + divide(content.Likes, s.weights.LikesDivisor)
 	case "text":
-		return float64(content.ReadingTime) + float64(content.Reactions)/50.0
+		return float64(content.ReadingTime)*s.weights.ReadingTimeWeight + divide(content.Reactions, s.weights.ReactionsDivisor)
 	default:
 		return 0
 	}
@@ -37,27 +161,30 @@ func (s *Scorer) calculateBaseScore(content domain.ProviderContent) float64 {
 func (s *Scorer) getTypeMultiplier(contentType string) float64 {
 	switch contentType {
 	case "video":
-		return 1.5
+		return s.weights.VideoMultiplier
 	case "text":
-		return 1.0
+		return s.weights.TextMultiplier
 	default:
 		return 1.0
 	}
 }
 
 func (s *Scorer) calculateFreshnessScore(publishedAt time.Time) float64 {
-	daysSincePublished := time.Since(publishedAt).Hours() / 24
-
-	switch {
-	case daysSincePublished <= 7:
-		return 5.0
-	case daysSincePublished <= 30:
-		return 3.0
-	case daysSincePublished <= 90:
-		return 1.0
-	default:
-		return 0.0
+	age := time.Since(publishedAt)
+
+	if s.weights.FreshnessHalfLife > 0 {
+		if age < 0 {
+			age = 0
+		}
+		return s.weights.FreshnessMax * math.Pow(0.5, float64(age)/float64(s.weights.FreshnessHalfLife))
+	}
+
+	for _, bucket := range s.weights.Freshness {
+		if age <= bucket.MaxAge {
+			return bucket.Score
+		}
 	}
+	return 0.0
 }
 
 func (s *Scorer) calculateEngagementScore(content domain.ProviderContent) float64 {
@@ -66,17 +193,25 @@ func (s *Scorer) calculateEngagementScore(content domain.ProviderContent) float6
 		if content.Views == 0 {
 			return 0
 		}
-		return float64(content.Likes) / float64(content.Views) * 10.0
+		return float64(content.Likes) / float64(content.Views) * s.weights.VideoEngagementWeight
 	case "text":
 		if content.ReadingTime == 0 {
 			return 0
 		}
-		return float64(content.Reactions) / float64(content.ReadingTime) * 5.0
+		return float64(content.Reactions) / float64(content.ReadingTime) * s.weights.TextEngagementWeight
 	default:
 		return 0
 	}
 }
 
+// divide scales a count down by divisor, where a zero divisor drops the term.
+func divide(count int, divisor float64) float64 {
+	if divisor == 0 {
+		return 0
+	}
+	return float64(count) / divisor
+}
+
 func roundTo2Decimals(val float64) float64 {
 	return math.Round(val*100) / 100
 }
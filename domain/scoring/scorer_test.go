@@ -1,6 +1,7 @@
 package scoring
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -84,3 +85,127 @@ func TestScorer_FreshnessDecay(t *testing.T) {
 		t.Errorf("Fresh content score (%v) should be higher than old content score (%v)", freshScore, oldScore)
 	}
 }
+
+func TestScorer_Breakdown(t *testing.T) {
+	scorer := NewScorer()
+
+	content := domain.ProviderContent{
+		Type:        "video",
+		Views:       10000,
+		Likes:       500,
+		PublishedAt: time.Now().AddDate(0, 0, -1),
+	}
+
+	breakdown := scorer.Breakdown(content)
+
+	want := domain.ScoreBreakdown{
+		BaseScore:       15,
+		TypeMultiplier:  1.5,
+		FreshnessScore:  5,
+		EngagementScore: 0.5,
+		Total:           28,
+	}
+	if breakdown != want {
+		t.Errorf("Breakdown() = %+v, want %+v", breakdown, want)
+	}
+
+	if score := scorer.CalculateScore(content); score != breakdown.Total {
+		t.Errorf("CalculateScore() = %v, want breakdown total %v", score, breakdown.Total)
+	}
+}
+
+func TestScorer_CustomWeights(t *testing.T) {
+	scorer := NewWeightedScorer("custom", Weights{
+		VideoMultiplier:      Weight(2),
+		TextEngagementWeight: Weight(0),
+		LikesDivisor:         Weight(0),
+		Freshness:            []FreshnessBucket{{MaxAge: 30 * 24 * time.Hour, Score: 10}, {MaxAge: 24 * time.Hour, Score: 50}},
+	})
+
+	tests := []struct {
+		name    string
+		content domain.ProviderContent
+		want    domain.ScoreBreakdown
+	}{
+		{
+			name:    "newest bucket wins regardless of config order",
+			content: domain.ProviderContent{Type: "video", Views: 1000, PublishedAt: time.Now().Add(-time.Hour)},
+			want:    domain.ScoreBreakdown{BaseScore: 1, TypeMultiplier: 2, FreshnessScore: 50, Total: 52},
+		},
+		{
+			name:    "unset weights fall back to defaults",
+			content: domain.ProviderContent{Type: "text", ReadingTime: 4, PublishedAt: time.Now().AddDate(0, 0, -10)},
+			want:    domain.ScoreBreakdown{BaseScore: 4, TypeMultiplier: 1, FreshnessScore: 10, Total: 14},
+		},
+		{
+			name:    "zero weight turns engagement off",
+			content: domain.ProviderContent{Type: "text", ReadingTime: 4, Reactions: 50, PublishedAt: time.Now().AddDate(0, 0, -10)},
+			want:    domain.ScoreBreakdown{BaseScore: 5, TypeMultiplier: 1, FreshnessScore: 10, Total: 15},
+		},
+		{
+			name:    "zero divisor drops the term",
+			content: domain.ProviderContent{Type: "video", Views: 2000, Likes: 300, PublishedAt: time.Now().AddDate(0, 0, -10)},
+			want:    domain.ScoreBreakdown{BaseScore: 2, TypeMultiplier: 2, FreshnessScore: 10, EngagementScore: 1.5, Total: 15.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scorer.Breakdown(tt.content); got != tt.want {
+				t.Errorf("Breakdown() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategies_Get(t *testing.T) {
+	custom, err := CreateStrategy(TypeWeighted, "custom", Weights{})
+	if err != nil {
+		t.Fatalf("CreateStrategy() error = %v", err)
+	}
+
+	strategies, err := NewStrategies("custom", custom)
+	if err != nil {
+		t.Fatalf("NewStrategies() error = %v", err)
+	}
+
+	if got, _ := strategies.Get(""); got.Name() != "custom" {
+		t.Errorf("Get(\"\") = %s, want configured default", got.Name())
+	}
+	if got, _ := strategies.Get(DefaultStrategy); got.Name() != DefaultStrategy {
+		t.Errorf("Get(default) = %s, want built-in default", got.Name())
+	}
+	if _, err := strategies.Get("missing"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("Get(missing) error = %v, want ErrUnknownStrategy", err)
+	}
+	if _, err := NewStrategies("missing"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("NewStrategies(missing) error = %v, want ErrUnknownStrategy", err)
+	}
+	if _, err := CreateStrategy("bogus", "x", Weights{}); err == nil {
+		t.Error("CreateStrategy(bogus) expected error")
+	}
+}
+
+func TestScorer_ExponentialFreshness(t *testing.T) {
+	scorer := NewWeightedScorer("decay", Weights{FreshnessHalfLife: 7 * 24 * time.Hour, FreshnessMax: Weight(8)})
+
+	tests := []struct {
+		name string
+		age  time.Duration
+		want float64
+	}{
+		{"just published", 0, 8},
+		{"one half-life", 7 * 24 * time.Hour, 4},
+		{"two half-lives", 14 * 24 * time.Hour, 2},
+		{"future dates are capped", -time.Hour, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := domain.ProviderContent{Type: "text", PublishedAt: time.Now().Add(-tt.age)}
+			if got := scorer.Breakdown(content).FreshnessScore; got != tt.want {
+				t.Errorf("FreshnessScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"search-engine/domain"
+)
+
+const (
+	DefaultStrategy = "default"
+	TypeWeighted    = "weighted"
+)
+
+var ErrUnknownStrategy = errors.New("unknown scoring strategy")
+
+type Strategy interface {
+	Name() string
+	Breakdown(content domain.ProviderContent) domain.ScoreBreakdown
+}
+
+type StrategyFactory func(name string, weights Weights) Strategy
+
+type registry struct {
+	mu        sync.RWMutex
+	factories map[string]StrategyFactory
+}
+
+var globalRegistry = &registry{
+	factories: make(map[string]StrategyFactory),
+}
+
+func init() {
+	Register(TypeWeighted, func(name string, weights Weights) Strategy {
+		return NewWeightedScorer(name, weights)
+	})
+}
+
+func Register(strategyType string, factory StrategyFactory) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	globalRegistry.factories[strategyType] = factory
+}
+
+func GetFactory(strategyType string) (StrategyFactory, error) {
+	globalRegistry.mu.RLock()
+	defer globalRegistry.mu.RUnlock()
+
+	factory, exists := globalRegistry.factories[strategyType]
+	if !exists {
+		return nil, fmt.Errorf("unknown scoring strategy type: %s", strategyType)
+	}
+	return factory, nil
+}
+
+func CreateStrategy(strategyType, name string, weights Weights) (Strategy, error) {
+	if strategyType == "" {
+		strategyType = TypeWeighted
+	}
+
+	factory, err := GetFactory(strategyType)
+	if err != nil {
+		return nil, err
+	}
+	strategy := factory(name, weights)
+	if strategy == nil {
+		return nil, fmt.Errorf("failed to create %s scoring strategy: %s", strategyType, name)
+	}
+	return strategy, nil
+}
+
+func ListRegisteredTypes() []string {
+	globalRegistry.mu.RLock()
+	defer globalRegistry.mu.RUnlock()
+
+	types := make([]string, 0, len(globalRegistry.factories))
+	for strategyType := range globalRegistry.factories {
+		types = append(types, strategyType)
+	}
+	return types
+}
+
+// Strategies is the set of configured strategies a request can pick from by
+// name. The built-in default strategy is always available.
+type Strategies struct {
+	defaultName string
+	strategies  map[string]Strategy
+}
+
+func NewStrategies(defaultName string, strategies ...Strategy) (*Strategies, error) {
+	if defaultName == "" {
+		defaultName = DefaultStrategy
+	}
+
+	set := &Strategies{
+		defaultName: defaultName,
+		strategies:  map[string]Strategy{DefaultStrategy: NewScorer()},
+	}
+	for _, strategy := range strategies {
+		set.strategies[strategy.Name()] = strategy
+	}
+
+	if _, ok := set.strategies[defaultName]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStrategy, defaultName)
+	}
+	return set, nil
+}
+
+func DefaultStrategies() *Strategies {
+	set, _ := NewStrategies(DefaultStrategy)
+	return set
+}
+
+func (s *Strategies) Get(name string) (Strategy, error) {
+	if name == "" {
+		return s.Default(), nil
+	}
+
+	strategy, ok := s.strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStrategy, name)
+	}
+	return strategy, nil
+}
+
+func (s *Strategies) Default() Strategy {
+	return s.strategies[s.defaultName]
+}
+
+func (s *Strategies) Names() []string {
+	names := make([]string, 0, len(s.strategies))
+	for name := range s.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
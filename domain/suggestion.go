@@ -0,0 +1,14 @@
+package domain
+
+type SuggestionType string
+
+const (
+	SuggestionTitle SuggestionType = "title"
+	SuggestionTag   SuggestionType = "tag"
+)
+
+type Suggestion struct {
+	Text       string         `json:"text"`
+	Type       SuggestionType `json:"type"`
+	Popularity int64          `json:"popularity"`
+}
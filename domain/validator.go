@@ -13,7 +13,19 @@ func init() {
 }
 
 func ValidateProviderContent(content ProviderContent) error {
-	if err := validate.Struct(content); err != nil {
+	return validateStruct(content)
+}
+
+func ValidateScoreExplainRequest(req ScoreExplainRequest) error {
+	return validateStruct(req)
+}
+
+func ValidateEventsRequest(req EventsRequest) error {
+	return validateStruct(req)
+}
+
+func validateStruct(v interface{}) error {
+	if err := validate.Struct(v); err != nil {
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
 			return fmt.Errorf("validation failed: %s", formatValidationErrors(validationErrors))
 		}
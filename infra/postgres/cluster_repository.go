@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"search-engine/app/search"
+	"search-engine/domain"
+	"search-engine/domain/dedup"
+	"search-engine/infra/postgres/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type clusterRepository struct {
+	queries *db.Queries
+}
+
+func NewClusterRepository(database *PostgresDB) search.ClusterStore {
+	return &clusterRepository{
+		queries: db.New(database.Pool),
+	}
+}
+
+func (r *clusterRepository) Assign(ctx context.Context, content *domain.Content, key dedup.Key, window time.Duration) error {
+	err := r.queries.AssignContentCluster(ctx, db.AssignContentClusterParams{
+		TitleKey:      key.Title,
+		PublishedAt:   pgtype.Timestamp{Time: key.PublishedAt, Valid: true},
+		WindowSeconds: window.Seconds(),
+		CanonicalUrl:  key.CanonicalURL,
+		Provider:      content.Provider,
+		ExternalID:    content.ExternalID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to assign content cluster: %w", err)
+	}
+	return nil
+}
+
+func (r *clusterRepository) Alternates(ctx context.Context, contentIDs []uuid.UUID) (map[uuid.UUID][]domain.Alternate, error) {
+	ids := make([]pgtype.UUID, len(contentIDs))
+	for i, id := range contentIDs {
+		ids[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	rows, err := r.queries.ListClusterAlternates(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster alternates: %w", err)
+	}
+
+	alternates := make(map[uuid.UUID][]domain.Alternate)
+	for _, row := range rows {
+		id := uuidFromPgtype(row.ContentID)
+		alternates[id] = append(alternates[id], domain.Alternate{Provider: row.Provider, ExternalID: row.ExternalID})
+	}
+	return alternates, nil
+}
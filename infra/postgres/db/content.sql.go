@@ -20,22 +20,121 @@ WHERE (
     )
     AND (
         cardinality($2::text[]) = 0 OR 
-        tags && $2::text[]
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
     )
     AND (
-        cardinality($3::text[]) = 0 OR 
-        type = ANY($3::text[])
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
     )
 `
 
 type CountSearchContentsParams struct {
-	Query        string   `json:"query"`
-	Tags         []string `json:"tags"`
-	ContentTypes []string `json:"content_types"`
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
 }
 
 func (q *Queries) CountSearchContents(ctx context.Context, arg CountSearchContentsParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countSearchContents, arg.Query, arg.Tags, arg.ContentTypes)
+	row := q.db.QueryRow(ctx, countSearchContents,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -51,8 +150,10 @@ func (q *Queries) DeleteContent(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getContentByExternalID = `-- name: GetContentByExternalID :one
-SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+SELECT id, external_id, provider, title, type, published_at, raw_data,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE provider = $1 AND external_id = $2
 `
@@ -63,20 +164,25 @@ type GetContentByExternalIDParams struct {
 }
 
 type GetContentByExternalIDRow struct {
-	ID          pgtype.UUID      `json:"id"`
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	RawData         []byte           `json:"raw_data"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
 }
 
 func (q *Queries) GetContentByExternalID(ctx context.Context, arg GetContentByExternalIDParams) (GetContentByExternalIDRow, error) {
@@ -89,11 +195,16 @@ func (q *Queries) GetContentByExternalID(ctx context.Context, arg GetContentByEx
 		&i.Title,
 		&i.Type,
 		&i.PublishedAt,
+		&i.RawData,
 		&i.Views,
 		&i.Likes,
 		&i.Reactions,
 		&i.ReadingTime,
 		&i.Score,
+		&i.BaseScore,
+		&i.TypeMultiplier,
+		&i.FreshnessScore,
+		&i.EngagementScore,
 		&i.Tags,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -102,27 +213,34 @@ func (q *Queries) GetContentByExternalID(ctx context.Context, arg GetContentByEx
 }
 
 const getContentByID = `-- name: GetContentByID :one
-SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+SELECT id, external_id, provider, title, type, published_at, raw_data,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE id = $1
 `
 
 type GetContentByIDRow struct {
-	ID          pgtype.UUID      `json:"id"`
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	RawData         []byte           `json:"raw_data"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
 }
 
 func (q *Queries) GetContentByID(ctx context.Context, id pgtype.UUID) (GetContentByIDRow, error) {
@@ -135,11 +253,16 @@ func (q *Queries) GetContentByID(ctx context.Context, id pgtype.UUID) (GetConten
 		&i.Title,
 		&i.Type,
 		&i.PublishedAt,
+		&i.RawData,
 		&i.Views,
 		&i.Likes,
 		&i.Reactions,
 		&i.ReadingTime,
 		&i.Score,
+		&i.BaseScore,
+		&i.TypeMultiplier,
+		&i.FreshnessScore,
+		&i.EngagementScore,
 		&i.Tags,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -149,7 +272,9 @@ func (q *Queries) GetContentByID(ctx context.Context, id pgtype.UUID) (GetConten
 
 const searchContents = `-- name: SearchContents :many
 SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE (
         $1::text = '' OR 
@@ -157,49 +282,148 @@ WHERE (
     )
     AND (
         cardinality($2::text[]) = 0 OR 
-        tags && $2::text[]
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
     )
     AND (
-        cardinality($3::text[]) = 0 OR 
-        type = ANY($3::text[])
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
     )
 ORDER BY
-    CASE WHEN $4::varchar = 'popularity' THEN (views + likes + reactions) END DESC,
-    score DESC
-LIMIT $6::int OFFSET $5::int
+    CASE WHEN $14::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END DESC,
+    score DESC, published_at DESC, id DESC
+LIMIT $16::int OFFSET $15::int
 `
 
 type SearchContentsParams struct {
-	Query        string   `json:"query"`
-	Tags         []string `json:"tags"`
-	ContentTypes []string `json:"content_types"`
-	SortBy       string   `json:"sort_by"`
-	PageOffset   int32    `json:"page_offset"`
-	PageLimit    int32    `json:"page_limit"`
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+	SortBy         string           `json:"sort_by"`
+	PageOffset     int32            `json:"page_offset"`
+	PageLimit      int32            `json:"page_limit"`
 }
 
 type SearchContentsRow struct {
-	ID          pgtype.UUID      `json:"id"`
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
 }
 
 func (q *Queries) SearchContents(ctx context.Context, arg SearchContentsParams) ([]SearchContentsRow, error) {
 	rows, err := q.db.Query(ctx, searchContents,
 		arg.Query,
 		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
 		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
 		arg.SortBy,
 		arg.PageOffset,
 		arg.PageLimit,
@@ -223,6 +447,430 @@ func (q *Queries) SearchContents(ctx context.Context, arg SearchContentsParams)
 			&i.Reactions,
 			&i.ReadingTime,
 			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchContentsAfter = `-- name: SearchContentsAfter :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+    AND (
+        ($14::varchar = 'popularity' AND
+            (COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0), score, published_at, id) <
+            ($15::bigint, $16::numeric, $17::timestamp, $18::uuid))
+        OR
+        ($14::varchar <> 'popularity' AND
+            (score, published_at, id) <
+            ($16::numeric, $17::timestamp, $18::uuid))
+    )
+ORDER BY
+    CASE WHEN $14::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END DESC,
+    score DESC, published_at DESC, id DESC
+LIMIT $19::int
+`
+
+type SearchContentsAfterParams struct {
+	Query             string           `json:"query"`
+	Tags              []string         `json:"tags"`
+	TagMode           string           `json:"tag_mode"`
+	ExcludeTags       []string         `json:"exclude_tags"`
+	ContentTypes      []string         `json:"content_types"`
+	Providers         []string         `json:"providers"`
+	PublishedFrom     pgtype.Timestamp `json:"published_from"`
+	PublishedTo       pgtype.Timestamp `json:"published_to"`
+	MinViews          int32            `json:"min_views"`
+	MinLikes          int32            `json:"min_likes"`
+	MinReactions      int32            `json:"min_reactions"`
+	MaxReadingTime    int32            `json:"max_reading_time"`
+	Dedup             bool             `json:"dedup"`
+	SortBy            string           `json:"sort_by"`
+	CursorPopularity  int64            `json:"cursor_popularity"`
+	CursorScore       pgtype.Numeric   `json:"cursor_score"`
+	CursorPublishedAt pgtype.Timestamp `json:"cursor_published_at"`
+	CursorID          pgtype.UUID      `json:"cursor_id"`
+	PageLimit         int32            `json:"page_limit"`
+}
+
+type SearchContentsAfterRow struct {
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) SearchContentsAfter(ctx context.Context, arg SearchContentsAfterParams) ([]SearchContentsAfterRow, error) {
+	rows, err := q.db.Query(ctx, searchContentsAfter,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.SortBy,
+		arg.CursorPopularity,
+		arg.CursorScore,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchContentsAfterRow{}
+	for rows.Next() {
+		var i SearchContentsAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Provider,
+			&i.Title,
+			&i.Type,
+			&i.PublishedAt,
+			&i.Views,
+			&i.Likes,
+			&i.Reactions,
+			&i.ReadingTime,
+			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchContentsBefore = `-- name: SearchContentsBefore :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+    AND (
+        ($14::varchar = 'popularity' AND
+            (COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0), score, published_at, id) >
+            ($15::bigint, $16::numeric, $17::timestamp, $18::uuid))
+        OR
+        ($14::varchar <> 'popularity' AND
+            (score, published_at, id) >
+            ($16::numeric, $17::timestamp, $18::uuid))
+    )
+ORDER BY
+    CASE WHEN $14::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END ASC,
+    score ASC, published_at ASC, id ASC
+LIMIT $19::int
+`
+
+type SearchContentsBeforeParams struct {
+	Query             string           `json:"query"`
+	Tags              []string         `json:"tags"`
+	TagMode           string           `json:"tag_mode"`
+	ExcludeTags       []string         `json:"exclude_tags"`
+	ContentTypes      []string         `json:"content_types"`
+	Providers         []string         `json:"providers"`
+	PublishedFrom     pgtype.Timestamp `json:"published_from"`
+	PublishedTo       pgtype.Timestamp `json:"published_to"`
+	MinViews          int32            `json:"min_views"`
+	MinLikes          int32            `json:"min_likes"`
+	MinReactions      int32            `json:"min_reactions"`
+	MaxReadingTime    int32            `json:"max_reading_time"`
+	Dedup             bool             `json:"dedup"`
+	SortBy            string           `json:"sort_by"`
+	CursorPopularity  int64            `json:"cursor_popularity"`
+	CursorScore       pgtype.Numeric   `json:"cursor_score"`
+	CursorPublishedAt pgtype.Timestamp `json:"cursor_published_at"`
+	CursorID          pgtype.UUID      `json:"cursor_id"`
+	PageLimit         int32            `json:"page_limit"`
+}
+
+type SearchContentsBeforeRow struct {
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) SearchContentsBefore(ctx context.Context, arg SearchContentsBeforeParams) ([]SearchContentsBeforeRow, error) {
+	rows, err := q.db.Query(ctx, searchContentsBefore,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.SortBy,
+		arg.CursorPopularity,
+		arg.CursorScore,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchContentsBeforeRow{}
+	for rows.Next() {
+		var i SearchContentsBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Provider,
+			&i.Title,
+			&i.Type,
+			&i.PublishedAt,
+			&i.Views,
+			&i.Likes,
+			&i.Reactions,
+			&i.ReadingTime,
+			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
 			&i.Tags,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -238,51 +886,84 @@ func (q *Queries) SearchContents(ctx context.Context, arg SearchContentsParams)
 }
 
 const upsertContent = `-- name: UpsertContent :one
-INSERT INTO contents (
-    external_id, provider, title, type, published_at, raw_data,
-    views, likes, reactions, reading_time, score, tags
-) VALUES (
-    $1, $2, $3, $4, $5, $6,
-    $7, $8, $9, $10, $11, $12
+WITH upserted AS (
+    INSERT INTO contents (
+        id, external_id, provider, title, type, published_at, raw_data,
+        views, likes, reactions, reading_time, score,
+        base_score, type_multiplier, freshness_score, engagement_score, tags
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7,
+        $8, $9, $10, $11, $12,
+        $13, $14, $15, $16, $17
+    )
+    ON CONFLICT (provider, external_id)
+    DO UPDATE SET
+        title = EXCLUDED.title,
+        published_at = EXCLUDED.published_at,
+        raw_data = EXCLUDED.raw_data,
+        views = EXCLUDED.views,
+        likes = EXCLUDED.likes,
+        reactions = EXCLUDED.reactions,
+        reading_time = EXCLUDED.reading_time,
+        score = EXCLUDED.score,
+        base_score = EXCLUDED.base_score,
+        type_multiplier = EXCLUDED.type_multiplier,
+        freshness_score = EXCLUDED.freshness_score,
+        engagement_score = EXCLUDED.engagement_score,
+        tags = EXCLUDED.tags,
+        updated_at = NOW()
+    RETURNING id, created_at, updated_at,
+              title, type, published_at, raw_data, views, likes, reactions, reading_time, tags
+), previous AS (
+    SELECT title, type, published_at, raw_data, views, likes, reactions, reading_time, tags
+    FROM contents
+    WHERE provider = $3 AND external_id = $2
 )
-ON CONFLICT (provider, external_id)
-DO UPDATE SET
-    title = EXCLUDED.title,
-    published_at = EXCLUDED.published_at,
-    raw_data = EXCLUDED.raw_data,
-    views = EXCLUDED.views,
-    likes = EXCLUDED.likes,
-    reactions = EXCLUDED.reactions,
-    reading_time = EXCLUDED.reading_time,
-    score = EXCLUDED.score,
-    tags = EXCLUDED.tags,
-    updated_at = NOW()
-RETURNING id, created_at, updated_at
+SELECT upserted.id, upserted.created_at, upserted.updated_at,
+       NOT EXISTS (
+           SELECT 1
+           FROM previous
+           WHERE (previous.title, previous.type, previous.published_at, previous.raw_data, previous.views,
+                  previous.likes, previous.reactions, previous.reading_time, previous.tags)
+                 IS NOT DISTINCT FROM
+                 (upserted.title, upserted.type, upserted.published_at, upserted.raw_data, upserted.views,
+                  upserted.likes, upserted.reactions, upserted.reading_time, upserted.tags)
+       ) AS changed
+FROM upserted
 `
 
 type UpsertContentParams struct {
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	RawData     []byte           `json:"raw_data"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	RawData         []byte           `json:"raw_data"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
 }
 
 type UpsertContentRow struct {
 	ID        pgtype.UUID      `json:"id"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Changed   bool             `json:"changed"`
 }
 
+// changed tells whether the content data differs from the stored row, so
+// rewriting unchanged contents can be told apart from real inserts and updates.
 func (q *Queries) UpsertContent(ctx context.Context, arg UpsertContentParams) (UpsertContentRow, error) {
 	row := q.db.QueryRow(ctx, upsertContent,
+		arg.ID,
 		arg.ExternalID,
 		arg.Provider,
 		arg.Title,
@@ -294,23 +975,34 @@ func (q *Queries) UpsertContent(ctx context.Context, arg UpsertContentParams) (U
 		arg.Reactions,
 		arg.ReadingTime,
 		arg.Score,
+		arg.BaseScore,
+		arg.TypeMultiplier,
+		arg.FreshnessScore,
+		arg.EngagementScore,
 		arg.Tags,
 	)
 	var i UpsertContentRow
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Changed,
+	)
 	return i, err
 }
 
 const searchContentsByProvider = `-- name: SearchContentsByProvider :many
 SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE provider = $1::varchar
   AND (
     $2::text = '' OR
     to_tsvector('english', title) @@ plainto_tsquery('english', $2::text)
   )
-ORDER BY score DESC
+ORDER BY score DESC, published_at DESC, id DESC
 LIMIT $4::int OFFSET $3::int
 `
 
@@ -322,20 +1014,24 @@ type SearchContentsByProviderParams struct {
 }
 
 type SearchContentsByProviderRow struct {
-	ID          pgtype.UUID      `json:"id"`
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
 }
 
 func (q *Queries) SearchContentsByProvider(ctx context.Context, arg SearchContentsByProviderParams) ([]SearchContentsByProviderRow, error) {
@@ -364,6 +1060,10 @@ func (q *Queries) SearchContentsByProvider(ctx context.Context, arg SearchConten
 			&i.Reactions,
 			&i.ReadingTime,
 			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
 			&i.Tags,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -377,3 +1077,1159 @@ func (q *Queries) SearchContentsByProvider(ctx context.Context, arg SearchConten
 	}
 	return items, nil
 }
+
+const listContentsForRescore = `-- name: ListContentsForRescore :many
+SELECT id, provider, type, published_at, views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score
+FROM contents
+WHERE id > $1
+ORDER BY id
+LIMIT $2::int
+`
+
+type ListContentsForRescoreParams struct {
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListContentsForRescoreRow struct {
+	ID              pgtype.UUID      `json:"id"`
+	Provider        string           `json:"provider"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+}
+
+func (q *Queries) ListContentsForRescore(ctx context.Context, arg ListContentsForRescoreParams) ([]ListContentsForRescoreRow, error) {
+	rows, err := q.db.Query(ctx, listContentsForRescore, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListContentsForRescoreRow{}
+	for rows.Next() {
+		var i ListContentsForRescoreRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Provider,
+			&i.Type,
+			&i.PublishedAt,
+			&i.Views,
+			&i.Likes,
+			&i.Reactions,
+			&i.ReadingTime,
+			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateContentScore = `-- name: UpdateContentScore :exec
+UPDATE contents
+SET score = $1,
+    base_score = $2,
+    type_multiplier = $3,
+    freshness_score = $4,
+    engagement_score = $5
+WHERE id = $6
+`
+
+type UpdateContentScoreParams struct {
+	Score           pgtype.Numeric `json:"score"`
+	BaseScore       float64        `json:"base_score"`
+	TypeMultiplier  float64        `json:"type_multiplier"`
+	FreshnessScore  float64        `json:"freshness_score"`
+	EngagementScore float64        `json:"engagement_score"`
+	ID              pgtype.UUID    `json:"id"`
+}
+
+func (q *Queries) UpdateContentScore(ctx context.Context, arg UpdateContentScoreParams) error {
+	_, err := q.db.Exec(ctx, updateContentScore,
+		arg.Score,
+		arg.BaseScore,
+		arg.TypeMultiplier,
+		arg.FreshnessScore,
+		arg.EngagementScore,
+		arg.ID,
+	)
+	return err
+}
+
+const facetContentTypes = `-- name: FacetContentTypes :many
+SELECT type::text AS value, COUNT(*) AS count
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+GROUP BY type
+ORDER BY count DESC, value
+`
+
+type FacetContentTypesParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+}
+
+type FacetContentTypesRow struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) FacetContentTypes(ctx context.Context, arg FacetContentTypesParams) ([]FacetContentTypesRow, error) {
+	rows, err := q.db.Query(ctx, facetContentTypes,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FacetContentTypesRow{}
+	for rows.Next() {
+		var i FacetContentTypesRow
+		if err := rows.Scan(&i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const facetProviders = `-- name: FacetProviders :many
+SELECT provider::text AS value, COUNT(*) AS count
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+GROUP BY provider
+ORDER BY count DESC, value
+`
+
+type FacetProvidersParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+}
+
+type FacetProvidersRow struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) FacetProviders(ctx context.Context, arg FacetProvidersParams) ([]FacetProvidersRow, error) {
+	rows, err := q.db.Query(ctx, facetProviders,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FacetProvidersRow{}
+	for rows.Next() {
+		var i FacetProvidersRow
+		if err := rows.Scan(&i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const facetTags = `-- name: FacetTags :many
+SELECT tag::text AS value, COUNT(*) AS count
+FROM contents, unnest(tags) AS tag
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+GROUP BY tag
+ORDER BY count DESC, value
+LIMIT $14::int
+`
+
+type FacetTagsParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+	FacetSize      int32            `json:"facet_size"`
+}
+
+type FacetTagsRow struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) FacetTags(ctx context.Context, arg FacetTagsParams) ([]FacetTagsRow, error) {
+	rows, err := q.db.Query(ctx, facetTags,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.FacetSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FacetTagsRow{}
+	for rows.Next() {
+		var i FacetTagsRow
+		if err := rows.Scan(&i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const facetPublished = `-- name: FacetPublished :many
+SELECT date_trunc($14::text, published_at)::timestamp AS bucket, COUNT(*) AS count
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+GROUP BY bucket
+ORDER BY bucket
+`
+
+type FacetPublishedParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+	Interval       string           `json:"interval"`
+}
+
+type FacetPublishedRow struct {
+	Bucket pgtype.Timestamp `json:"bucket"`
+	Count  int64            `json:"count"`
+}
+
+func (q *Queries) FacetPublished(ctx context.Context, arg FacetPublishedParams) ([]FacetPublishedRow, error) {
+	rows, err := q.db.Query(ctx, facetPublished,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.Interval,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FacetPublishedRow{}
+	for rows.Next() {
+		var i FacetPublishedRow
+		if err := rows.Scan(&i.Bucket, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const facetViewRanges = `-- name: FacetViewRanges :many
+SELECT width_bucket(COALESCE(views, 0), $14::int[])::int AS bucket, COUNT(*) AS count
+FROM contents
+WHERE (
+        $1::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+    )
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      $1::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', $1::text)
+                  )
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+GROUP BY bucket
+ORDER BY bucket
+`
+
+type FacetViewRangesParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+	Bounds         []int32          `json:"bounds"`
+}
+
+type FacetViewRangesRow struct {
+	Bucket int32 `json:"bucket"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) FacetViewRanges(ctx context.Context, arg FacetViewRangesParams) ([]FacetViewRangesRow, error) {
+	rows, err := q.db.Query(ctx, facetViewRanges,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.Bounds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FacetViewRangesRow{}
+	for rows.Next() {
+		var i FacetViewRangesRow
+		if err := rows.Scan(&i.Bucket, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fuzzySearchContents = `-- name: FuzzySearchContents :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE $1::text <% lower(title)
+    AND (
+        cardinality($2::text[]) = 0 OR 
+        ($3::text = 'all' AND tags @> $2::text[]) OR 
+        ($3::text <> 'all' AND tags && $2::text[])
+    )
+    AND (
+        cardinality($4::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && $4::text[])
+    )
+    AND (
+        cardinality($5::text[]) = 0 OR 
+        type = ANY($5::text[])
+    )
+    AND (
+        cardinality($6::text[]) = 0 OR 
+        provider = ANY($6::text[])
+    )
+    AND (
+        $7::timestamp IS NULL OR 
+        published_at >= $7::timestamp
+    )
+    AND (
+        $8::timestamp IS NULL OR 
+        published_at <= $8::timestamp
+    )
+    AND COALESCE(views, 0) >= $9::int
+    AND COALESCE(likes, 0) >= $10::int
+    AND COALESCE(reactions, 0) >= $11::int
+    AND (
+        $12::int = 0 OR 
+        COALESCE(reading_time, 0) <= $12::int
+    )
+    AND (
+        NOT $13::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND $1::text <% lower(title)
+                  AND (
+                      cardinality($2::text[]) = 0 OR 
+                      ($3::text = 'all' AND tags @> $2::text[]) OR 
+                      ($3::text <> 'all' AND tags && $2::text[])
+                  )
+                  AND (
+                      cardinality($4::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && $4::text[])
+                  )
+                  AND (
+                      cardinality($5::text[]) = 0 OR 
+                      type = ANY($5::text[])
+                  )
+                  AND (
+                      cardinality($6::text[]) = 0 OR 
+                      provider = ANY($6::text[])
+                  )
+                  AND (
+                      $7::timestamp IS NULL OR 
+                      published_at >= $7::timestamp
+                  )
+                  AND (
+                      $8::timestamp IS NULL OR 
+                      published_at <= $8::timestamp
+                  )
+                  AND COALESCE(views, 0) >= $9::int
+                  AND COALESCE(likes, 0) >= $10::int
+                  AND COALESCE(reactions, 0) >= $11::int
+                  AND (
+                      $12::int = 0 OR 
+                      COALESCE(reading_time, 0) <= $12::int
+                  )
+              )
+        )
+    )
+ORDER BY word_similarity($1::text, lower(title)) DESC, score DESC
+LIMIT $14::int
+`
+
+type FuzzySearchContentsParams struct {
+	Query          string           `json:"query"`
+	Tags           []string         `json:"tags"`
+	TagMode        string           `json:"tag_mode"`
+	ExcludeTags    []string         `json:"exclude_tags"`
+	ContentTypes   []string         `json:"content_types"`
+	Providers      []string         `json:"providers"`
+	PublishedFrom  pgtype.Timestamp `json:"published_from"`
+	PublishedTo    pgtype.Timestamp `json:"published_to"`
+	MinViews       int32            `json:"min_views"`
+	MinLikes       int32            `json:"min_likes"`
+	MinReactions   int32            `json:"min_reactions"`
+	MaxReadingTime int32            `json:"max_reading_time"`
+	Dedup          bool             `json:"dedup"`
+	ResultLimit    int32            `json:"result_limit"`
+}
+
+type FuzzySearchContentsRow struct {
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) FuzzySearchContents(ctx context.Context, arg FuzzySearchContentsParams) ([]FuzzySearchContentsRow, error) {
+	rows, err := q.db.Query(ctx, fuzzySearchContents,
+		arg.Query,
+		arg.Tags,
+		arg.TagMode,
+		arg.ExcludeTags,
+		arg.ContentTypes,
+		arg.Providers,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.MinViews,
+		arg.MinLikes,
+		arg.MinReactions,
+		arg.MaxReadingTime,
+		arg.Dedup,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FuzzySearchContentsRow{}
+	for rows.Next() {
+		var i FuzzySearchContentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Provider,
+			&i.Title,
+			&i.Type,
+			&i.PublishedAt,
+			&i.Views,
+			&i.Likes,
+			&i.Reactions,
+			&i.ReadingTime,
+			&i.Score,
+			&i.BaseScore,
+			&i.TypeMultiplier,
+			&i.FreshnessScore,
+			&i.EngagementScore,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSearchTerms = `-- name: ListSearchTerms :many
+SELECT term::text AS term, COUNT(*)::bigint AS frequency
+FROM (
+    SELECT regexp_split_to_table(lower(title), '[^[:alnum:]]+') AS term FROM contents
+    UNION ALL
+    SELECT lower(tag) FROM contents, unnest(tags) AS tag
+) AS terms
+WHERE term <> ''
+GROUP BY term
+ORDER BY frequency DESC, term
+LIMIT $1::int
+`
+
+type ListSearchTermsRow struct {
+	Term      string `json:"term"`
+	Frequency int64  `json:"frequency"`
+}
+
+func (q *Queries) ListSearchTerms(ctx context.Context, termLimit int32) ([]ListSearchTermsRow, error) {
+	rows, err := q.db.Query(ctx, listSearchTerms, termLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSearchTermsRow{}
+	for rows.Next() {
+		var i ListSearchTermsRow
+		if err := rows.Scan(&i.Term, &i.Frequency); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContentsForSuggest = `-- name: ListContentsForSuggest :many
+SELECT id, title, views, likes, reactions, tags
+FROM contents
+WHERE id > $1
+ORDER BY id
+LIMIT $2::int
+`
+
+type ListContentsForSuggestParams struct {
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListContentsForSuggestRow struct {
+	ID        pgtype.UUID `json:"id"`
+	Title     string      `json:"title"`
+	Views     pgtype.Int4 `json:"views"`
+	Likes     pgtype.Int4 `json:"likes"`
+	Reactions pgtype.Int4 `json:"reactions"`
+	Tags      []string    `json:"tags"`
+}
+
+func (q *Queries) ListContentsForSuggest(ctx context.Context, arg ListContentsForSuggestParams) ([]ListContentsForSuggestRow, error) {
+	rows, err := q.db.Query(ctx, listContentsForSuggest, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListContentsForSuggestRow{}
+	for rows.Next() {
+		var i ListContentsForSuggestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Views,
+			&i.Likes,
+			&i.Reactions,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const highlightContents = `-- name: HighlightContents :many
+SELECT id, ts_headline(
+           'simple', title, to_tsquery('simple', $1::text),
+           'HighlightAll=true, StartSel=' || chr(57344) || ', StopSel=' || chr(57345)
+       )::text AS headline
+FROM contents
+WHERE id = ANY($2::uuid[])
+`
+
+type HighlightContentsParams struct {
+	HighlightQuery string        `json:"highlight_query"`
+	Ids            []pgtype.UUID `json:"ids"`
+}
+
+type HighlightContentsRow struct {
+	ID       pgtype.UUID `json:"id"`
+	Headline string      `json:"headline"`
+}
+
+func (q *Queries) HighlightContents(ctx context.Context, arg HighlightContentsParams) ([]HighlightContentsRow, error) {
+	rows, err := q.db.Query(ctx, highlightContents, arg.HighlightQuery, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []HighlightContentsRow{}
+	for rows.Next() {
+		var i HighlightContentsRow
+		if err := rows.Scan(&i.ID, &i.Headline); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
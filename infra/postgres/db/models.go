@@ -9,19 +9,23 @@ import (
 )
 
 type Content struct {
-	ID          pgtype.UUID      `json:"id"`
-	ExternalID  string           `json:"external_id"`
-	Provider    string           `json:"provider"`
-	Title       string           `json:"title"`
-	Type        string           `json:"type"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	RawData     []byte           `json:"raw_data"`
-	Views       pgtype.Int4      `json:"views"`
-	Likes       pgtype.Int4      `json:"likes"`
-	Reactions   pgtype.Int4      `json:"reactions"`
-	ReadingTime pgtype.Int4      `json:"reading_time"`
-	Score       pgtype.Numeric   `json:"score"`
-	Tags        []string         `json:"tags"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID              pgtype.UUID      `json:"id"`
+	ExternalID      string           `json:"external_id"`
+	Provider        string           `json:"provider"`
+	Title           string           `json:"title"`
+	Type            string           `json:"type"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	RawData         []byte           `json:"raw_data"`
+	Views           pgtype.Int4      `json:"views"`
+	Likes           pgtype.Int4      `json:"likes"`
+	Reactions       pgtype.Int4      `json:"reactions"`
+	ReadingTime     pgtype.Int4      `json:"reading_time"`
+	Score           pgtype.Numeric   `json:"score"`
+	BaseScore       float64          `json:"base_score"`
+	TypeMultiplier  float64          `json:"type_multiplier"`
+	FreshnessScore  float64          `json:"freshness_score"`
+	EngagementScore float64          `json:"engagement_score"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
 }
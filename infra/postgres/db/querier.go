@@ -11,12 +11,31 @@ import (
 )
 
 type Querier interface {
+	AssignContentCluster(ctx context.Context, arg AssignContentClusterParams) error
 	CountSearchContents(ctx context.Context, arg CountSearchContentsParams) (int64, error)
 	DeleteContent(ctx context.Context, id pgtype.UUID) error
+	FacetContentTypes(ctx context.Context, arg FacetContentTypesParams) ([]FacetContentTypesRow, error)
+	FacetProviders(ctx context.Context, arg FacetProvidersParams) ([]FacetProvidersRow, error)
+	FacetPublished(ctx context.Context, arg FacetPublishedParams) ([]FacetPublishedRow, error)
+	FacetTags(ctx context.Context, arg FacetTagsParams) ([]FacetTagsRow, error)
+	FacetViewRanges(ctx context.Context, arg FacetViewRangesParams) ([]FacetViewRangesRow, error)
+	FuzzySearchContents(ctx context.Context, arg FuzzySearchContentsParams) ([]FuzzySearchContentsRow, error)
 	GetContentByExternalID(ctx context.Context, arg GetContentByExternalIDParams) (GetContentByExternalIDRow, error)
 	GetContentByID(ctx context.Context, id pgtype.UUID) (GetContentByIDRow, error)
+	GetContentEngagement(ctx context.Context, arg GetContentEngagementParams) ([]GetContentEngagementRow, error)
+	HighlightContents(ctx context.Context, arg HighlightContentsParams) ([]HighlightContentsRow, error)
+	InsertSearchEvent(ctx context.Context, arg InsertSearchEventParams) error
+	ListClusterAlternates(ctx context.Context, contentIds []pgtype.UUID) ([]ListClusterAlternatesRow, error)
+	ListContentsForRescore(ctx context.Context, arg ListContentsForRescoreParams) ([]ListContentsForRescoreRow, error)
+	ListContentsForSuggest(ctx context.Context, arg ListContentsForSuggestParams) ([]ListContentsForSuggestRow, error)
+	ListSearchTerms(ctx context.Context, termLimit int32) ([]ListSearchTermsRow, error)
 	SearchContents(ctx context.Context, arg SearchContentsParams) ([]SearchContentsRow, error)
+	SearchContentsAfter(ctx context.Context, arg SearchContentsAfterParams) ([]SearchContentsAfterRow, error)
+	SearchContentsBefore(ctx context.Context, arg SearchContentsBeforeParams) ([]SearchContentsBeforeRow, error)
+	SearchContentsByProvider(ctx context.Context, arg SearchContentsByProviderParams) ([]SearchContentsByProviderRow, error)
+	UpdateContentScore(ctx context.Context, arg UpdateContentScoreParams) error
 	UpsertContent(ctx context.Context, arg UpsertContentParams) (UpsertContentRow, error)
+	UpsertContentEventDaily(ctx context.Context, arg UpsertContentEventDailyParams) error
 }
 
 var _ Querier = (*Queries)(nil)
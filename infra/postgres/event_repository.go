@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"search-engine/app/events"
+	"search-engine/domain"
+	"search-engine/infra/postgres/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type eventRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewEventRepository(database *PostgresDB) events.Repository {
+	return &eventRepository{
+		pool:    database.Pool,
+		queries: db.New(database.Pool),
+	}
+}
+
+type rollupKey struct {
+	contentID uuid.UUID
+	day       time.Time
+}
+
+func (r *eventRepository) Record(ctx context.Context, evts []domain.Event) (int, error) {
+	if len(evts) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin event batch: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	rollups := make(map[rollupKey]*db.UpsertContentEventDailyParams)
+	for _, event := range evts {
+		err := qtx.InsertSearchEvent(ctx, db.InsertSearchEventParams{
+			RequestID: event.RequestID,
+			ContentID: pgtype.UUID{Bytes: event.ContentID, Valid: true},
+			EventType: string(event.Type),
+			Position:  int32(event.Position),
+			Query:     event.Query,
+			CreatedAt: pgtype.Timestamp{Time: event.CreatedAt, Valid: true},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert event: %w", err)
+		}
+
+		key := rollupKey{contentID: event.ContentID, day: event.CreatedAt.UTC().Truncate(24 * time.Hour)}
+		rollup, ok := rollups[key]
+		if !ok {
+			rollup = &db.UpsertContentEventDailyParams{
+				ContentID: pgtype.UUID{Bytes: key.contentID, Valid: true},
+				Day:       pgtype.Date{Time: key.day, Valid: true},
+			}
+			rollups[key] = rollup
+		}
+		switch event.Type {
+		case domain.EventImpression:
+			rollup.Impressions++
+			rollup.Examinations += domain.PositionPropensity(event.Position)
+		case domain.EventClick:
+			rollup.Clicks++
+		}
+	}
+
+	for _, rollup := range rollups {
+		if err := qtx.UpsertContentEventDaily(ctx, *rollup); err != nil {
+			return 0, fmt.Errorf("failed to update event rollup: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit event batch: %w", err)
+	}
+
+	return len(evts), nil
+}
+
+func (r *eventRepository) Engagement(ctx context.Context, contentIDs []uuid.UUID, since time.Time) (map[uuid.UUID]domain.Engagement, error) {
+	ids := make([]pgtype.UUID, len(contentIDs))
+	for i, id := range contentIDs {
+		ids[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	rows, err := r.queries.GetContentEngagement(ctx, db.GetContentEngagementParams{
+		ContentIds: ids,
+		Since:      pgtype.Date{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content engagement: %w", err)
+	}
+
+	engagement := make(map[uuid.UUID]domain.Engagement, len(rows))
+	for _, row := range rows {
+		engagement[uuidFromPgtype(row.ContentID)] = domain.Engagement{
+			Impressions:  row.Impressions,
+			Clicks:       row.Clicks,
+			Examinations: row.Examinations,
+		}
+	}
+
+	return engagement, nil
+}
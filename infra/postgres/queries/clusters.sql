@@ -0,0 +1,35 @@
+-- AssignContentCluster joins the cluster of an existing duplicate: the same
+-- title key published within the window, or the same canonical URL.
+-- Otherwise the content starts its own cluster.
+-- name: AssignContentCluster :exec
+INSERT INTO content_clusters (content_id, cluster_id, title_key, canonical_url, published_at)
+SELECT c.id,
+       COALESCE((
+           SELECT other.cluster_id
+           FROM content_clusters other
+           WHERE other.content_id <> c.id
+             AND (
+                 (other.title_key = @title_key::text AND
+                  other.published_at BETWEEN @published_at::timestamp - make_interval(secs => @window_seconds::float8)
+                                         AND @published_at::timestamp + make_interval(secs => @window_seconds::float8))
+                 OR (@canonical_url::text <> '' AND other.canonical_url = @canonical_url::text)
+             )
+           ORDER BY other.cluster_id
+           LIMIT 1
+       ), c.id),
+       @title_key::text, @canonical_url::text, @published_at::timestamp
+FROM contents c
+WHERE c.provider = @provider AND c.external_id = @external_id
+ON CONFLICT (content_id) DO UPDATE SET
+    cluster_id = EXCLUDED.cluster_id,
+    title_key = EXCLUDED.title_key,
+    canonical_url = EXCLUDED.canonical_url,
+    published_at = EXCLUDED.published_at;
+
+-- name: ListClusterAlternates :many
+SELECT mine.content_id, c.provider, c.external_id
+FROM content_clusters mine
+JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+JOIN contents c ON c.id = other.content_id
+WHERE mine.content_id = ANY(@content_ids::uuid[])
+ORDER BY mine.content_id, c.score DESC, c.id;
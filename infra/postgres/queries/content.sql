@@ -1,6 +1,10 @@
+-- With dedup, a content is hidden when a better-scored duplicate passes the
+-- same filters. Unqualified columns in that subquery refer to the duplicate.
 -- name: SearchContents :many
 SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE (
         @query::text = '' OR 
@@ -8,17 +12,308 @@ WHERE (
     )
     AND (
         cardinality(@tags::text[]) = 0 OR 
-        tags && @tags::text[]
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
     )
     AND (
         cardinality(@content_types::text[]) = 0 OR 
         type = ANY(@content_types::text[])
     )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
 ORDER BY
-    CASE WHEN @sort_by::varchar = 'popularity' THEN (views + likes + reactions) END DESC,
-    score DESC
+    CASE WHEN @sort_by::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END DESC,
+    score DESC, published_at DESC, id DESC
 LIMIT @page_limit::int OFFSET @page_offset::int;
 
+-- SearchContentsAfter and SearchContentsBefore page by keyset from a cursor.
+-- Before walks the order backwards; callers reverse its rows.
+-- name: SearchContentsAfter :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+    AND (
+        (@sort_by::varchar = 'popularity' AND
+            (COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0), score, published_at, id) <
+            (@cursor_popularity::bigint, @cursor_score::numeric, @cursor_published_at::timestamp, @cursor_id::uuid))
+        OR
+        (@sort_by::varchar <> 'popularity' AND
+            (score, published_at, id) <
+            (@cursor_score::numeric, @cursor_published_at::timestamp, @cursor_id::uuid))
+    )
+ORDER BY
+    CASE WHEN @sort_by::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END DESC,
+    score DESC, published_at DESC, id DESC
+LIMIT @page_limit::int;
+
+-- name: SearchContentsBefore :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+    AND (
+        (@sort_by::varchar = 'popularity' AND
+            (COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0), score, published_at, id) >
+            (@cursor_popularity::bigint, @cursor_score::numeric, @cursor_published_at::timestamp, @cursor_id::uuid))
+        OR
+        (@sort_by::varchar <> 'popularity' AND
+            (score, published_at, id) >
+            (@cursor_score::numeric, @cursor_published_at::timestamp, @cursor_id::uuid))
+    )
+ORDER BY
+    CASE WHEN @sort_by::varchar = 'popularity' THEN COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0) END ASC,
+    score ASC, published_at ASC, id ASC
+LIMIT @page_limit::int;
+
 -- name: CountSearchContents :one
 SELECT COUNT(*)
 FROM contents
@@ -28,44 +323,149 @@ WHERE (
     )
     AND (
         cardinality(@tags::text[]) = 0 OR 
-        tags && @tags::text[]
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
     )
     AND (
         cardinality(@content_types::text[]) = 0 OR 
         type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
     );
 
 -- name: UpsertContent :one
-INSERT INTO contents (
-    external_id, provider, title, type, published_at, raw_data,
-    views, likes, reactions, reading_time, score, tags
-) VALUES (
-    @external_id, @provider, @title, @type, @published_at, @raw_data,
-    @views, @likes, @reactions, @reading_time, @score, @tags
+-- changed tells whether the content data differs from the stored row, so
+-- rewriting unchanged contents can be told apart from real inserts and updates.
+WITH upserted AS (
+    INSERT INTO contents (
+        id, external_id, provider, title, type, published_at, raw_data,
+        views, likes, reactions, reading_time, score,
+        base_score, type_multiplier, freshness_score, engagement_score, tags
+    ) VALUES (
+        @id, @external_id, @provider, @title, @type, @published_at, @raw_data,
+        @views, @likes, @reactions, @reading_time, @score,
+        @base_score, @type_multiplier, @freshness_score, @engagement_score, @tags
+    )
+    ON CONFLICT (provider, external_id)
+    DO UPDATE SET
+        title = EXCLUDED.title,
+        published_at = EXCLUDED.published_at,
+        raw_data = EXCLUDED.raw_data,
+        views = EXCLUDED.views,
+        likes = EXCLUDED.likes,
+        reactions = EXCLUDED.reactions,
+        reading_time = EXCLUDED.reading_time,
+        score = EXCLUDED.score,
+        base_score = EXCLUDED.base_score,
+        type_multiplier = EXCLUDED.type_multiplier,
+        freshness_score = EXCLUDED.freshness_score,
+        engagement_score = EXCLUDED.engagement_score,
+        tags = EXCLUDED.tags,
+        updated_at = NOW()
+    RETURNING id, created_at, updated_at,
+              title, type, published_at, raw_data, views, likes, reactions, reading_time, tags
+), previous AS (
+    SELECT title, type, published_at, raw_data, views, likes, reactions, reading_time, tags
+    FROM contents
+    WHERE provider = @provider AND external_id = @external_id
 )
-ON CONFLICT (provider, external_id)
-DO UPDATE SET
-    title = EXCLUDED.title,
-    published_at = EXCLUDED.published_at,
-    raw_data = EXCLUDED.raw_data,
-    views = EXCLUDED.views,
-    likes = EXCLUDED.likes,
-    reactions = EXCLUDED.reactions,
-    reading_time = EXCLUDED.reading_time,
-    score = EXCLUDED.score,
-    tags = EXCLUDED.tags,
-    updated_at = NOW()
-RETURNING id, created_at, updated_at;
+SELECT upserted.id, upserted.created_at, upserted.updated_at,
+       NOT EXISTS (
+           SELECT 1
+           FROM previous
+           WHERE (previous.title, previous.type, previous.published_at, previous.raw_data, previous.views,
+                  previous.likes, previous.reactions, previous.reading_time, previous.tags)
+                 IS NOT DISTINCT FROM
+                 (upserted.title, upserted.type, upserted.published_at, upserted.raw_data, upserted.views,
+                  upserted.likes, upserted.reactions, upserted.reading_time, upserted.tags)
+       ) AS changed
+FROM upserted;
 
 -- name: GetContentByID :one
-SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, created_at, updated_at
+SELECT id, external_id, provider, title, type, published_at, raw_data,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE id = @id;
 
 -- name: GetContentByExternalID :one
-SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, created_at, updated_at
+SELECT id, external_id, provider, title, type, published_at, raw_data,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE provider = @provider AND external_id = @external_id;
 
@@ -74,12 +474,614 @@ DELETE FROM contents WHERE id = @id;
 
 -- name: SearchContentsByProvider :many
 SELECT id, external_id, provider, title, type, published_at,
-       views, likes, reactions, reading_time, score, tags, created_at, updated_at
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
 FROM contents
 WHERE provider = @provider::varchar
   AND (
     @query::text = '' OR
     to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
   )
-ORDER BY score DESC
+ORDER BY score DESC, published_at DESC, id DESC
 LIMIT @page_limit::int OFFSET @page_offset::int;
+
+-- name: ListContentsForRescore :many
+SELECT id, provider, type, published_at, views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score
+FROM contents
+WHERE id > @after_id
+ORDER BY id
+LIMIT @batch_size::int;
+
+-- name: UpdateContentScore :exec
+UPDATE contents
+SET score = @score,
+    base_score = @base_score,
+    type_multiplier = @type_multiplier,
+    freshness_score = @freshness_score,
+    engagement_score = @engagement_score
+WHERE id = @id;
+
+-- name: FacetContentTypes :many
+SELECT type::text AS value, COUNT(*) AS count
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+GROUP BY type
+ORDER BY count DESC, value;
+
+-- name: FacetProviders :many
+SELECT provider::text AS value, COUNT(*) AS count
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+GROUP BY provider
+ORDER BY count DESC, value;
+
+-- name: FacetTags :many
+SELECT tag::text AS value, COUNT(*) AS count
+FROM contents, unnest(tags) AS tag
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+GROUP BY tag
+ORDER BY count DESC, value
+LIMIT @facet_size::int;
+
+-- name: FacetPublished :many
+SELECT date_trunc(@interval::text, published_at)::timestamp AS bucket, COUNT(*) AS count
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: FacetViewRanges :many
+SELECT width_bucket(COALESCE(views, 0), @bounds::int[])::int AS bucket, COUNT(*) AS count
+FROM contents
+WHERE (
+        @query::text = '' OR 
+        to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+    )
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND (
+                      @query::text = '' OR 
+                      to_tsvector('english', title) @@ plainto_tsquery('english', @query::text)
+                  )
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: FuzzySearchContents :many
+SELECT id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at
+FROM contents
+WHERE @query::text <% lower(title)
+    AND (
+        cardinality(@tags::text[]) = 0 OR 
+        (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+        (@tag_mode::text <> 'all' AND tags && @tags::text[])
+    )
+    AND (
+        cardinality(@exclude_tags::text[]) = 0 OR 
+        NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+    )
+    AND (
+        cardinality(@content_types::text[]) = 0 OR 
+        type = ANY(@content_types::text[])
+    )
+    AND (
+        cardinality(@providers::text[]) = 0 OR 
+        provider = ANY(@providers::text[])
+    )
+    AND (
+        sqlc.narg(published_from)::timestamp IS NULL OR 
+        published_at >= sqlc.narg(published_from)::timestamp
+    )
+    AND (
+        sqlc.narg(published_to)::timestamp IS NULL OR 
+        published_at <= sqlc.narg(published_to)::timestamp
+    )
+    AND COALESCE(views, 0) >= @min_views::int
+    AND COALESCE(likes, 0) >= @min_likes::int
+    AND COALESCE(reactions, 0) >= @min_reactions::int
+    AND (
+        @max_reading_time::int = 0 OR 
+        COALESCE(reading_time, 0) <= @max_reading_time::int
+    )
+    AND (
+        NOT @dedup::boolean OR 
+        NOT EXISTS (
+            SELECT 1
+            FROM content_clusters mine
+            JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+            WHERE mine.content_id = contents.id
+              AND EXISTS (
+                SELECT 1
+                FROM contents better
+                WHERE better.id = other.content_id
+                  AND (better.score, better.id) > (contents.score, contents.id)
+                  AND @query::text <% lower(title)
+                  AND (
+                      cardinality(@tags::text[]) = 0 OR 
+                      (@tag_mode::text = 'all' AND tags @> @tags::text[]) OR 
+                      (@tag_mode::text <> 'all' AND tags && @tags::text[])
+                  )
+                  AND (
+                      cardinality(@exclude_tags::text[]) = 0 OR 
+                      NOT (COALESCE(tags, '{}') && @exclude_tags::text[])
+                  )
+                  AND (
+                      cardinality(@content_types::text[]) = 0 OR 
+                      type = ANY(@content_types::text[])
+                  )
+                  AND (
+                      cardinality(@providers::text[]) = 0 OR 
+                      provider = ANY(@providers::text[])
+                  )
+                  AND (
+                      sqlc.narg(published_from)::timestamp IS NULL OR 
+                      published_at >= sqlc.narg(published_from)::timestamp
+                  )
+                  AND (
+                      sqlc.narg(published_to)::timestamp IS NULL OR 
+                      published_at <= sqlc.narg(published_to)::timestamp
+                  )
+                  AND COALESCE(views, 0) >= @min_views::int
+                  AND COALESCE(likes, 0) >= @min_likes::int
+                  AND COALESCE(reactions, 0) >= @min_reactions::int
+                  AND (
+                      @max_reading_time::int = 0 OR 
+                      COALESCE(reading_time, 0) <= @max_reading_time::int
+                  )
+              )
+        )
+    )
+ORDER BY word_similarity(@query::text, lower(title)) DESC, score DESC
+LIMIT @result_limit::int;
+
+-- name: ListSearchTerms :many
+SELECT term::text AS term, COUNT(*)::bigint AS frequency
+FROM (
+    SELECT regexp_split_to_table(lower(title), '[^[:alnum:]]+') AS term FROM contents
+    UNION ALL
+    SELECT lower(tag) FROM contents, unnest(tags) AS tag
+) AS terms
+WHERE term <> ''
+GROUP BY term
+ORDER BY frequency DESC, term
+LIMIT @term_limit::int;
+
+-- name: ListContentsForSuggest :many
+SELECT id, title, views, likes, reactions, tags
+FROM contents
+WHERE id > @after_id
+ORDER BY id
+LIMIT @batch_size::int;
+
+-- name: HighlightContents :many
+SELECT id, ts_headline(
+           'simple', title, to_tsquery('simple', @highlight_query::text),
+           'HighlightAll=true, StartSel=' || chr(57344) || ', StopSel=' || chr(57345)
+       )::text AS headline
+FROM contents
+WHERE id = ANY(@ids::uuid[]);
@@ -0,0 +1,28 @@
+-- name: InsertSearchEvent :exec
+INSERT INTO search_events (
+    request_id, content_id, event_type, position, query, created_at
+) VALUES (
+    @request_id, @content_id, @event_type, @position, @query, @created_at
+);
+
+-- name: UpsertContentEventDaily :exec
+INSERT INTO content_event_daily (
+    content_id, day, impressions, clicks, examinations
+) VALUES (
+    @content_id, @day, @impressions, @clicks, @examinations
+)
+ON CONFLICT (content_id, day)
+DO UPDATE SET
+    impressions = content_event_daily.impressions + EXCLUDED.impressions,
+    clicks = content_event_daily.clicks + EXCLUDED.clicks,
+    examinations = content_event_daily.examinations + EXCLUDED.examinations;
+
+-- name: GetContentEngagement :many
+SELECT content_id,
+       SUM(impressions)::bigint AS impressions,
+       SUM(clicks)::bigint AS clicks,
+       SUM(examinations)::float8 AS examinations
+FROM content_event_daily
+WHERE content_id = ANY(@content_ids::uuid[])
+  AND day >= @since::date
+GROUP BY content_id;
@@ -2,35 +2,58 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
+	"time"
 
 	"search-engine/app/search"
 	"search-engine/domain"
 	"search-engine/infra/postgres/db"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type repository struct {
+	pool    *pgxpool.Pool
 	queries *db.Queries
 }
 
 func NewRepository(database *PostgresDB) search.Repository {
 	return &repository{
+		pool:    database.Pool,
 		queries: db.New(database.Pool),
 	}
 }
 
-func (r *repository) Search(ctx context.Context, query string, tags []string, contentTypes []string, sortBy string, page, perPage int) ([]domain.Content, int64, error) {
+func (r *repository) Search(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, page, perPage int) ([]domain.Content, int64, error) {
+	if parsed, ok := parseStructured(query); ok {
+		return r.searchStructured(ctx, parsed, filter, sortBy, page, perPage)
+	}
+
+	f := filterParams(query, filter)
 	params := db.SearchContentsParams{
-		Query:        query,
-		Tags:         tags,
-		ContentTypes: contentTypes,
-		SortBy:       sortBy,
-		PageOffset:   int32((page - 1) * perPage),
-		PageLimit:    int32(perPage),
+		Query:          f.Query,
+		Tags:           f.Tags,
+		TagMode:        f.TagMode,
+		ExcludeTags:    f.ExcludeTags,
+		ContentTypes:   f.ContentTypes,
+		Providers:      f.Providers,
+		PublishedFrom:  f.PublishedFrom,
+		PublishedTo:    f.PublishedTo,
+		MinViews:       f.MinViews,
+		MinLikes:       f.MinLikes,
+		MinReactions:   f.MinReactions,
+		MaxReadingTime: f.MaxReadingTime,
+		Dedup:          f.Dedup,
+		SortBy:         sortBy,
+		PageOffset:     int32((page - 1) * perPage),
+		PageLimit:      int32(perPage),
 	}
 
 	rows, err := r.queries.SearchContents(ctx, params)
@@ -38,61 +61,335 @@ func (r *repository) Search(ctx context.Context, query string, tags []string, co
 		return nil, 0, fmt.Errorf("failed to search contents: %w", err)
 	}
 
-	countParams := db.CountSearchContentsParams{
-		Query:        query,
-		Tags:         tags,
-		ContentTypes: contentTypes,
-	}
-	total, err := r.queries.CountSearchContents(ctx, countParams)
+	total, err := r.queries.CountSearchContents(ctx, f)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count search contents: %w", err)
 	}
 
 	contents := make([]domain.Content, len(rows))
 	for i, row := range rows {
-		score, _ := row.Score.Float64Value()
-		contents[i] = domain.Content{
-			ID:          uuidFromPgtype(row.ID),
-			ExternalID:  row.ExternalID,
-			Provider:    row.Provider,
-			Title:       row.Title,
-			Type:        domain.ContentType(row.Type),
-			PublishedAt: row.PublishedAt.Time,
-			Views:       int(row.Views.Int32),
-			Likes:       int(row.Likes.Int32),
-			Reactions:   int(row.Reactions.Int32),
-			ReadingTime: int(row.ReadingTime.Int32),
-			Score:       score.Float64,
-			Tags:        row.Tags,
-			CreatedAt:   row.CreatedAt.Time,
-			UpdatedAt:   row.UpdatedAt.Time,
+		contents[i] = contentFromSearchRow(row)
+	}
+
+	return contents, total, nil
+}
+
+// SearchKeyset returns up to limit contents following the keyset in result
+// order, or preceding it when paging backwards, and the total match count.
+func (r *repository) SearchKeyset(ctx context.Context, query string, filter domain.SearchFilter, sortBy string, keyset domain.Keyset, direction domain.CursorDirection, limit int) ([]domain.Content, int64, error) {
+	if parsed, ok := parseStructured(query); ok {
+		return r.searchKeysetStructured(ctx, parsed, filter, sortBy, keyset, direction, limit)
+	}
+
+	f := filterParams(query, filter)
+	params := db.SearchContentsAfterParams{
+		Query:             f.Query,
+		Tags:              f.Tags,
+		TagMode:           f.TagMode,
+		ExcludeTags:       f.ExcludeTags,
+		ContentTypes:      f.ContentTypes,
+		Providers:         f.Providers,
+		PublishedFrom:     f.PublishedFrom,
+		PublishedTo:       f.PublishedTo,
+		MinViews:          f.MinViews,
+		MinLikes:          f.MinLikes,
+		MinReactions:      f.MinReactions,
+		MaxReadingTime:    f.MaxReadingTime,
+		Dedup:             f.Dedup,
+		SortBy:            sortBy,
+		CursorPopularity:  keyset.Popularity,
+		CursorScore:       numericFromFloat(keyset.Score),
+		CursorPublishedAt: pgtype.Timestamp{Time: keyset.PublishedAt, Valid: true},
+		CursorID:          pgtype.UUID{Bytes: keyset.ID, Valid: true},
+		PageLimit:         int32(limit),
+	}
+
+	var contents []domain.Content
+	if direction == domain.CursorPrev {
+		rows, err := r.queries.SearchContentsBefore(ctx, db.SearchContentsBeforeParams(params))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to search contents by keyset: %w", err)
+		}
+		for _, row := range rows {
+			contents = append(contents, contentFromSearchRow(db.SearchContentsRow(row)))
+		}
+		reverseContents(contents)
+	} else {
+		rows, err := r.queries.SearchContentsAfter(ctx, params)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to search contents by keyset: %w", err)
+		}
+		for _, row := range rows {
+			contents = append(contents, contentFromSearchRow(db.SearchContentsRow(row)))
 		}
 	}
 
+	total, err := r.queries.CountSearchContents(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search contents: %w", err)
+	}
 	return contents, total, nil
 }
 
+func reverseContents(contents []domain.Content) {
+	for i, j := 0, len(contents)-1; i < j; i, j = i+1, j-1 {
+		contents[i], contents[j] = contents[j], contents[i]
+	}
+}
+
+func (r *repository) FuzzySearch(ctx context.Context, query string, filter domain.SearchFilter, limit int) ([]domain.Content, error) {
+	f := filterParams(strings.ToLower(query), filter)
+	rows, err := r.queries.FuzzySearchContents(ctx, db.FuzzySearchContentsParams{
+		Query:          f.Query,
+		Tags:           f.Tags,
+		TagMode:        f.TagMode,
+		ExcludeTags:    f.ExcludeTags,
+		ContentTypes:   f.ContentTypes,
+		Providers:      f.Providers,
+		PublishedFrom:  f.PublishedFrom,
+		PublishedTo:    f.PublishedTo,
+		MinViews:       f.MinViews,
+		MinLikes:       f.MinLikes,
+		MinReactions:   f.MinReactions,
+		MaxReadingTime: f.MaxReadingTime,
+		Dedup:          f.Dedup,
+		ResultLimit:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fuzzy search contents: %w", err)
+	}
+
+	contents := make([]domain.Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSearchRow(db.SearchContentsRow(row))
+	}
+	return contents, nil
+}
+
+func (r *repository) SearchTerms(ctx context.Context, limit int) (map[string]int, error) {
+	rows, err := r.queries.ListSearchTerms(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list search terms: %w", err)
+	}
+
+	terms := make(map[string]int, len(rows))
+	for _, row := range rows {
+		terms[row.Term] = int(row.Frequency)
+	}
+	return terms, nil
+}
+
+// Highlight returns titles with matched words wrapped in the markers of the
+// highlight package.
+func (r *repository) Highlight(ctx context.Context, ids []uuid.UUID, tsquery string) (map[uuid.UUID]string, error) {
+	pgIDs := make([]pgtype.UUID, len(ids))
+	for i, id := range ids {
+		pgIDs[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	rows, err := r.queries.HighlightContents(ctx, db.HighlightContentsParams{HighlightQuery: tsquery, Ids: pgIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to highlight contents: %w", err)
+	}
+
+	headlines := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		headlines[uuidFromPgtype(row.ID)] = row.Headline
+	}
+	return headlines, nil
+}
+
+func (r *repository) Facets(ctx context.Context, query string, filter domain.SearchFilter, req domain.FacetRequest) (*domain.Facets, error) {
+	if parsed, ok := parseStructured(query); ok {
+		return r.facetsStructured(ctx, parsed, filter, req)
+	}
+
+	f := filterParams(query, filter)
+	facets := &domain.Facets{}
+
+	if req.Has(domain.FacetType) {
+		rows, err := r.queries.FacetContentTypes(ctx, db.FacetContentTypesParams(f))
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet content types: %w", err)
+		}
+		for _, row := range rows {
+			facets.Types = append(facets.Types, domain.FacetBucket{Value: row.Value, Count: row.Count})
+		}
+	}
+
+	if req.Has(domain.FacetTags) {
+		rows, err := r.queries.FacetTags(ctx, db.FacetTagsParams{
+			Query:          f.Query,
+			Tags:           f.Tags,
+			TagMode:        f.TagMode,
+			ExcludeTags:    f.ExcludeTags,
+			ContentTypes:   f.ContentTypes,
+			Providers:      f.Providers,
+			PublishedFrom:  f.PublishedFrom,
+			PublishedTo:    f.PublishedTo,
+			MinViews:       f.MinViews,
+			MinLikes:       f.MinLikes,
+			MinReactions:   f.MinReactions,
+			MaxReadingTime: f.MaxReadingTime,
+			Dedup:          f.Dedup,
+			FacetSize:      int32(req.Size),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet tags: %w", err)
+		}
+		for _, row := range rows {
+			facets.Tags = append(facets.Tags, domain.FacetBucket{Value: row.Value, Count: row.Count})
+		}
+	}
+
+	if req.Has(domain.FacetProvider) {
+		rows, err := r.queries.FacetProviders(ctx, db.FacetProvidersParams(f))
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet providers: %w", err)
+		}
+		for _, row := range rows {
+			facets.Providers = append(facets.Providers, domain.FacetBucket{Value: row.Value, Count: row.Count})
+		}
+	}
+
+	if req.Has(domain.FacetPublished) {
+		rows, err := r.queries.FacetPublished(ctx, db.FacetPublishedParams{
+			Query:          f.Query,
+			Tags:           f.Tags,
+			TagMode:        f.TagMode,
+			ExcludeTags:    f.ExcludeTags,
+			ContentTypes:   f.ContentTypes,
+			Providers:      f.Providers,
+			PublishedFrom:  f.PublishedFrom,
+			PublishedTo:    f.PublishedTo,
+			MinViews:       f.MinViews,
+			MinLikes:       f.MinLikes,
+			MinReactions:   f.MinReactions,
+			MaxReadingTime: f.MaxReadingTime,
+			Dedup:          f.Dedup,
+			Interval:       req.Interval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet published dates: %w", err)
+		}
+		for _, row := range rows {
+			facets.Published = append(facets.Published, domain.FacetBucket{Value: domain.FormatFacetDate(row.Bucket.Time), Count: row.Count})
+		}
+	}
+
+	if req.Has(domain.FacetViews) {
+		// width_bucket returns 0 below the first bound, so passing the lower
+		// bounds from the second range on yields domain.ViewRanges indexes.
+		bounds := make([]int32, 0, len(domain.ViewRanges)-1)
+		for _, viewRange := range domain.ViewRanges[1:] {
+			bounds = append(bounds, int32(viewRange.Min))
+		}
+
+		rows, err := r.queries.FacetViewRanges(ctx, db.FacetViewRangesParams{
+			Query:          f.Query,
+			Tags:           f.Tags,
+			TagMode:        f.TagMode,
+			ExcludeTags:    f.ExcludeTags,
+			ContentTypes:   f.ContentTypes,
+			Providers:      f.Providers,
+			PublishedFrom:  f.PublishedFrom,
+			PublishedTo:    f.PublishedTo,
+			MinViews:       f.MinViews,
+			MinLikes:       f.MinLikes,
+			MinReactions:   f.MinReactions,
+			MaxReadingTime: f.MaxReadingTime,
+			Dedup:          f.Dedup,
+			Bounds:         bounds,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet view ranges: %w", err)
+		}
+		for _, row := range rows {
+			if int(row.Bucket) >= len(domain.ViewRanges) {
+				continue
+			}
+			facets.Views = append(facets.Views, domain.FacetBucket{Value: domain.ViewRanges[row.Bucket].Label, Count: row.Count})
+		}
+	}
+
+	return facets, nil
+}
+
 func (r *repository) Upsert(ctx context.Context, content *domain.Content) error {
-	params := db.UpsertContentParams{
-		ExternalID:  content.ExternalID,
-		Provider:    content.Provider,
-		Title:       content.Title,
-		Type:        string(content.Type),
-		PublishedAt: pgtype.Timestamp{Time: content.PublishedAt, Valid: true},
-		RawData:     content.RawData,
-		Views:       pgtype.Int4{Int32: int32(content.Views), Valid: true},
-		Likes:       pgtype.Int4{Int32: int32(content.Likes), Valid: true},
-		Reactions:   pgtype.Int4{Int32: int32(content.Reactions), Valid: true},
-		ReadingTime: pgtype.Int4{Int32: int32(content.ReadingTime), Valid: true},
-		Score:       floatToNumeric(content.Score),
-		Tags:        content.Tags,
+	row, err := r.queries.UpsertContent(ctx, upsertParams(content))
+	if err != nil {
+		return err
+	}
+	applyUpsertRow(content, row)
+	return nil
+}
+
+func (r *repository) UpsertBatch(ctx context.Context, contents []*domain.Content) (int, error) {
+	if len(contents) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin upsert batch: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	_, err := r.queries.UpsertContent(ctx, params)
-	return err
+	qtx := r.queries.WithTx(tx)
+	changed := 0
+	for _, content := range contents {
+		row, err := qtx.UpsertContent(ctx, upsertParams(content))
+		if err != nil {
+			return 0, fmt.Errorf("failed to upsert content %s/%s: %w", content.Provider, content.ExternalID, err)
+		}
+		applyUpsertRow(content, row)
+		if row.Changed {
+			changed++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit upsert batch: %w", err)
+	}
+
+	return changed, nil
+}
+
+// applyUpsertRow takes the stored ID and timestamps, since a row written
+// before IDs were derived from the provider item keeps the ID it had.
+func applyUpsertRow(content *domain.Content, row db.UpsertContentRow) {
+	content.ID = uuidFromPgtype(row.ID)
+	content.CreatedAt = row.CreatedAt.Time
+	content.UpdatedAt = row.UpdatedAt.Time
+}
+
+func upsertParams(content *domain.Content) db.UpsertContentParams {
+	return db.UpsertContentParams{
+		ID:              pgtype.UUID{Bytes: content.ID, Valid: true},
+		ExternalID:      content.ExternalID,
+		Provider:        content.Provider,
+		Title:           content.Title,
+		Type:            string(content.Type),
+		PublishedAt:     pgtype.Timestamp{Time: content.PublishedAt, Valid: true},
+		RawData:         content.RawData,
+		Views:           pgtype.Int4{Int32: int32(content.Views), Valid: true},
+		Likes:           pgtype.Int4{Int32: int32(content.Likes), Valid: true},
+		Reactions:       pgtype.Int4{Int32: int32(content.Reactions), Valid: true},
+		ReadingTime:     pgtype.Int4{Int32: int32(content.ReadingTime), Valid: true},
+		Score:           floatToNumeric(content.Score),
+		BaseScore:       content.Breakdown.BaseScore,
+		TypeMultiplier:  content.Breakdown.TypeMultiplier,
+		FreshnessScore:  content.Breakdown.FreshnessScore,
+		EngagementScore: content.Breakdown.EngagementScore,
+		Tags:            content.Tags,
+	}
 }
 
 func (r *repository) SearchByProvider(ctx context.Context, provider string, query string, page, perPage int) ([]domain.Content, error) {
+	if parsed, ok := parseStructured(query); ok {
+		return r.searchByProviderStructured(ctx, provider, parsed, page, perPage)
+	}
+
 	params := db.SearchContentsByProviderParams{
 		Provider:   provider,
 		Query:      query,
@@ -107,36 +404,133 @@ func (r *repository) SearchByProvider(ctx context.Context, provider string, quer
 
 	contents := make([]domain.Content, len(rows))
 	for i, row := range rows {
-		score, _ := row.Score.Float64Value()
-		contents[i] = domain.Content{
-			ID:          uuidFromPgtype(row.ID),
-			ExternalID:  row.ExternalID,
-			Provider:    row.Provider,
-			Title:       row.Title,
-			Type:        domain.ContentType(row.Type),
-			PublishedAt: row.PublishedAt.Time,
-			Views:       int(row.Views.Int32),
-			Likes:       int(row.Likes.Int32),
-			Reactions:   int(row.Reactions.Int32),
-			ReadingTime: int(row.ReadingTime.Int32),
-			Score:       score.Float64,
-			Tags:        row.Tags,
-			CreatedAt:   row.CreatedAt.Time,
-			UpdatedAt:   row.UpdatedAt.Time,
-		}
+		contents[i] = contentFromSearchRow(db.SearchContentsRow(row))
 	}
 
 	return contents, nil
 }
 
 func (r *repository) GetByID(ctx context.Context, id string) (*domain.Content, error) {
-	return nil, fmt.Errorf("not implemented")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.ErrContentNotFound
+	}
+
+	row, err := r.queries.GetContentByID(ctx, pgtype.UUID{Bytes: parsed, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrContentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content by id: %w", err)
+	}
+
+	return contentFromDetailRow(row), nil
+}
+
+func (r *repository) GetByExternalID(ctx context.Context, provider, externalID string) (*domain.Content, error) {
+	row, err := r.queries.GetContentByExternalID(ctx, db.GetContentByExternalIDParams{
+		Provider:   provider,
+		ExternalID: externalID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrContentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content by external id: %w", err)
+	}
+
+	return contentFromDetailRow(db.GetContentByIDRow(row)), nil
+}
+
+// filterParams maps a search filter onto the parameters shared by the search,
+// count and facet queries.
+func filterParams(query string, filter domain.SearchFilter) db.CountSearchContentsParams {
+	return db.CountSearchContentsParams{
+		Query:          query,
+		Tags:           filter.Tags,
+		TagMode:        filter.TagMode,
+		ExcludeTags:    filter.ExcludeTags,
+		ContentTypes:   filter.ContentTypes,
+		Providers:      filter.Providers,
+		PublishedFrom:  optionalTimestamp(filter.PublishedFrom),
+		PublishedTo:    optionalTimestamp(filter.PublishedTo),
+		MinViews:       int32(filter.MinViews),
+		MinLikes:       int32(filter.MinLikes),
+		MinReactions:   int32(filter.MinReactions),
+		MaxReadingTime: int32(filter.MaxReadingTime),
+		Dedup:          filter.Dedup,
+	}
+}
+
+func optionalTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: !t.IsZero()}
+}
+
+func contentFromSearchRow(row db.SearchContentsRow) domain.Content {
+	score, _ := row.Score.Float64Value()
+	return domain.Content{
+		ID:          uuidFromPgtype(row.ID),
+		ExternalID:  row.ExternalID,
+		Provider:    row.Provider,
+		Title:       row.Title,
+		Type:        domain.ContentType(row.Type),
+		PublishedAt: row.PublishedAt.Time,
+		Views:       int(row.Views.Int32),
+		Likes:       int(row.Likes.Int32),
+		Reactions:   int(row.Reactions.Int32),
+		ReadingTime: int(row.ReadingTime.Int32),
+		Score:       score.Float64,
+		Breakdown:   scoreBreakdown(score.Float64, row.BaseScore, row.TypeMultiplier, row.FreshnessScore, row.EngagementScore),
+		Tags:        row.Tags,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func contentFromDetailRow(row db.GetContentByIDRow) *domain.Content {
+	score, _ := row.Score.Float64Value()
+	return &domain.Content{
+		ID:          uuidFromPgtype(row.ID),
+		ExternalID:  row.ExternalID,
+		Provider:    row.Provider,
+		Title:       row.Title,
+		Type:        domain.ContentType(row.Type),
+		PublishedAt: row.PublishedAt.Time,
+		RawData:     row.RawData,
+		Views:       int(row.Views.Int32),
+		Likes:       int(row.Likes.Int32),
+		Reactions:   int(row.Reactions.Int32),
+		ReadingTime: int(row.ReadingTime.Int32),
+		Score:       score.Float64,
+		Breakdown:   scoreBreakdown(score.Float64, row.BaseScore, row.TypeMultiplier, row.FreshnessScore, row.EngagementScore),
+		Tags:        row.Tags,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func scoreBreakdown(total, base, multiplier, freshness, engagement float64) domain.ScoreBreakdown {
+	return domain.ScoreBreakdown{
+		BaseScore:       base,
+		TypeMultiplier:  multiplier,
+		FreshnessScore:  freshness,
+		EngagementScore: engagement,
+		Total:           total,
+	}
 }
 
 func uuidFromPgtype(u pgtype.UUID) uuid.UUID {
 	return uuid.UUID(u.Bytes)
 }
 
+// numericFromFloat converts through the shortest decimal form, so scores read
+// from numeric columns compare equal when written back.
+func numericFromFloat(f float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(strconv.FormatFloat(f, 'f', -1, 64))
+	return n
+}
+
 func floatToNumeric(f float64) pgtype.Numeric {
 	scaled := int64(f * 100)
 	n := pgtype.Numeric{
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/domain/dedup"
+)
+
+// newTestDB connects to the migrated database in TEST_DATABASE_URL and skips
+// the test when none is configured.
+func newTestDB(t *testing.T) *PostgresDB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	database, err := NewPostgresDB(url)
+	if err != nil {
+		t.Fatalf("NewPostgresDB() error = %v", err)
+	}
+	t.Cleanup(database.Close)
+	return database
+}
+
+func TestRepository_DedupKeepsRepresentativeWhenBetterDuplicateIsFiltered(t *testing.T) {
+	database := newTestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(database)
+	clusters := NewClusterRepository(database)
+	deduper := dedup.New(72*time.Hour, false)
+
+	provider := "dedup-test-" + domain.NewUUID().String()[:8]
+	t.Cleanup(func() {
+		database.Pool.Exec(context.Background(), "DELETE FROM contents WHERE provider = $1", provider)
+	})
+
+	published := time.Now().UTC().Truncate(time.Second)
+	newContent := func(externalID string, score float64, tags []string) *domain.Content {
+		return &domain.Content{
+			ID:          domain.ContentID(provider, externalID),
+			ExternalID:  externalID,
+			Provider:    provider,
+			Title:       "Deduplication across filters",
+			Type:        domain.ContentTypeVideo,
+			PublishedAt: published,
+			RawData:     []byte(`{}`),
+			Score:       score,
+			Tags:        tags,
+		}
+	}
+	better := newContent("better", 90, []string{"go", "archived"})
+	matching := newContent("matching", 10, []string{"go"})
+	for _, content := range []*domain.Content{better, matching} {
+		if err := repo.Upsert(ctx, content); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		if err := clusters.Assign(ctx, content, deduper.Key(*content), deduper.Window()); err != nil {
+			t.Fatalf("Assign() error = %v", err)
+		}
+	}
+
+	filter := domain.SearchFilter{Providers: []string{provider}, ExcludeTags: []string{"archived"}, Dedup: true}
+	for _, q := range []string{"", "tag:go OR tag:rust"} {
+		contents, total, err := repo.Search(ctx, q, filter, "", 1, 10)
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", q, err)
+		}
+		if len(contents) != 1 || contents[0].ID != matching.ID || total != 1 {
+			t.Errorf("Search(%q) = %d contents (total %d), want only the matching duplicate", q, len(contents), total)
+		}
+	}
+}
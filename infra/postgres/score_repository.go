@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"search-engine/app/rescore"
+	"search-engine/domain"
+	"search-engine/infra/postgres/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type scoreRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewScoreRepository(database *PostgresDB) rescore.Repository {
+	return &scoreRepository{
+		pool:    database.Pool,
+		queries: db.New(database.Pool),
+	}
+}
+
+func (r *scoreRepository) ListForRescore(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error) {
+	rows, err := r.queries.ListContentsForRescore(ctx, db.ListContentsForRescoreParams{
+		AfterID:   pgtype.UUID{Bytes: afterID, Valid: true},
+		BatchSize: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contents for rescore: %w", err)
+	}
+
+	contents := make([]domain.Content, len(rows))
+	for i, row := range rows {
+		score, _ := row.Score.Float64Value()
+		contents[i] = domain.Content{
+			ID:          uuidFromPgtype(row.ID),
+			Provider:    row.Provider,
+			Type:        domain.ContentType(row.Type),
+			PublishedAt: row.PublishedAt.Time,
+			Views:       int(row.Views.Int32),
+			Likes:       int(row.Likes.Int32),
+			Reactions:   int(row.Reactions.Int32),
+			ReadingTime: int(row.ReadingTime.Int32),
+			Score:       score.Float64,
+			Breakdown:   scoreBreakdown(score.Float64, row.BaseScore, row.TypeMultiplier, row.FreshnessScore, row.EngagementScore),
+		}
+	}
+
+	return contents, nil
+}
+
+func (r *scoreRepository) UpdateScores(ctx context.Context, contents []*domain.Content) (int, error) {
+	if len(contents) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin score update: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	for _, content := range contents {
+		err := qtx.UpdateContentScore(ctx, db.UpdateContentScoreParams{
+			ID:              pgtype.UUID{Bytes: content.ID, Valid: true},
+			Score:           floatToNumeric(content.Score),
+			BaseScore:       content.Breakdown.BaseScore,
+			TypeMultiplier:  content.Breakdown.TypeMultiplier,
+			FreshnessScore:  content.Breakdown.FreshnessScore,
+			EngagementScore: content.Breakdown.EngagementScore,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to update score for content %s: %w", content.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit score update: %w", err)
+	}
+
+	return len(contents), nil
+}
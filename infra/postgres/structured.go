@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"search-engine/domain"
+	"search-engine/domain/query"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Structured queries (boolean operators, phrases, field filters) cannot be
+// expressed as static sqlc queries, so their predicate is compiled from the
+// parsed AST. Plain queries keep using the generated queries.
+
+const contentColumns = `id, external_id, provider, title, type, published_at,
+       views, likes, reactions, reading_time, score,
+       base_score, type_multiplier, freshness_score, engagement_score,
+       tags, created_at, updated_at`
+
+const popularityColumn = "COALESCE(views, 0) + COALESCE(likes, 0) + COALESCE(reactions, 0)"
+
+// representativeCondition hides contents that have a better-scored duplicate
+// passing the same filters, which it takes with their arguments in place.
+// Unqualified columns in the filters refer to the duplicate there.
+const representativeCondition = `NOT EXISTS (
+    SELECT 1
+    FROM content_clusters mine
+    JOIN content_clusters other ON other.cluster_id = mine.cluster_id AND other.content_id <> mine.content_id
+    WHERE mine.content_id = contents.id
+      AND EXISTS (
+        SELECT 1
+        FROM contents better
+        WHERE better.id = other.content_id
+          AND (better.score, better.id) > (contents.score, contents.id)
+          AND %s))`
+
+type structuredFilter struct {
+	where string
+	args  []any
+}
+
+func parseStructured(raw string) (*query.Query, bool) {
+	parsed, err := query.Parse(raw)
+	if err != nil || parsed.IsSimple() {
+		return nil, false
+	}
+	return parsed, true
+}
+
+func newStructuredFilter(q *query.Query, filter domain.SearchFilter) structuredFilter {
+	predicate, args := q.SQL(0)
+	conditions := []string{predicate}
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(filter.Tags) > 0 && filter.TagMode == domain.TagModeAll {
+		add("tags @> $%d::text[]", filter.Tags)
+	} else if len(filter.Tags) > 0 {
+		add("tags && $%d::text[]", filter.Tags)
+	}
+	if len(filter.ExcludeTags) > 0 {
+		add("NOT (COALESCE(tags, '{}') && $%d::text[])", filter.ExcludeTags)
+	}
+	if len(filter.ContentTypes) > 0 {
+		add("type = ANY($%d::text[])", filter.ContentTypes)
+	}
+	if len(filter.Providers) > 0 {
+		add("provider = ANY($%d::text[])", filter.Providers)
+	}
+	if !filter.PublishedFrom.IsZero() {
+		add("published_at >= $%d::timestamp", filter.PublishedFrom)
+	}
+	if !filter.PublishedTo.IsZero() {
+		add("published_at <= $%d::timestamp", filter.PublishedTo)
+	}
+	if filter.MinViews > 0 {
+		add("COALESCE(views, 0) >= $%d::int", filter.MinViews)
+	}
+	if filter.MinLikes > 0 {
+		add("COALESCE(likes, 0) >= $%d::int", filter.MinLikes)
+	}
+	if filter.MinReactions > 0 {
+		add("COALESCE(reactions, 0) >= $%d::int", filter.MinReactions)
+	}
+	if filter.MaxReadingTime > 0 {
+		add("COALESCE(reading_time, 0) <= $%d::int", filter.MaxReadingTime)
+	}
+	if filter.Dedup {
+		conditions = append(conditions, fmt.Sprintf(representativeCondition, strings.Join(conditions, " AND ")))
+	}
+
+	return structuredFilter{where: strings.Join(conditions, " AND "), args: args}
+}
+
+func (r *repository) searchStructured(ctx context.Context, q *query.Query, searchFilter domain.SearchFilter, sortBy string, page, perPage int) ([]domain.Content, int64, error) {
+	filter := newStructuredFilter(q, searchFilter)
+
+	args := append(append([]any(nil), filter.args...), perPage, (page-1)*perPage)
+	sql := fmt.Sprintf("SELECT %s FROM contents WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d",
+		contentColumns, filter.where, structuredOrder(sortBy, "DESC"), len(args)-1, len(args))
+
+	contents, err := r.queryContents(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search contents: %w", err)
+	}
+
+	total, err := r.countStructured(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return contents, total, nil
+}
+
+func (r *repository) searchKeysetStructured(ctx context.Context, q *query.Query, searchFilter domain.SearchFilter, sortBy string, keyset domain.Keyset, direction domain.CursorDirection, limit int) ([]domain.Content, int64, error) {
+	filter := newStructuredFilter(q, searchFilter)
+
+	op, order := "<", "DESC"
+	if direction == domain.CursorPrev {
+		op, order = ">", "ASC"
+	}
+
+	columns := "score, published_at, id"
+	values := []any{numericFromFloat(keyset.Score), pgtype.Timestamp{Time: keyset.PublishedAt, Valid: true}, pgtype.UUID{Bytes: keyset.ID, Valid: true}}
+	casts := []string{"numeric", "timestamp", "uuid"}
+	if sortBy == "popularity" {
+		columns = popularityColumn + ", " + columns
+		values = append([]any{keyset.Popularity}, values...)
+		casts = append([]string{"bigint"}, casts...)
+	}
+
+	args := append([]any(nil), filter.args...)
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = fmt.Sprintf("$%d::%s", len(args), casts[i])
+	}
+	args = append(args, limit)
+
+	sql := fmt.Sprintf("SELECT %s FROM contents WHERE %s AND (%s) %s (%s) ORDER BY %s LIMIT $%d",
+		contentColumns, filter.where, columns, op, strings.Join(placeholders, ", "), structuredOrder(sortBy, order), len(args))
+
+	contents, err := r.queryContents(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search contents by keyset: %w", err)
+	}
+	if direction == domain.CursorPrev {
+		reverseContents(contents)
+	}
+
+	total, err := r.countStructured(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return contents, total, nil
+}
+
+func (r *repository) countStructured(ctx context.Context, filter structuredFilter) (int64, error) {
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM contents WHERE "+filter.where, filter.args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count search contents: %w", err)
+	}
+	return total, nil
+}
+
+// structuredOrder mirrors the ORDER BY of the generated search queries.
+func structuredOrder(sortBy, direction string) string {
+	order := fmt.Sprintf("score %[1]s, published_at %[1]s, id %[1]s", direction)
+	if sortBy == "popularity" {
+		return fmt.Sprintf("%s %s, %s", popularityColumn, direction, order)
+	}
+	return order
+}
+
+func (r *repository) searchByProviderStructured(ctx context.Context, provider string, q *query.Query, page, perPage int) ([]domain.Content, error) {
+	predicate, args := q.SQL(0)
+	args = append(args, provider, perPage, (page-1)*perPage)
+	sql := fmt.Sprintf("SELECT %s FROM contents WHERE provider = $%d AND %s ORDER BY score DESC, published_at DESC, id DESC LIMIT $%d OFFSET $%d",
+		contentColumns, len(args)-2, predicate, len(args)-1, len(args))
+
+	contents, err := r.queryContents(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search contents by provider: %w", err)
+	}
+	return contents, nil
+}
+
+func (r *repository) facetsStructured(ctx context.Context, q *query.Query, searchFilter domain.SearchFilter, req domain.FacetRequest) (*domain.Facets, error) {
+	filter := newStructuredFilter(q, searchFilter)
+	facets := &domain.Facets{}
+
+	countBuckets := func(name, sql string, args ...any) ([]domain.FacetBucket, error) {
+		rows, err := r.pool.Query(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet %s: %w", name, err)
+		}
+		buckets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.FacetBucket, error) {
+			var bucket domain.FacetBucket
+			err := row.Scan(&bucket.Value, &bucket.Count)
+			return bucket, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet %s: %w", name, err)
+		}
+		return buckets, nil
+	}
+
+	var err error
+	if req.Has(domain.FacetType) {
+		facets.Types, err = countBuckets("content types",
+			"SELECT type::text AS value, COUNT(*) AS count FROM contents WHERE "+filter.where+" GROUP BY type ORDER BY count DESC, value",
+			filter.args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Has(domain.FacetTags) {
+		args := append(append([]any(nil), filter.args...), req.Size)
+		facets.Tags, err = countBuckets("tags",
+			fmt.Sprintf("SELECT tag::text AS value, COUNT(*) AS count FROM contents, unnest(tags) AS tag WHERE %s GROUP BY tag ORDER BY count DESC, value LIMIT $%d", filter.where, len(args)),
+			args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Has(domain.FacetProvider) {
+		facets.Providers, err = countBuckets("providers",
+			"SELECT provider::text AS value, COUNT(*) AS count FROM contents WHERE "+filter.where+" GROUP BY provider ORDER BY count DESC, value",
+			filter.args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Has(domain.FacetPublished) {
+		args := append(append([]any(nil), filter.args...), req.Interval)
+		rows, err := r.pool.Query(ctx,
+			fmt.Sprintf("SELECT date_trunc($%d::text, published_at)::timestamp AS bucket, COUNT(*) AS count FROM contents WHERE %s GROUP BY bucket ORDER BY bucket", len(args), filter.where),
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet published dates: %w", err)
+		}
+		facets.Published, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.FacetBucket, error) {
+			var bucket pgtype.Timestamp
+			var count int64
+			err := row.Scan(&bucket, &count)
+			return domain.FacetBucket{Value: domain.FormatFacetDate(bucket.Time), Count: count}, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet published dates: %w", err)
+		}
+	}
+
+	if req.Has(domain.FacetViews) {
+		bounds := make([]int32, 0, len(domain.ViewRanges)-1)
+		for _, viewRange := range domain.ViewRanges[1:] {
+			bounds = append(bounds, int32(viewRange.Min))
+		}
+
+		args := append(append([]any(nil), filter.args...), bounds)
+		rows, err := r.pool.Query(ctx,
+			fmt.Sprintf("SELECT width_bucket(COALESCE(views, 0), $%d::int[])::int AS bucket, COUNT(*) AS count FROM contents WHERE %s GROUP BY bucket ORDER BY bucket", len(args), filter.where),
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet view ranges: %w", err)
+		}
+		buckets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([2]int64, error) {
+			var bucket int32
+			var count int64
+			err := row.Scan(&bucket, &count)
+			return [2]int64{int64(bucket), count}, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to facet view ranges: %w", err)
+		}
+		for _, bucket := range buckets {
+			if int(bucket[0]) >= len(domain.ViewRanges) {
+				continue
+			}
+			facets.Views = append(facets.Views, domain.FacetBucket{Value: domain.ViewRanges[bucket[0]].Label, Count: bucket[1]})
+		}
+	}
+
+	return facets, nil
+}
+
+func (r *repository) queryContents(ctx context.Context, sql string, args ...any) ([]domain.Content, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := make([]domain.Content, 0)
+	for rows.Next() {
+		var (
+			id                                      pgtype.UUID
+			publishedAt, createdAt, updatedAt       pgtype.Timestamp
+			views, likes, reactions, readingTime    pgtype.Int4
+			score                                   pgtype.Numeric
+			base, multiplier, freshness, engagement float64
+			content                                 domain.Content
+			contentType                             string
+		)
+		if err := rows.Scan(
+			&id, &content.ExternalID, &content.Provider, &content.Title, &contentType, &publishedAt,
+			&views, &likes, &reactions, &readingTime, &score,
+			&base, &multiplier, &freshness, &engagement,
+			&content.Tags, &createdAt, &updatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		total, _ := score.Float64Value()
+		content.ID = uuidFromPgtype(id)
+		content.Type = domain.ContentType(contentType)
+		content.PublishedAt = publishedAt.Time
+		content.Views = int(views.Int32)
+		content.Likes = int(likes.Int32)
+		content.Reactions = int(reactions.Int32)
+		content.ReadingTime = int(readingTime.Int32)
+		content.Score = total.Float64
+		content.Breakdown = scoreBreakdown(total.Float64, base, multiplier, freshness, engagement)
+		content.CreatedAt = createdAt.Time
+		content.UpdatedAt = updatedAt.Time
+		contents = append(contents, content)
+	}
+
+	return contents, rows.Err()
+}
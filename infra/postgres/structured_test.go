@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"search-engine/domain"
+	"search-engine/domain/query"
+)
+
+func TestStructuredFilter_DedupAppliesFiltersToDuplicates(t *testing.T) {
+	parsed, err := query.Parse("tag:go OR tag:rust")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	filter := newStructuredFilter(parsed, domain.SearchFilter{ExcludeTags: []string{"archived"}, MinViews: 10, Dedup: true})
+
+	_, duplicate, ok := strings.Cut(filter.where, "FROM contents better")
+	if !ok {
+		t.Fatalf("where = %s, want a duplicate subquery", filter.where)
+	}
+	for _, condition := range []string{"lower($1)", "lower($2)", "&& $3::text[]", ">= $4::int"} {
+		if !strings.Contains(duplicate, condition) {
+			t.Errorf("duplicate subquery %s is missing %q", duplicate, condition)
+		}
+	}
+	if len(filter.args) != 4 {
+		t.Errorf("len(args) = %d, want 4 shared by both predicates", len(filter.args))
+	}
+}
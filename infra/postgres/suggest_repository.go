@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"search-engine/app/suggest"
+	"search-engine/domain"
+	"search-engine/infra/postgres/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type suggestRepository struct {
+	queries *db.Queries
+}
+
+func NewSuggestRepository(database *PostgresDB) suggest.Repository {
+	return &suggestRepository{
+		queries: db.New(database.Pool),
+	}
+}
+
+func (r *suggestRepository) ListForSuggest(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.Content, error) {
+	rows, err := r.queries.ListContentsForSuggest(ctx, db.ListContentsForSuggestParams{
+		AfterID:   pgtype.UUID{Bytes: afterID, Valid: true},
+		BatchSize: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contents for suggest: %w", err)
+	}
+
+	contents := make([]domain.Content, len(rows))
+	for i, row := range rows {
+		contents[i] = domain.Content{
+			ID:        uuidFromPgtype(row.ID),
+			Title:     row.Title,
+			Views:     int(row.Views.Int32),
+			Likes:     int(row.Likes.Int32),
+			Reactions: int(row.Reactions.Int32),
+			Tags:      row.Tags,
+		}
+	}
+
+	return contents, nil
+}
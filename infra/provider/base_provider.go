@@ -12,17 +12,21 @@ import (
 )
 
 type BaseHTTPProvider struct {
-	name    string
-	baseURL string
-	doer    *httpclient.Doer
+	name          string
+	baseURL       string
+	doer          *httpclient.Doer
+	capabilities  Capabilities
+	fuzzyMatching bool
 }
 
 type BaseHTTPProviderConfig struct {
-	Name    string
-	BaseURL string
-	Timeout time.Duration
-	Client  httpclient.HTTPClient
-	Logger  *zap.Logger
+	Name          string
+	BaseURL       string
+	Timeout       time.Duration
+	Client        httpclient.HTTPClient
+	Logger        *zap.Logger
+	Capabilities  Capabilities
+	FuzzyMatching bool
 }
 
 func NewBaseHTTPProvider(config BaseHTTPProviderConfig) BaseHTTPProvider {
@@ -34,9 +38,11 @@ func NewBaseHTTPProvider(config BaseHTTPProviderConfig) BaseHTTPProvider {
 	}
 
 	return BaseHTTPProvider{
-		name:    config.Name,
-		baseURL: config.BaseURL,
-		doer:    httpclient.NewDoer(client),
+		name:          config.Name,
+		baseURL:       config.BaseURL,
+		doer:          httpclient.NewDoer(client),
+		capabilities:  config.Capabilities.withDefaults(),
+		fuzzyMatching: config.FuzzyMatching,
 	}
 }
 
@@ -48,6 +54,14 @@ func (b *BaseHTTPProvider) BaseURL() string {
 	return b.baseURL
 }
 
+func (b *BaseHTTPProvider) Capabilities() Capabilities {
+	return b.capabilities
+}
+
+func (b *BaseHTTPProvider) FuzzyMatching() bool {
+	return b.fuzzyMatching
+}
+
 func (b *BaseHTTPProvider) HealthCheck(ctx context.Context) error {
 	resp, err := b.doer.Head(ctx, b.baseURL)
 	if err != nil {
@@ -62,12 +76,20 @@ func (b *BaseHTTPProvider) HealthCheck(ctx context.Context) error {
 }
 
 func (b *BaseHTTPProvider) FetchData(ctx context.Context, acceptHeader string) ([]byte, error) {
+	return b.fetchURL(ctx, b.baseURL, acceptHeader)
+}
+
+func (b *BaseHTTPProvider) FetchPage(ctx context.Context, acceptHeader, query string, page, perPage int) ([]byte, error) {
+	return b.fetchURL(ctx, b.capabilities.BuildURL(b.baseURL, query, page, perPage), acceptHeader)
+}
+
+func (b *BaseHTTPProvider) fetchURL(ctx context.Context, url, acceptHeader string) ([]byte, error) {
 	headers := make(map[string]string)
 	if acceptHeader != "" {
 		headers["Accept"] = acceptHeader
 	}
 
-	resp, err := b.doer.Get(ctx, b.baseURL, headers)
+	resp, err := b.doer.Get(ctx, url, headers)
 	if err != nil {
 		return nil, fmt.Errorf("%s request failed: %w", b.name, err)
 	}
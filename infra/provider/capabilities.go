@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"search-engine/domain"
+	"search-engine/domain/fuzzy"
+)
+
+// Capabilities describe what an upstream supports. The query is sent upstream
+// unless LocalFiltering is set for feeds that ignore it.
+type Capabilities struct {
+	QueryParam           string
+	PageParam            string
+	PerPageParam         string
+	MaxPageSize          int
+	LocalFiltering       bool
+	ServerSidePagination bool
+}
+
+type CapableProvider interface {
+	ContentProvider
+	Capabilities() Capabilities
+}
+
+func DefaultCapabilities() Capabilities {
+	return Capabilities{
+		QueryParam:   "q",
+		PageParam:    "page",
+		PerPageParam: "per_page",
+	}
+}
+
+type Options struct {
+	Capabilities  Capabilities
+	Mapping       *MappingSpec
+	FuzzyMatching bool
+}
+
+type Option func(*Options)
+
+func WithCapabilities(caps Capabilities) Option {
+	return func(o *Options) {
+		o.Capabilities = caps.withDefaults()
+	}
+}
+
+// WithFuzzyMatching lets local title filtering fall back to typo-tolerant
+// matching when nothing matches exactly.
+func WithFuzzyMatching(enabled bool) Option {
+	return func(o *Options) {
+		o.FuzzyMatching = enabled
+	}
+}
+
+func newOptions(opts []Option) Options {
+	o := Options{Capabilities: DefaultCapabilities()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (c Capabilities) withDefaults() Capabilities {
+	defaults := DefaultCapabilities()
+	if c.QueryParam == "" {
+		c.QueryParam = defaults.QueryParam
+	}
+	if c.PageParam == "" {
+		c.PageParam = defaults.PageParam
+	}
+	if c.PerPageParam == "" {
+		c.PerPageParam = defaults.PerPageParam
+	}
+	if c.MaxPageSize < 0 {
+		c.MaxPageSize = 0
+	}
+	return c
+}
+
+func (c Capabilities) BuildURL(baseURL, query string, page, perPage int) string {
+	values := url.Values{}
+
+	if !c.LocalFiltering && query != "" {
+		values.Set(c.QueryParam, query)
+	}
+
+	if c.ServerSidePagination && page > 0 && perPage > 0 {
+		values.Set(c.PageParam, strconv.Itoa(page))
+		values.Set(c.PerPageParam, strconv.Itoa(perPage))
+	}
+
+	if len(values) == 0 {
+		return baseURL
+	}
+
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+
+	return baseURL + separator + values.Encode()
+}
+
+func (c Capabilities) CanPaginate(query string) bool {
+	return c.ServerSidePagination && (query == "" || !c.LocalFiltering)
+}
+
+type PageFetcher func(page, perPage int) (*SearchResponse, error)
+
+// FetchWindow serves page/perPage from an upstream that caps its page size by
+// requesting every upstream page overlapping the window and slicing the result.
+func (c Capabilities) FetchWindow(page, perPage int, fetch PageFetcher) (*SearchResponse, error) {
+	upstreamSize := perPage
+	if c.MaxPageSize > 0 && upstreamSize > c.MaxPageSize {
+		upstreamSize = c.MaxPageSize
+	}
+
+	if upstreamSize == perPage {
+		return fetch(page, perPage)
+	}
+
+	start := (page - 1) * perPage
+	end := start + perPage
+	firstPage := start/upstreamSize + 1
+	lastPage := (end-1)/upstreamSize + 1
+
+	var collected []domain.ProviderContent
+	var pagination PaginationInfo
+
+	for p := firstPage; p <= lastPage; p++ {
+		resp, err := fetch(p, upstreamSize)
+		if err != nil {
+			return nil, err
+		}
+
+		pagination = resp.Pagination
+		collected = append(collected, resp.Contents...)
+
+		if len(resp.Contents) == 0 || (pagination.TotalPages > 0 && p >= pagination.TotalPages) {
+			break
+		}
+	}
+
+	offset := start - (firstPage-1)*upstreamSize
+	if offset > len(collected) {
+		offset = len(collected)
+	}
+	collected = collected[offset:]
+	if len(collected) > perPage {
+		collected = collected[:perPage]
+	}
+
+	return &SearchResponse{
+		Contents: collected,
+		Pagination: PaginationInfo{
+			CurrentPage: page,
+			PerPage:     perPage,
+			Total:       pagination.Total,
+			TotalPages:  totalPages(pagination.Total, perPage),
+		},
+	}, nil
+}
+
+// filterByTitle keeps contents whose title contains the query. When nothing
+// matches exactly and fuzzyMatching is set, typo-tolerant matching is used instead.
+func filterByTitle(contents []domain.ProviderContent, query string, fuzzyMatching bool) []domain.ProviderContent {
+	if query == "" {
+		return contents
+	}
+
+	lowered := strings.ToLower(query)
+	filtered := make([]domain.ProviderContent, 0, len(contents))
+	for _, content := range contents {
+		if strings.Contains(strings.ToLower(content.Title), lowered) {
+			filtered = append(filtered, content)
+		}
+	}
+	if len(filtered) > 0 || !fuzzyMatching {
+		return filtered
+	}
+
+	for _, content := range contents {
+		if fuzzy.MatchText(query, content.Title) {
+			filtered = append(filtered, content)
+		}
+	}
+	return filtered
+}
+
+func paginateLocally(contents []domain.ProviderContent, page, perPage int) *SearchResponse {
+	total := len(contents)
+
+	start := (page - 1) * perPage
+	end := start + perPage
+
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	return &SearchResponse{
+		Contents: contents[start:end],
+		Pagination: PaginationInfo{
+			CurrentPage: page,
+			PerPage:     perPage,
+			Total:       total,
+			TotalPages:  totalPages(total, perPage),
+		},
+	}
+}
+
+func totalPages(total, perPage int) int {
+	if perPage <= 0 {
+		return 0
+	}
+	return (total + perPage - 1) / perPage
+}
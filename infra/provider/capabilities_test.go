@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/infra/httpclient"
+)
+
+func TestCapabilities_BuildURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     Capabilities
+		baseURL  string
+		query    string
+		page     int
+		perPage  int
+		expected string
+	}{
+		{"query by default", DefaultCapabilities(), "http://feed", "go", 2, 10, "http://feed?q=go"},
+		{"local filtering", Capabilities{QueryParam: "q", LocalFiltering: true}, "http://feed", "go", 2, 10, "http://feed"},
+		{"pagination only", Capabilities{PageParam: "p", PerPageParam: "size", LocalFiltering: true, ServerSidePagination: true}, "http://feed", "go", 2, 10, "http://feed?p=2&size=10"},
+		{"existing query string", Capabilities{QueryParam: "search"}, "http://feed?key=1", "a b", 0, 0, "http://feed?key=1&search=a+b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.caps.BuildURL(tt.baseURL, tt.query, tt.page, tt.perPage)
+			if got != tt.expected {
+				t.Errorf("BuildURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCapabilities_FetchWindow(t *testing.T) {
+	all := make([]domain.ProviderContent, 95)
+	for i := range all {
+		all[i] = domain.ProviderContent{ExternalID: strconv.Itoa(i)}
+	}
+
+	var requested []int
+	fetch := func(page, perPage int) (*SearchResponse, error) {
+		requested = append(requested, page)
+		return paginateLocally(all, page, perPage), nil
+	}
+
+	caps := Capabilities{MaxPageSize: 20, ServerSidePagination: true}
+
+	resp, err := caps.FetchWindow(2, 50, fetch)
+	if err != nil {
+		t.Fatalf("FetchWindow() error = %v", err)
+	}
+
+	if len(resp.Contents) != 45 {
+		t.Fatalf("len(Contents) = %d, want 45", len(resp.Contents))
+	}
+	if resp.Contents[0].ExternalID != "50" {
+		t.Errorf("first ExternalID = %q, want \"50\"", resp.Contents[0].ExternalID)
+	}
+	if resp.Pagination.Total != 95 || resp.Pagination.TotalPages != 2 {
+		t.Errorf("Pagination = %+v, want Total 95 and TotalPages 2", resp.Pagination)
+	}
+	if fmt.Sprint(requested) != "[3 4 5]" {
+		t.Errorf("requested upstream pages = %v, want [3 4 5]", requested)
+	}
+}
+
+func TestProvider1_SearchWithPaginationPushdown(t *testing.T) {
+	var gotQuery string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"contents":[{"id":"v1","title":"Go Basics","type":"video","metrics":{"views":10,"likes":1},"published_at":"2024-03-15T00:00:00Z"}],"pagination":{"total":41,"page":3,"per_page":20}}`)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewDefaultHTTPClient()
+	p := NewProvider1("p1", server.URL, time.Second, client, nil, WithCapabilities(Capabilities{
+		ServerSidePagination: true,
+	}))
+
+	resp, err := p.SearchWithPagination(t.Context(), "go", 3, 20)
+	if err != nil {
+		t.Fatalf("SearchWithPagination() error = %v", err)
+	}
+
+	if gotQuery != "page=3&per_page=20&q=go" {
+		t.Errorf("upstream query = %q, want %q", gotQuery, "page=3&per_page=20&q=go")
+	}
+	if len(resp.Contents) != 1 || resp.Pagination.Total != 41 || resp.Pagination.TotalPages != 3 {
+		t.Errorf("unexpected response: %d contents, pagination %+v", len(resp.Contents), resp.Pagination)
+	}
+}
+
+func TestFilterByTitle(t *testing.T) {
+	contents := []domain.ProviderContent{
+		{ExternalID: "1", Title: "Golang Tutorial for Beginners"},
+		{ExternalID: "2", Title: "Advanced Golang Patterns"},
+		{ExternalID: "3", Title: "Rust Basics"},
+	}
+
+	tests := []struct {
+		query string
+		fuzzy bool
+		want  []string
+	}{
+		{"", true, []string{"1", "2", "3"}},
+		{"golang", true, []string{"1", "2"}},
+		{"golang tutorail", true, []string{"1"}},
+		{"golang tutorail", false, []string{}},
+		{"python", true, []string{}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for _, content := range filterByTitle(contents, tt.query, tt.fuzzy) {
+			got = append(got, content.ExternalID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("filterByTitle(%q, %v) = %v, want %v", tt.query, tt.fuzzy, got, tt.want)
+		}
+	}
+}
@@ -30,7 +30,10 @@ func (s CircuitState) String() string {
 	}
 }
 
-var ErrCircuitOpen = errors.New("circuit breaker is open")
+var (
+	ErrCircuitOpen         = errors.New("circuit breaker is open")
+	ErrFetchAllUnsupported = errors.New("provider does not support fetching all contents")
+)
 
 type CircuitBreaker struct {
 	mu              sync.RWMutex
@@ -129,6 +132,17 @@ func (cb *CircuitBreaker) State() CircuitState {
 	return cb.state
 }
 
+// Headroom is the number of failures the breaker absorbs before it opens.
+// It is zero while the breaker is open or half-open.
+func (cb *CircuitBreaker) Headroom() int {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.state != StateClosed {
+		return 0
+	}
+	return max(cb.threshold-cb.failureCount, 0)
+}
+
 type CircuitBreakerProvider struct {
 	provider ContentProvider
 	breaker  *CircuitBreaker
@@ -159,6 +173,66 @@ func (p *CircuitBreakerProvider) Search(ctx context.Context, query string) ([]do
 	return contents, searchErr
 }
 
+func (p *CircuitBreakerProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	var resp *SearchResponse
+	var searchErr error
+
+	err := p.breaker.Execute(func() error {
+		var err error
+		if paginatable, ok := p.provider.(PaginatableProvider); ok {
+			resp, err = paginatable.SearchWithPagination(ctx, query, page, perPage)
+		} else {
+			var contents []domain.ProviderContent
+			contents, err = p.provider.Search(ctx, query)
+			if err == nil {
+				resp = paginateLocally(contents, page, perPage)
+			}
+		}
+		searchErr = err
+		return err
+	})
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return nil, err
+	}
+
+	return resp, searchErr
+}
+
+func (p *CircuitBreakerProvider) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
+	fetchable, ok := p.provider.(FetchableProvider)
+	if !ok {
+		return nil, ErrFetchAllUnsupported
+	}
+
+	var contents []domain.ProviderContent
+	var fetchErr error
+
+	err := p.breaker.Execute(func() error {
+		var err error
+		contents, err = fetchable.FetchAll(ctx)
+		fetchErr = err
+		return err
+	})
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return nil, err
+	}
+
+	return contents, fetchErr
+}
+
+func (p *CircuitBreakerProvider) Unwrap() ContentProvider {
+	return p.provider
+}
+
+func (p *CircuitBreakerProvider) Capabilities() Capabilities {
+	if capable, ok := p.provider.(CapableProvider); ok {
+		return capable.Capabilities()
+	}
+	return DefaultCapabilities()
+}
+
 func (p *CircuitBreakerProvider) Name() string {
 	return p.provider.Name()
 }
@@ -173,3 +247,7 @@ func (p *CircuitBreakerProvider) HealthCheck(ctx context.Context) error {
 func (p *CircuitBreakerProvider) CircuitState() CircuitState {
 	return p.breaker.State()
 }
+
+func (p *CircuitBreakerProvider) CircuitHeadroom() int {
+	return p.breaker.Headroom()
+}
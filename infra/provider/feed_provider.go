@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/infra/httpclient"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("rss", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+		return NewFeedProvider(name, url, FeedFormatRSS, timeout, client, logger, opts...)
+	})
+	Register("atom", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+		return NewFeedProvider(name, url, FeedFormatAtom, timeout, client, logger, opts...)
+	})
+}
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+
+	wordsPerMinute = 200
+)
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+type FeedProvider struct {
+	BaseHTTPProvider
+	format string
+	logger *zap.Logger
+}
+
+type RSSFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+type RSSChannel struct {
+	Title string    `xml:"title"`
+	Items []RSSItem `xml:"item"`
+}
+
+type RSSItem struct {
+	Title        string          `xml:"title"`
+	Link         string          `xml:"link"`
+	GUID         string          `xml:"guid"`
+	PubDate      string          `xml:"pubDate"`
+	Description  string          `xml:"description"`
+	Categories   []string        `xml:"category"`
+	Enclosures   []RSSEnclosure  `xml:"enclosure"`
+	MediaContent []MediaContent  `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup   *MediaGroupItem `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type MediaContent struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+}
+
+type MediaGroupItem struct {
+	Contents []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// MediaContent is declared before Content so that media:content elements are
+// not captured by the namespace-agnostic content field.
+type AtomEntry struct {
+	ID           string         `xml:"id"`
+	Title        string         `xml:"title"`
+	Updated      string         `xml:"updated"`
+	Published    string         `xml:"published"`
+	Summary      string         `xml:"summary"`
+	MediaContent []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Content      string         `xml:"content"`
+	Links        []AtomLink     `xml:"link"`
+	Categories   []AtomCategory `xml:"category"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type AtomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+func NewFeedProvider(name, baseURL, format string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) *FeedProvider {
+	options := newOptions(opts)
+
+	return &FeedProvider{
+		BaseHTTPProvider: NewBaseHTTPProvider(BaseHTTPProviderConfig{
+			Name:          name,
+			BaseURL:       baseURL,
+			Timeout:       timeout,
+			Client:        client,
+			Logger:        logger,
+			Capabilities:  options.Capabilities,
+			FuzzyMatching: options.FuzzyMatching,
+		}),
+		format: format,
+		logger: logger,
+	}
+}
+
+func (p *FeedProvider) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
+	body, err := p.FetchPage(ctx, p.acceptHeader(), query, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := p.parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Capabilities().LocalFiltering {
+		contents = filterByTitle(contents, query, p.FuzzyMatching())
+	}
+
+	return contents, nil
+}
+
+func (p *FeedProvider) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
+	body, err := p.FetchData(ctx, p.acceptHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parse(body)
+}
+
+func (p *FeedProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	contents, err := p.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateLocally(contents, page, perPage), nil
+}
+
+func (p *FeedProvider) acceptHeader() string {
+	if p.format == FeedFormatAtom {
+		return "application/atom+xml"
+	}
+	return "application/rss+xml"
+}
+
+func (p *FeedProvider) parse(body []byte) ([]domain.ProviderContent, error) {
+	var mapped []domain.ProviderContent
+
+	switch p.format {
+	case FeedFormatAtom:
+		var feed AtomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		for _, entry := range feed.Entries {
+			mapped = append(mapped, mapAtomEntry(entry))
+		}
+	default:
+		var feed RSSFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		for _, item := range feed.Channel.Items {
+			mapped = append(mapped, mapRSSItem(item))
+		}
+	}
+
+	contents := make([]domain.ProviderContent, 0, len(mapped))
+	for _, content := range mapped {
+		if err := domain.ValidateProviderContent(content); err != nil {
+			if p.logger != nil {
+				p.logger.Warn("invalid content from provider, skipping",
+					zap.String("provider", p.Name()),
+					zap.String("external_id", content.ExternalID),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+		contents = append(contents, content)
+	}
+
+	return contents, nil
+}
+
+func mapRSSItem(item RSSItem) domain.ProviderContent {
+	externalID := strings.TrimSpace(item.GUID)
+	if externalID == "" {
+		externalID = strings.TrimSpace(item.Link)
+	}
+
+	media := item.MediaContent
+	if item.MediaGroup != nil {
+		media = append(media, item.MediaGroup.Contents...)
+	}
+
+	isVideo := hasVideoMedia(media)
+	for _, enclosure := range item.Enclosures {
+		if isVideoMIME(enclosure.Type) {
+			isVideo = true
+		}
+	}
+
+	rawData, _ := json.Marshal(item)
+
+	return newFeedContent(externalID, item.Title, parseFeedDate(item.PubDate), item.Description, item.Categories, isVideo, rawData)
+}
+
+func mapAtomEntry(entry AtomEntry) domain.ProviderContent {
+	externalID := strings.TrimSpace(entry.ID)
+	isVideo := hasVideoMedia(entry.MediaContent)
+
+	for _, link := range entry.Links {
+		if externalID == "" && (link.Rel == "" || link.Rel == "alternate") {
+			externalID = strings.TrimSpace(link.Href)
+		}
+		if link.Rel == "enclosure" && isVideoMIME(link.Type) {
+			isVideo = true
+		}
+	}
+
+	published := entry.Published
+	if published == "" {
+		published = entry.Updated
+	}
+
+	body := entry.Content
+	if body == "" {
+		body = entry.Summary
+	}
+
+	tags := make([]string, 0, len(entry.Categories))
+	for _, category := range entry.Categories {
+		tag := category.Term
+		if tag == "" {
+			tag = category.Label
+		}
+		tags = append(tags, tag)
+	}
+
+	rawData, _ := json.Marshal(entry)
+
+	return newFeedContent(externalID, entry.Title, parseFeedDate(published), body, tags, isVideo, rawData)
+}
+
+func newFeedContent(externalID, title string, publishedAt time.Time, body string, categories []string, isVideo bool, rawData []byte) domain.ProviderContent {
+	content := domain.ProviderContent{
+		ExternalID:  externalID,
+		Title:       strings.TrimSpace(html.UnescapeString(title)),
+		Type:        string(domain.ContentTypeText),
+		PublishedAt: publishedAt,
+		RawData:     rawData,
+	}
+
+	for _, category := range categories {
+		if tag := strings.TrimSpace(category); tag != "" {
+			content.Tags = append(content.Tags, tag)
+		}
+	}
+
+	if isVideo {
+		content.Type = string(domain.ContentTypeVideo)
+	} else {
+		content.ReadingTime = estimateReadingTime(body)
+	}
+
+	return content
+}
+
+func hasVideoMedia(media []MediaContent) bool {
+	for _, m := range media {
+		if strings.EqualFold(m.Medium, "video") || isVideoMIME(m.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVideoMIME(mimeType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(mimeType)), "video/")
+}
+
+func parseFeedDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+func estimateReadingTime(body string) int {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(body, " "))
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return 0
+	}
+
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
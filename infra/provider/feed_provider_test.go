@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleRSS = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Example</title>
+    <item>
+      <title>Go Concurrency Patterns</title>
+      <guid>rss-1</guid>
+      <pubDate>Fri, 15 Mar 2024 10:00:00 +0000</pubDate>
+      <description>&lt;p&gt;` + "%s" + `&lt;/p&gt;</description>
+      <category>go</category>
+      <category>concurrency</category>
+    </item>
+    <item>
+      <title>Docker in 10 Minutes</title>
+      <link>https://example.com/docker</link>
+      <pubDate>Sat, 16 Mar 2024 10:00:00 GMT</pubDate>
+      <media:content url="https://example.com/docker.mp4" medium="video"/>
+    </item>
+    <item>
+      <title>Podcast Episode</title>
+      <guid>rss-3</guid>
+      <pubDate>Sun, 17 Mar 2024 10:00:00 +0000</pubDate>
+      <enclosure url="https://example.com/ep.mp4" type="video/mp4" length="1000"/>
+    </item>
+  </channel>
+</rss>`
+
+const sampleAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <entry>
+    <id>urn:uuid:atom-1</id>
+    <title>Kubernetes Basics</title>
+    <updated>2024-03-15T10:00:00Z</updated>
+    <summary>Short summary</summary>
+    <category term="k8s"/>
+    <link rel="alternate" href="https://example.com/k8s"/>
+  </entry>
+  <entry>
+    <title>Live Coding Session</title>
+    <published>2024-03-16T10:00:00Z</published>
+    <link href="https://example.com/live"/>
+    <link rel="enclosure" type="video/webm" href="https://example.com/live.webm"/>
+  </entry>
+</feed>`
+
+func TestFeedProvider_ParseRSS(t *testing.T) {
+	p := NewFeedProvider("blog", "http://example.com", FeedFormatRSS, time.Second, nil, nil)
+
+	description := strings.Repeat("word ", 450)
+	contents, err := p.parse([]byte(strings.Replace(sampleRSS, "%s", description, 1)))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	if len(contents) != 3 {
+		t.Fatalf("len(contents) = %d, want 3", len(contents))
+	}
+
+	article := contents[0]
+	if article.ExternalID != "rss-1" || article.Type != "text" || article.ReadingTime != 3 {
+		t.Errorf("unexpected article: %+v", article)
+	}
+	if len(article.Tags) != 2 || article.Tags[0] != "go" {
+		t.Errorf("Tags = %v, want [go concurrency]", article.Tags)
+	}
+
+	if contents[1].ExternalID != "https://example.com/docker" || contents[1].Type != "video" {
+		t.Errorf("media:content item = %+v, want video keyed by link", contents[1])
+	}
+	if contents[2].Type != "video" {
+		t.Errorf("enclosure item type = %q, want video", contents[2].Type)
+	}
+}
+
+func TestFeedProvider_ParseAtom(t *testing.T) {
+	p := NewFeedProvider("blog", "http://example.com", FeedFormatAtom, time.Second, nil, nil)
+
+	contents, err := p.parse([]byte(sampleAtom))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	if len(contents) != 2 {
+		t.Fatalf("len(contents) = %d, want 2", len(contents))
+	}
+
+	first := contents[0]
+	if first.ExternalID != "urn:uuid:atom-1" || first.Type != "text" || first.ReadingTime != 1 {
+		t.Errorf("unexpected entry: %+v", first)
+	}
+	if first.PublishedAt.IsZero() || len(first.Tags) != 1 || first.Tags[0] != "k8s" {
+		t.Errorf("PublishedAt = %v, Tags = %v", first.PublishedAt, first.Tags)
+	}
+
+	second := contents[1]
+	if second.ExternalID != "https://example.com/live" || second.Type != "video" {
+		t.Errorf("unexpected entry: %+v", second)
+	}
+}
+
+func TestEstimateReadingTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"empty", "", 0},
+		{"markup only", "<p></p>", 0},
+		{"short", "<p>a few words</p>", 1},
+		{"two minutes", strings.Repeat("w ", 201), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateReadingTime(tt.body); got != tt.expected {
+				t.Errorf("estimateReadingTime() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
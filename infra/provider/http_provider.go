@@ -6,7 +6,6 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
@@ -17,28 +16,33 @@ import (
 )
 
 type HTTPProvider struct {
-	name    string
-	baseURL string
-	format  string
-	client  *httpclient.Doer
-	logger  *zap.Logger
-	timeout time.Duration
+	name          string
+	baseURL       string
+	format        string
+	client        *httpclient.Doer
+	logger        *zap.Logger
+	timeout       time.Duration
+	capabilities  Capabilities
+	fuzzyMatching bool
 }
 
-func NewHTTPProvider(name, baseURL, format string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) (*HTTPProvider, error) {
+func NewHTTPProvider(name, baseURL, format string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) (*HTTPProvider, error) {
 	if format != "json" && format != "xml" {
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 
 	doer := httpclient.NewDoer(client)
+	options := newOptions(opts)
 
 	return &HTTPProvider{
-		name:    name,
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-		format:  format,
-		client:  doer,
-		logger:  logger,
-		timeout: timeout,
+		name:          name,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		format:        format,
+		client:        doer,
+		logger:        logger,
+		timeout:       timeout,
+		capabilities:  options.Capabilities,
+		fuzzyMatching: options.FuzzyMatching,
 	}, nil
 }
 
@@ -46,8 +50,47 @@ func (p *HTTPProvider) Name() string {
 	return p.name
 }
 
+func (p *HTTPProvider) Capabilities() Capabilities {
+	return p.capabilities
+}
+
 func (p *HTTPProvider) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
-	searchURL := fmt.Sprintf("%s?q=%s", p.baseURL, url.QueryEscape(query))
+	resp, err := p.fetch(ctx, query, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.capabilities.LocalFiltering {
+		resp.Contents = filterByTitle(resp.Contents, query, p.fuzzyMatching)
+	}
+
+	return resp.Contents, nil
+}
+
+func (p *HTTPProvider) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
+	resp, err := p.fetch(ctx, "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Contents, nil
+}
+
+func (p *HTTPProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	if !p.capabilities.CanPaginate(query) {
+		contents, err := p.Search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return paginateLocally(contents, page, perPage), nil
+	}
+
+	return p.capabilities.FetchWindow(page, perPage, func(upstreamPage, upstreamPerPage int) (*SearchResponse, error) {
+		return p.fetch(ctx, query, upstreamPage, upstreamPerPage)
+	})
+}
+
+func (p *HTTPProvider) fetch(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	searchURL := p.capabilities.BuildURL(p.baseURL, query, page, perPage)
 
 	p.logger.Debug("searching provider",
 		zap.String("provider", p.name),
@@ -102,7 +145,14 @@ func (p *HTTPProvider) getAcceptHeader() string {
 }
 
 type JSONResponse struct {
-	Contents []JSONContent `json:"contents"`
+	Contents   []JSONContent  `json:"contents"`
+	Pagination JSONPagination `json:"pagination"`
+}
+
+type JSONPagination struct {
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
 }
 
 type JSONContent struct {
@@ -120,7 +170,7 @@ type JSONMetrics struct {
 	Duration string `json:"duration,omitempty"`
 }
 
-func (p *HTTPProvider) parseJSONResponse(body []byte) ([]domain.ProviderContent, error) {
+func (p *HTTPProvider) parseJSONResponse(body []byte) (*SearchResponse, error) {
 	var response JSONResponse
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
@@ -147,11 +197,26 @@ func (p *HTTPProvider) parseJSONResponse(body []byte) ([]domain.ProviderContent,
 		}
 	}
 
-	return contents, nil
+	return &SearchResponse{
+		Contents: contents,
+		Pagination: PaginationInfo{
+			CurrentPage: response.Pagination.Page,
+			PerPage:     response.Pagination.PerPage,
+			Total:       response.Pagination.Total,
+			TotalPages:  totalPages(response.Pagination.Total, response.Pagination.PerPage),
+		},
+	}, nil
 }
 
 type XMLFeed struct {
 	Items []XMLItem `xml:"items>item"`
+	Meta  XMLMeta   `xml:"meta"`
+}
+
+type XMLMeta struct {
+	TotalCount   int `xml:"total_count"`
+	CurrentPage  int `xml:"current_page"`
+	ItemsPerPage int `xml:"items_per_page"`
 }
 
 type XMLItem struct {
@@ -175,7 +240,7 @@ type XMLCategories struct {
 	Categories []string `xml:"category"`
 }
 
-func (p *HTTPProvider) parseXMLResponse(body []byte) ([]domain.ProviderContent, error) {
+func (p *HTTPProvider) parseXMLResponse(body []byte) (*SearchResponse, error) {
 	var feed XMLFeed
 	if err := xml.Unmarshal(body, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse XML response: %w", err)
@@ -204,10 +269,17 @@ func (p *HTTPProvider) parseXMLResponse(body []byte) ([]domain.ProviderContent,
 		}
 	}
 
-	return contents, nil
+	return &SearchResponse{
+		Contents: contents,
+		Pagination: PaginationInfo{
+			CurrentPage: feed.Meta.CurrentPage,
+			PerPage:     feed.Meta.ItemsPerPage,
+			Total:       feed.Meta.TotalCount,
+			TotalPages:  totalPages(feed.Meta.TotalCount, feed.Meta.ItemsPerPage),
+		},
+	}, nil
 }
 
-
 func mapContentType(t string) string {
 	t = strings.ToLower(t)
 	switch t {
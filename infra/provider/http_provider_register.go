@@ -13,12 +13,12 @@ func init() {
 	Register("http_xml", createHTTPXMLProvider)
 }
 
-func createHTTPJSONProvider(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) ContentProvider {
-	provider, _ := NewHTTPProvider(name, url, "json", timeout, client, logger)
+func createHTTPJSONProvider(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+	provider, _ := NewHTTPProvider(name, url, "json", timeout, client, logger, opts...)
 	return provider
 }
 
-func createHTTPXMLProvider(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) ContentProvider {
-	provider, _ := NewHTTPProvider(name, url, "xml", timeout, client, logger)
+func createHTTPXMLProvider(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+	provider, _ := NewHTTPProvider(name, url, "xml", timeout, client, logger, opts...)
 	return provider
 }
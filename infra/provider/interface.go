@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"search-engine/domain"
@@ -18,6 +19,20 @@ type FetchableProvider interface {
 	FetchAll(ctx context.Context) ([]domain.ProviderContent, error)
 }
 
+// IsFetchable reports whether a provider, or the provider it wraps, can
+// return its whole feed through FetchAll.
+func IsFetchable(p ContentProvider) bool {
+	for p != nil {
+		if wrapper, ok := p.(interface{ Unwrap() ContentProvider }); ok {
+			p = wrapper.Unwrap()
+			continue
+		}
+		_, ok := p.(FetchableProvider)
+		return ok
+	}
+	return false
+}
+
 type PaginatableProvider interface {
 	ContentProvider
 	SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error)
@@ -36,10 +51,11 @@ type PaginationInfo struct {
 }
 
 type ProviderResult struct {
-	Provider string
-	Contents []domain.ProviderContent
-	Error    error
-	Duration time.Duration
+	Provider   string
+	Contents   []domain.ProviderContent
+	Pagination *PaginationInfo
+	Error      error
+	Duration   time.Duration
 }
 
 type Manager struct {
@@ -96,31 +112,7 @@ func (m *Manager) SearchAllWithPagination(ctx context.Context, query string, pag
 
 	for _, p := range m.providers {
 		go func(provider ContentProvider) {
-			start := time.Now()
-
-			providerCtx, cancel := context.WithTimeout(ctx, m.timeout)
-			defer cancel()
-
-			var contents []domain.ProviderContent
-			var err error
-
-			if paginatable, ok := provider.(PaginatableProvider); ok {
-				resp, paginationErr := paginatable.SearchWithPagination(providerCtx, query, page, perPage)
-				if paginationErr != nil {
-					err = paginationErr
-				} else {
-					contents = resp.Contents
-				}
-			} else {
-				contents, err = provider.Search(providerCtx, query)
-			}
-
-			results <- ProviderResult{
-				Provider: provider.Name(),
-				Contents: contents,
-				Error:    err,
-				Duration: time.Since(start),
-			}
+			results <- m.searchPage(ctx, provider, query, page, perPage)
 		}(p)
 	}
 
@@ -137,6 +129,67 @@ func (m *Manager) SearchAllWithPagination(ctx context.Context, query string, pag
 	return collected
 }
 
+// SearchProviderWithPagination fetches a page from a single provider, e.g. to
+// read further into one provider's results than SearchAllWithPagination did.
+func (m *Manager) SearchProviderWithPagination(ctx context.Context, name, query string, page, perPage int) ProviderResult {
+	for _, p := range m.providers {
+		if p.Name() == name {
+			return m.searchPage(ctx, p, query, page, perPage)
+		}
+	}
+	return ProviderResult{Provider: name, Error: fmt.Errorf("provider %q not registered", name)}
+}
+
+func (m *Manager) searchPage(ctx context.Context, provider ContentProvider, query string, page, perPage int) ProviderResult {
+	start := time.Now()
+
+	providerCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	var contents []domain.ProviderContent
+	var pagination *PaginationInfo
+	var err error
+
+	if paginatable, ok := provider.(PaginatableProvider); ok && paginatesUpstream(provider, query) {
+		resp, paginationErr := paginatable.SearchWithPagination(providerCtx, query, page, perPage)
+		if paginationErr != nil {
+			err = paginationErr
+		} else {
+			contents = resp.Contents
+			pagination = &resp.Pagination
+		}
+	} else {
+		contents, err = provider.Search(providerCtx, query)
+		if err == nil {
+			pagination = &PaginationInfo{
+				CurrentPage: 1,
+				PerPage:     len(contents),
+				Total:       len(contents),
+				TotalPages:  1,
+			}
+		}
+	}
+
+	return ProviderResult{
+		Provider:   provider.Name(),
+		Contents:   contents,
+		Pagination: pagination,
+		Error:      err,
+		Duration:   time.Since(start),
+	}
+}
+
+// paginatesUpstream reports whether a provider can serve a page without
+// downloading its whole feed; otherwise the full filtered result is returned
+// so the caller can sort and paginate across providers.
+func paginatesUpstream(provider ContentProvider, query string) bool {
+	capable, ok := provider.(CapableProvider)
+	if !ok {
+		return false
+	}
+	return capable.Capabilities().CanPaginate(query)
+}
+
 func (m *Manager) HealthCheckAll(ctx context.Context) map[string]error {
 	healthResults := make(map[string]error)
 
@@ -150,3 +203,19 @@ func (m *Manager) HealthCheckAll(ctx context.Context) map[string]error {
 func (m *Manager) GetProviders() []ContentProvider {
 	return m.providers
 }
+
+// CircuitHeadroom returns the smallest circuit breaker headroom among the
+// registered providers, or -1 when none of them has a breaker.
+func (m *Manager) CircuitHeadroom() int {
+	headroom := -1
+	for _, p := range m.providers {
+		breaker, ok := p.(interface{ CircuitHeadroom() int })
+		if !ok {
+			continue
+		}
+		if h := breaker.CircuitHeadroom(); headroom < 0 || h < headroom {
+			headroom = h
+		}
+	}
+	return headroom
+}
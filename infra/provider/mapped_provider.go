@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"search-engine/domain"
+	"search-engine/infra/httpclient"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("mapped", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+		provider, err := NewMappedProvider(name, url, timeout, client, logger, opts...)
+		if err != nil {
+			if logger != nil {
+				logger.Error("invalid provider mapping",
+					zap.String("provider", name),
+					zap.Error(err),
+				)
+			}
+			return nil
+		}
+		return provider
+	})
+}
+
+type MappingSpec struct {
+	Source      string
+	Items       string
+	Total       string
+	Fields      FieldMapping
+	DateLayouts []string
+	TypeAliases map[string]string
+}
+
+type FieldMapping struct {
+	ID          string
+	Title       string
+	Type        string
+	Views       string
+	Likes       string
+	Reactions   string
+	ReadingTime string
+	PublishedAt string
+	Tags        string
+}
+
+var defaultDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func WithMapping(spec MappingSpec) Option {
+	return func(o *Options) {
+		o.Mapping = &spec
+	}
+}
+
+type compiledMapping struct {
+	items       Selector
+	total       Selector
+	id          Selector
+	title       Selector
+	contentType Selector
+	views       Selector
+	likes       Selector
+	reactions   Selector
+	readingTime Selector
+	publishedAt Selector
+	tags        Selector
+	dateLayouts []string
+	typeAliases map[string]string
+}
+
+func compileMapping(spec MappingSpec) (*compiledMapping, error) {
+	m := &compiledMapping{
+		dateLayouts: spec.DateLayouts,
+		typeAliases: make(map[string]string, len(spec.TypeAliases)),
+	}
+	if len(m.dateLayouts) == 0 {
+		m.dateLayouts = defaultDateLayouts
+	}
+	for alias, target := range spec.TypeAliases {
+		m.typeAliases[strings.ToLower(alias)] = strings.ToLower(target)
+	}
+
+	required := map[string]string{
+		"items":        spec.Items,
+		"id":           spec.Fields.ID,
+		"title":        spec.Fields.Title,
+		"published_at": spec.Fields.PublishedAt,
+	}
+	for field, expr := range required {
+		if strings.TrimSpace(expr) == "" {
+			return nil, fmt.Errorf("mapping field %q is required", field)
+		}
+	}
+
+	targets := []struct {
+		expr string
+		dest *Selector
+	}{
+		{spec.Items, &m.items},
+		{spec.Total, &m.total},
+		{spec.Fields.ID, &m.id},
+		{spec.Fields.Title, &m.title},
+		{spec.Fields.Type, &m.contentType},
+		{spec.Fields.Views, &m.views},
+		{spec.Fields.Likes, &m.likes},
+		{spec.Fields.Reactions, &m.reactions},
+		{spec.Fields.ReadingTime, &m.readingTime},
+		{spec.Fields.PublishedAt, &m.publishedAt},
+		{spec.Fields.Tags, &m.tags},
+	}
+	for _, target := range targets {
+		if target.expr == "" {
+			continue
+		}
+		sel, err := ParseSelector(target.expr)
+		if err != nil {
+			return nil, err
+		}
+		*target.dest = sel
+	}
+
+	return m, nil
+}
+
+type MappedProvider struct {
+	BaseHTTPProvider
+	source  string
+	mapping *compiledMapping
+	logger  *zap.Logger
+}
+
+func NewMappedProvider(name, baseURL string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) (*MappedProvider, error) {
+	options := newOptions(opts)
+	if options.Mapping == nil {
+		return nil, fmt.Errorf("provider %s: mapping spec is required", name)
+	}
+
+	source := strings.ToLower(options.Mapping.Source)
+	if source != "json" && source != "xml" {
+		return nil, fmt.Errorf("provider %s: unsupported mapping source: %s", name, options.Mapping.Source)
+	}
+
+	mapping, err := compileMapping(*options.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: %w", name, err)
+	}
+
+	return &MappedProvider{
+		BaseHTTPProvider: NewBaseHTTPProvider(BaseHTTPProviderConfig{
+			Name:          name,
+			BaseURL:       baseURL,
+			Timeout:       timeout,
+			Client:        client,
+			Logger:        logger,
+			Capabilities:  options.Capabilities,
+			FuzzyMatching: options.FuzzyMatching,
+		}),
+		source:  source,
+		mapping: mapping,
+		logger:  logger,
+	}, nil
+}
+
+func (p *MappedProvider) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
+	resp, err := p.fetch(ctx, query, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Capabilities().LocalFiltering {
+		resp.Contents = filterByTitle(resp.Contents, query, p.FuzzyMatching())
+	}
+
+	return resp.Contents, nil
+}
+
+func (p *MappedProvider) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
+	resp, err := p.fetch(ctx, "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Contents, nil
+}
+
+func (p *MappedProvider) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	caps := p.Capabilities()
+
+	if !caps.CanPaginate(query) {
+		contents, err := p.Search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return paginateLocally(contents, page, perPage), nil
+	}
+
+	return caps.FetchWindow(page, perPage, func(upstreamPage, upstreamPerPage int) (*SearchResponse, error) {
+		resp, err := p.fetch(ctx, query, upstreamPage, upstreamPerPage)
+		if err != nil {
+			return nil, err
+		}
+		resp.Pagination.CurrentPage = upstreamPage
+		resp.Pagination.PerPage = upstreamPerPage
+		resp.Pagination.TotalPages = totalPages(resp.Pagination.Total, upstreamPerPage)
+		return resp, nil
+	})
+}
+
+func (p *MappedProvider) fetch(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	body, err := p.FetchPage(ctx, p.acceptHeader(), query, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parse(body)
+}
+
+func (p *MappedProvider) parse(body []byte) (*SearchResponse, error) {
+	var doc interface{}
+	var err error
+
+	switch p.source {
+	case "xml":
+		doc, err = decodeXMLDocument(body)
+	default:
+		doc, err = decodeJSONDocument(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := flattenValues(p.mapping.items.SelectAll(doc))
+	contents := make([]domain.ProviderContent, 0, len(items))
+
+	for _, item := range items {
+		content := p.mapItem(item)
+
+		if err := domain.ValidateProviderContent(content); err != nil {
+			if p.logger != nil {
+				p.logger.Warn("invalid content from provider, skipping",
+					zap.String("provider", p.Name()),
+					zap.String("external_id", content.ExternalID),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		contents = append(contents, content)
+	}
+
+	resp := &SearchResponse{Contents: contents}
+	if !p.mapping.total.IsZero() {
+		if total, ok := p.mapping.total.SelectFirst(doc); ok {
+			resp.Pagination.Total = valueToInt(total)
+		}
+	}
+
+	return resp, nil
+}
+
+func (p *MappedProvider) mapItem(item interface{}) domain.ProviderContent {
+	m := p.mapping
+	rawData, _ := json.Marshal(item)
+
+	content := domain.ProviderContent{
+		ExternalID:  m.selectString(m.id, item),
+		Title:       m.selectString(m.title, item),
+		Type:        m.mapType(m.selectString(m.contentType, item)),
+		PublishedAt: m.parseDate(m.selectString(m.publishedAt, item)),
+		Views:       m.selectInt(m.views, item),
+		Likes:       m.selectInt(m.likes, item),
+		Reactions:   m.selectInt(m.reactions, item),
+		ReadingTime: m.selectInt(m.readingTime, item),
+		RawData:     rawData,
+	}
+
+	if !m.tags.IsZero() {
+		for _, tag := range flattenValues(m.tags.SelectAll(item)) {
+			if s := valueToString(tag); s != "" {
+				content.Tags = append(content.Tags, s)
+			}
+		}
+	}
+
+	return content
+}
+
+func (p *MappedProvider) acceptHeader() string {
+	if p.source == "xml" {
+		return "application/xml"
+	}
+	return "application/json"
+}
+
+func (m *compiledMapping) selectString(sel Selector, item interface{}) string {
+	if sel.IsZero() {
+		return ""
+	}
+	v, ok := sel.SelectFirst(item)
+	if !ok {
+		return ""
+	}
+	return valueToString(v)
+}
+
+func (m *compiledMapping) selectInt(sel Selector, item interface{}) int {
+	if sel.IsZero() {
+		return 0
+	}
+	v, ok := sel.SelectFirst(item)
+	if !ok {
+		return 0
+	}
+	return valueToInt(v)
+}
+
+func (m *compiledMapping) mapType(t string) string {
+	t = strings.ToLower(t)
+	if alias, ok := m.typeAliases[t]; ok {
+		t = alias
+	}
+	return mapContentType(t)
+}
+
+func (m *compiledMapping) parseDate(value string) time.Time {
+	for _, layout := range m.dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
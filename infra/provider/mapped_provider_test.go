@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMappedProvider_ParseJSON(t *testing.T) {
+	p, err := NewMappedProvider("partner", "http://example.com", time.Second, nil, nil, WithMapping(MappingSpec{
+		Source: "json",
+		Items:  "$.data.entries[*]",
+		Total:  "$.data.total",
+		Fields: FieldMapping{
+			ID:          "uuid",
+			Title:       "name",
+			Type:        "kind",
+			Views:       "stats.views",
+			Likes:       "stats.likes",
+			PublishedAt: "published",
+			Tags:        "labels[*]",
+		},
+		TypeAliases: map[string]string{"clip": "video"},
+	}))
+	if err != nil {
+		t.Fatalf("NewMappedProvider() error = %v", err)
+	}
+
+	body := []byte(`{"data":{"total":7,"entries":[
+		{"uuid":"a1","name":"Go Tips","kind":"Clip","stats":{"views":1200,"likes":"30"},"published":"2024-03-15T10:00:00Z","labels":["go","tips"]},
+		{"uuid":"a2","name":"Broken","kind":"clip","published":"not a date"}
+	]}}`)
+
+	resp, err := p.parse(body)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	if len(resp.Contents) != 1 {
+		t.Fatalf("len(Contents) = %d, want 1 (invalid date must be skipped)", len(resp.Contents))
+	}
+	if resp.Pagination.Total != 7 {
+		t.Errorf("Total = %d, want 7", resp.Pagination.Total)
+	}
+
+	got := resp.Contents[0]
+	if got.ExternalID != "a1" || got.Title != "Go Tips" || got.Type != "video" {
+		t.Errorf("unexpected content: %+v", got)
+	}
+	if got.Views != 1200 || got.Likes != 30 {
+		t.Errorf("metrics = %d/%d, want 1200/30", got.Views, got.Likes)
+	}
+	if len(got.Tags) != 2 || got.Tags[1] != "tips" {
+		t.Errorf("Tags = %v, want [go tips]", got.Tags)
+	}
+}
+
+func TestMappedProvider_ParseXML(t *testing.T) {
+	p, err := NewMappedProvider("partner", "http://example.com", time.Second, nil, nil, WithMapping(MappingSpec{
+		Source: "xml",
+		Items:  "/rss/entries/entry",
+		Fields: FieldMapping{
+			ID:          "@ref",
+			Title:       "heading",
+			Type:        "format",
+			Reactions:   "counters/reactions",
+			ReadingTime: "counters/minutes",
+			PublishedAt: "date",
+			Tags:        "topics/topic",
+		},
+		DateLayouts: []string{"02.01.2006"},
+		TypeAliases: map[string]string{"post": "text"},
+	}))
+	if err != nil {
+		t.Fatalf("NewMappedProvider() error = %v", err)
+	}
+
+	body := []byte(`<rss><entries>
+		<entry ref="x1"><heading>Single topic</heading><format>post</format><counters><reactions>12</reactions><minutes>4 min</minutes></counters><date>15.03.2024</date><topics><topic>go</topic></topics></entry>
+		<entry ref="x2"><heading>Two topics</heading><format>post</format><date>16.03.2024</date><topics><topic>go</topic><topic>db</topic></topics></entry>
+	</entries></rss>`)
+
+	resp, err := p.parse(body)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	if len(resp.Contents) != 2 {
+		t.Fatalf("len(Contents) = %d, want 2", len(resp.Contents))
+	}
+
+	first := resp.Contents[0]
+	if first.ExternalID != "x1" || first.Type != "text" || first.Reactions != 12 || first.ReadingTime != 4 {
+		t.Errorf("unexpected content: %+v", first)
+	}
+	if first.PublishedAt.Day() != 15 || len(first.Tags) != 1 {
+		t.Errorf("PublishedAt = %v, Tags = %v", first.PublishedAt, first.Tags)
+	}
+	if len(resp.Contents[1].Tags) != 2 {
+		t.Errorf("Tags = %v, want 2 tags", resp.Contents[1].Tags)
+	}
+}
+
+func TestNewMappedProvider_RequiresMapping(t *testing.T) {
+	if _, err := NewMappedProvider("partner", "http://example.com", time.Second, nil, nil); err == nil {
+		t.Error("expected error without mapping spec")
+	}
+
+	_, err := NewMappedProvider("partner", "http://example.com", time.Second, nil, nil, WithMapping(MappingSpec{
+		Source: "json",
+		Items:  "items",
+		Fields: FieldMapping{ID: "id", PublishedAt: "date"},
+	}))
+	if err == nil {
+		t.Error("expected error when title selector is missing")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"search-engine/domain"
@@ -14,8 +13,8 @@ import (
 )
 
 func init() {
-	Register("json", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) ContentProvider {
-		return NewProvider1(name, url, timeout, client, logger)
+	Register("json", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+		return NewProvider1(name, url, timeout, client, logger, opts...)
 	})
 }
 
@@ -48,64 +47,37 @@ type Provider1Metrics struct {
 	Duration string `json:"duration"`
 }
 
-func NewProvider1(name, baseURL string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) *Provider1 {
+func NewProvider1(name, baseURL string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) *Provider1 {
+	options := newOptions(opts)
+
 	return &Provider1{
 		BaseHTTPProvider: NewBaseHTTPProvider(BaseHTTPProviderConfig{
-			Name:    name,
-			BaseURL: baseURL,
-			Timeout: timeout,
-			Client:  client,
-			Logger:  logger,
+			Name:          name,
+			BaseURL:       baseURL,
+			Timeout:       timeout,
+			Client:        client,
+			Logger:        logger,
+			Capabilities:  options.Capabilities,
+			FuzzyMatching: options.FuzzyMatching,
 		}),
 		logger: logger,
 	}
 }
 
 func (p *Provider1) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
-	body, err := p.FetchData(ctx, "application/json")
+	body, err := p.FetchPage(ctx, "application/json", query, 0, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	var apiResp Provider1Response
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	apiResp, err := p.parseResponse(body)
+	if err != nil {
+		return nil, err
 	}
 
-	contents := make([]domain.ProviderContent, 0, len(apiResp.Contents))
-	query = strings.ToLower(query)
-
-	for _, item := range apiResp.Contents {
-		if query != "" && !strings.Contains(strings.ToLower(item.Title), query) {
-			continue
-		}
-
-		publishedAt, _ := time.Parse(time.RFC3339, item.PublishedAt)
-		rawData, _ := json.Marshal(item)
-
-		content := domain.ProviderContent{
-			ExternalID:  item.ID,
-			Title:       item.Title,
-			Type:        mapContentType(item.Type),
-			PublishedAt: publishedAt,
-			Views:       item.Metrics.Views,
-			Likes:       item.Metrics.Likes,
-			Tags:        item.Tags,
-			RawData:     rawData,
-		}
-
-		if err := domain.ValidateProviderContent(content); err != nil {
-			if p.logger != nil {
-				p.logger.Warn("invalid content from provider, skipping",
-					zap.String("provider", p.Name()),
-					zap.String("external_id", content.ExternalID),
-					zap.Error(err),
-				)
-			}
-			continue
-		}
-
-		contents = append(contents, content)
+	contents := p.mapContents(apiResp.Contents)
+	if p.Capabilities().LocalFiltering {
+		contents = filterByTitle(contents, query, p.FuzzyMatching())
 	}
 
 	return contents, nil
@@ -117,64 +89,60 @@ func (p *Provider1) FetchAll(ctx context.Context) ([]domain.ProviderContent, err
 		return nil, err
 	}
 
-	var apiResp Provider1Response
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	apiResp, err := p.parseResponse(body)
+	if err != nil {
+		return nil, err
 	}
 
-	contents := make([]domain.ProviderContent, 0, len(apiResp.Contents))
+	return p.mapContents(apiResp.Contents), nil
+}
 
-	for _, item := range apiResp.Contents {
-		publishedAt, _ := time.Parse(time.RFC3339, item.PublishedAt)
-		rawData, _ := json.Marshal(item)
+func (p *Provider1) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	caps := p.Capabilities()
 
-		content := domain.ProviderContent{
-			ExternalID:  item.ID,
-			Title:       item.Title,
-			Type:        mapContentType(item.Type),
-			PublishedAt: publishedAt,
-			Views:       item.Metrics.Views,
-			Likes:       item.Metrics.Likes,
-			Tags:        item.Tags,
-			RawData:     rawData,
+	if !caps.CanPaginate(query) {
+		contents, err := p.Search(ctx, query)
+		if err != nil {
+			return nil, err
 		}
+		return paginateLocally(contents, page, perPage), nil
+	}
 
-		if err := domain.ValidateProviderContent(content); err != nil {
-			if p.logger != nil {
-				p.logger.Warn("invalid content from provider, skipping",
-					zap.String("provider", p.Name()),
-					zap.String("external_id", content.ExternalID),
-					zap.Error(err),
-				)
-			}
-			continue
+	return caps.FetchWindow(page, perPage, func(upstreamPage, upstreamPerPage int) (*SearchResponse, error) {
+		body, err := p.FetchPage(ctx, "application/json", query, upstreamPage, upstreamPerPage)
+		if err != nil {
+			return nil, err
 		}
 
-		contents = append(contents, content)
-	}
+		apiResp, err := p.parseResponse(body)
+		if err != nil {
+			return nil, err
+		}
 
-	return contents, nil
+		return &SearchResponse{
+			Contents: p.mapContents(apiResp.Contents),
+			Pagination: PaginationInfo{
+				CurrentPage: upstreamPage,
+				PerPage:     upstreamPerPage,
+				Total:       apiResp.Pagination.Total,
+				TotalPages:  totalPages(apiResp.Pagination.Total, upstreamPerPage),
+			},
+		}, nil
+	})
 }
 
-func (p *Provider1) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
-	body, err := p.FetchData(ctx, "application/json")
-	if err != nil {
-		return nil, err
-	}
-
+func (p *Provider1) parseResponse(body []byte) (*Provider1Response, error) {
 	var apiResp Provider1Response
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
+	return &apiResp, nil
+}
 
-	allContents := make([]domain.ProviderContent, 0)
-	query = strings.ToLower(query)
-
-	for _, item := range apiResp.Contents {
-		if query != "" && !strings.Contains(strings.ToLower(item.Title), query) {
-			continue
-		}
+func (p *Provider1) mapContents(items []Provider1Content) []domain.ProviderContent {
+	contents := make([]domain.ProviderContent, 0, len(items))
 
+	for _, item := range items {
 		publishedAt, _ := time.Parse(time.RFC3339, item.PublishedAt)
 		rawData, _ := json.Marshal(item)
 
@@ -200,41 +168,8 @@ func (p *Provider1) SearchWithPagination(ctx context.Context, query string, page
 			continue
 		}
 
-		allContents = append(allContents, content)
-	}
-
-	total := len(allContents)
-	totalPages := (total + perPage - 1) / perPage
-
-	start := (page - 1) * perPage
-	end := start + perPage
-
-	if start >= total {
-		return &SearchResponse{
-			Contents: []domain.ProviderContent{},
-			Pagination: PaginationInfo{
-				CurrentPage: page,
-				PerPage:     perPage,
-				Total:       total,
-				TotalPages:  totalPages,
-			},
-		}, nil
-	}
-
-	if end > total {
-		end = total
+		contents = append(contents, content)
 	}
 
-	pagedContents := allContents[start:end]
-
-	return &SearchResponse{
-		Contents: pagedContents,
-		Pagination: PaginationInfo{
-			CurrentPage: page,
-			PerPage:     perPage,
-			Total:       total,
-			TotalPages:  totalPages,
-		},
-	}, nil
+	return contents
 }
-
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"strings"
 	"time"
 
 	"search-engine/domain"
@@ -15,8 +14,8 @@ import (
 )
 
 func init() {
-	Register("xml", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) ContentProvider {
-		return NewProvider2(name, url, timeout, client, logger)
+	Register("xml", func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider {
+		return NewProvider2(name, url, timeout, client, logger, opts...)
 	})
 }
 
@@ -46,12 +45,12 @@ type Provider2Item struct {
 }
 
 type Provider2Stats struct {
-	Views    int    `xml:"views"`
-	Likes    int    `xml:"likes"`
-	Duration string `xml:"duration"`
-	ReadingTime int `xml:"reading_time"`
-	Reactions   int `xml:"reactions"`
-	Comments    int `xml:"comments"`
+	Views       int    `xml:"views"`
+	Likes       int    `xml:"likes"`
+	Duration    string `xml:"duration"`
+	ReadingTime int    `xml:"reading_time"`
+	Reactions   int    `xml:"reactions"`
+	Comments    int    `xml:"comments"`
 }
 
 type Provider2Categories struct {
@@ -64,76 +63,102 @@ type Provider2Meta struct {
 	ItemsPerPage int `xml:"items_per_page"`
 }
 
-func NewProvider2(name, baseURL string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) *Provider2 {
+func NewProvider2(name, baseURL string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) *Provider2 {
+	options := newOptions(opts)
+
 	return &Provider2{
 		BaseHTTPProvider: NewBaseHTTPProvider(BaseHTTPProviderConfig{
-			Name:    name,
-			BaseURL: baseURL,
-			Timeout: timeout,
-			Client:  client,
-			Logger:  logger,
+			Name:          name,
+			BaseURL:       baseURL,
+			Timeout:       timeout,
+			Client:        client,
+			Logger:        logger,
+			Capabilities:  options.Capabilities,
+			FuzzyMatching: options.FuzzyMatching,
 		}),
 		logger: logger,
 	}
 }
 
 func (p *Provider2) Search(ctx context.Context, query string) ([]domain.ProviderContent, error) {
-	body, err := p.FetchData(ctx, "application/xml")
+	body, err := p.FetchPage(ctx, "application/xml", query, 0, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	var feed Provider2Feed
-	if err := xml.Unmarshal(body, &feed); err != nil {
-		return nil, fmt.Errorf("failed to parse XML response: %w", err)
+	feed, err := p.parseFeed(body)
+	if err != nil {
+		return nil, err
 	}
 
-	contents := make([]domain.ProviderContent, 0, len(feed.Items.Items))
-	query = strings.ToLower(query)
-
-	for _, item := range feed.Items.Items {
-		if query != "" && !strings.Contains(strings.ToLower(item.Headline), query) {
-			continue
-		}
-
-		content := p.mapToProviderContent(item)
-
-		if err := domain.ValidateProviderContent(content); err != nil {
-			if p.logger != nil {
-				p.logger.Warn("invalid content from provider, skipping",
-					zap.String("provider", p.Name()),
-					zap.String("external_id", content.ExternalID),
-					zap.Error(err),
-				)
-			}
-			continue
-		}
-
-		contents = append(contents, content)
+	contents := p.mapContents(feed.Items.Items)
+	if p.Capabilities().LocalFiltering {
+		contents = filterByTitle(contents, query, p.FuzzyMatching())
 	}
 
 	return contents, nil
 }
 
-func (p *Provider2) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+func (p *Provider2) FetchAll(ctx context.Context) ([]domain.ProviderContent, error) {
 	body, err := p.FetchData(ctx, "application/xml")
 	if err != nil {
 		return nil, err
 	}
 
+	feed, err := p.parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.mapContents(feed.Items.Items), nil
+}
+
+func (p *Provider2) SearchWithPagination(ctx context.Context, query string, page, perPage int) (*SearchResponse, error) {
+	caps := p.Capabilities()
+
+	if !caps.CanPaginate(query) {
+		contents, err := p.Search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return paginateLocally(contents, page, perPage), nil
+	}
+
+	return caps.FetchWindow(page, perPage, func(upstreamPage, upstreamPerPage int) (*SearchResponse, error) {
+		body, err := p.FetchPage(ctx, "application/xml", query, upstreamPage, upstreamPerPage)
+		if err != nil {
+			return nil, err
+		}
+
+		feed, err := p.parseFeed(body)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SearchResponse{
+			Contents: p.mapContents(feed.Items.Items),
+			Pagination: PaginationInfo{
+				CurrentPage: upstreamPage,
+				PerPage:     upstreamPerPage,
+				Total:       feed.Meta.TotalCount,
+				TotalPages:  totalPages(feed.Meta.TotalCount, upstreamPerPage),
+			},
+		}, nil
+	})
+}
+
+func (p *Provider2) parseFeed(body []byte) (*Provider2Feed, error) {
 	var feed Provider2Feed
 	if err := xml.Unmarshal(body, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse XML response: %w", err)
 	}
+	return &feed, nil
+}
 
-	allContents := make([]domain.ProviderContent, 0)
-	query = strings.ToLower(query)
-
-	for _, item := range feed.Items.Items {
-		if query != "" && !strings.Contains(strings.ToLower(item.Headline), query) {
-			continue
-		}
+func (p *Provider2) mapContents(items []Provider2Item) []domain.ProviderContent {
+	contents := make([]domain.ProviderContent, 0, len(items))
 
+	for _, item := range items {
 		content := p.mapToProviderContent(item)
 
 		if err := domain.ValidateProviderContent(content); err != nil {
@@ -147,42 +172,10 @@ func (p *Provider2) SearchWithPagination(ctx context.Context, query string, page
 			continue
 		}
 
-		allContents = append(allContents, content)
-	}
-
-	total := len(allContents)
-	totalPages := (total + perPage - 1) / perPage
-
-	start := (page - 1) * perPage
-	end := start + perPage
-
-	if start >= total {
-		return &SearchResponse{
-			Contents: []domain.ProviderContent{},
-			Pagination: PaginationInfo{
-				CurrentPage: page,
-				PerPage:     perPage,
-				Total:       total,
-				TotalPages:  totalPages,
-			},
-		}, nil
-	}
-
-	if end > total {
-		end = total
+		contents = append(contents, content)
 	}
 
-	pagedContents := allContents[start:end]
-
-	return &SearchResponse{
-		Contents: pagedContents,
-		Pagination: PaginationInfo{
-			CurrentPage: page,
-			PerPage:     perPage,
-			Total:       total,
-			TotalPages:  totalPages,
-		},
-	}, nil
+	return contents
 }
 
 func (p *Provider2) mapToProviderContent(item Provider2Item) domain.ProviderContent {
@@ -210,4 +203,3 @@ func (p *Provider2) mapToProviderContent(item Provider2Item) domain.ProviderCont
 
 	return content
 }
-
@@ -10,7 +10,7 @@ import (
 	"go.uber.org/zap"
 )
 
-type ProviderFactory func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) ContentProvider
+type ProviderFactory func(name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) ContentProvider
 
 type Registry struct {
 	mu        sync.RWMutex
@@ -38,12 +38,16 @@ func GetFactory(formatType string) (ProviderFactory, error) {
 	return factory, nil
 }
 
-func CreateProvider(formatType, name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger) (ContentProvider, error) {
+func CreateProvider(formatType, name, url string, timeout time.Duration, client httpclient.HTTPClient, logger *zap.Logger, opts ...Option) (ContentProvider, error) {
 	factory, err := GetFactory(formatType)
 	if err != nil {
 		return nil, err
 	}
-	return factory(name, url, timeout, client, logger), nil
+	provider := factory(name, url, timeout, client, logger, opts...)
+	if provider == nil {
+		return nil, fmt.Errorf("failed to create %s provider: %s", formatType, name)
+	}
+	return provider, nil
 }
 
 func ListRegisteredFormats() []string {
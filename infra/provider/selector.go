@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+type selectorStep struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+// Selector is a small JSONPath/XPath subset evaluated over decoded documents:
+// "$.data.items[*].title", "feed/items/item", "stats.views", "@type", "text()".
+type Selector struct {
+	raw   string
+	steps []selectorStep
+}
+
+func ParseSelector(expr string) (Selector, error) {
+	sel := Selector{raw: expr}
+
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimLeft(expr, "./")
+
+	for expr != "" {
+		switch {
+		case expr[0] == '.' || expr[0] == '/':
+			expr = expr[1:]
+		case expr[0] == '[':
+			end := strings.IndexByte(expr, ']')
+			if end < 0 {
+				return Selector{}, fmt.Errorf("invalid selector %q: unterminated '['", sel.raw)
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+
+			if inner == "*" {
+				sel.steps = append(sel.steps, selectorStep{kind: stepWildcard})
+				continue
+			}
+
+			if quoted := strings.Trim(inner, `'"`); quoted != inner {
+				sel.steps = append(sel.steps, selectorStep{kind: stepKey, key: quoted})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return Selector{}, fmt.Errorf("invalid selector %q: bad index %q", sel.raw, inner)
+			}
+			sel.steps = append(sel.steps, selectorStep{kind: stepIndex, index: index})
+		default:
+			end := strings.IndexAny(expr, "./[")
+			if end < 0 {
+				end = len(expr)
+			}
+			key := expr[:end]
+			expr = expr[end:]
+
+			switch key {
+			case "*":
+				sel.steps = append(sel.steps, selectorStep{kind: stepWildcard})
+			case "text()":
+				sel.steps = append(sel.steps, selectorStep{kind: stepKey, key: xmlTextKey})
+			default:
+				sel.steps = append(sel.steps, selectorStep{kind: stepKey, key: key})
+			}
+		}
+	}
+
+	return sel, nil
+}
+
+func (s Selector) IsZero() bool {
+	return s.raw == ""
+}
+
+func (s Selector) String() string {
+	return s.raw
+}
+
+func (s Selector) SelectAll(node interface{}) []interface{} {
+	current := []interface{}{node}
+
+	for _, step := range s.steps {
+		var next []interface{}
+		for _, n := range current {
+			next = append(next, step.apply(n)...)
+		}
+		current = next
+	}
+
+	return current
+}
+
+func (s Selector) SelectFirst(node interface{}) (interface{}, bool) {
+	values := flattenValues(s.SelectAll(node))
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+func (st selectorStep) apply(node interface{}) []interface{} {
+	switch st.kind {
+	case stepKey:
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if child, ok := v[st.key]; ok {
+				return []interface{}{child}
+			}
+		case []interface{}:
+			var out []interface{}
+			for _, item := range v {
+				out = append(out, st.apply(item)...)
+			}
+			return out
+		}
+	case stepIndex:
+		if v, ok := node.([]interface{}); ok {
+			index := st.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				return []interface{}{v[index]}
+			}
+			return nil
+		}
+		if st.index == 0 {
+			return []interface{}{node}
+		}
+	case stepWildcard:
+		switch v := node.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			out := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				out = append(out, child)
+			}
+			return out
+		default:
+			return []interface{}{node}
+		}
+	}
+	return nil
+}
+
+func flattenValues(values []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if list, ok := v.([]interface{}); ok {
+			out = append(out, flattenValues(list)...)
+			continue
+		}
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func valueToString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case map[string]interface{}:
+		if text, ok := val[xmlTextKey]; ok {
+			return valueToString(text)
+		}
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func valueToInt(v interface{}) int {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+
+	s := valueToString(v)
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || (end == 0 && s[end] == '-')) {
+		end++
+	}
+
+	f, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
+	}
+	return int(f)
+}
+
+func decodeJSONDocument(body []byte) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return doc, nil
+}
+
+const xmlTextKey = "#text"
+
+// decodeXMLDocument turns an XML document into the same map/slice shape that
+// encoding/json produces: child elements become keys (repeated ones become
+// slices), attributes become "@name" and character data becomes "#text".
+// Elements that only carry text collapse to plain strings.
+func decodeXMLDocument(body []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	root := map[string]interface{}{}
+	type frame struct {
+		name     string
+		node     map[string]interface{}
+		text     strings.Builder
+		children int
+	}
+	stack := []*frame{{node: root}}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XML response: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := map[string]interface{}{}
+			for _, attr := range t.Attr {
+				node["@"+attr.Name.Local] = attr.Value
+			}
+			stack[len(stack)-1].children++
+			stack = append(stack, &frame{name: t.Name.Local, node: node})
+		case xml.CharData:
+			stack[len(stack)-1].text.Write(t)
+		case xml.EndElement:
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			parent := stack[len(stack)-1].node
+
+			var value interface{} = current.node
+			text := strings.TrimSpace(current.text.String())
+			if len(current.node) == 0 && current.children == 0 {
+				value = text
+			} else if text != "" {
+				current.node[xmlTextKey] = text
+			}
+
+			switch existing := parent[current.name].(type) {
+			case nil:
+				parent[current.name] = value
+			case []interface{}:
+				parent[current.name] = append(existing, value)
+			default:
+				parent[current.name] = []interface{}{existing, value}
+			}
+		}
+	}
+
+	return root, nil
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"search-engine/app/search"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	queryLogKeyPrefix = "search:queries:"
+	queryLogTopKey    = "search:queries:top"
+)
+
+// queryLog counts searches in one sorted set per window, shared between
+// instances. Rankings cover the current and the previous window, so a search
+// stops counting at most two windows after it was last made. Each set keeps
+// only the maxSearches most frequent searches.
+type queryLog struct {
+	client      *redis.Client
+	window      time.Duration
+	maxSearches int
+}
+
+func NewQueryLog(cache *RedisCache, window time.Duration, maxSearches int) search.QueryLog {
+	if window <= 0 {
+		window = time.Hour
+	}
+	if maxSearches <= 0 {
+		maxSearches = 10000
+	}
+	return &queryLog{client: cache.client, window: window, maxSearches: maxSearches}
+}
+
+func (l *queryLog) bucketKey(t time.Time) string {
+	return fmt.Sprintf("%s%d", queryLogKeyPrefix, t.UnixMilli()/l.window.Milliseconds())
+}
+
+func (l *queryLog) Record(ctx context.Context, search string) error {
+	key := l.bucketKey(time.Now())
+	_, err := l.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, key, 1, search)
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-l.maxSearches-1))
+		pipe.PExpire(ctx, key, 2*l.window)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("query log record failed: %w", err)
+	}
+	return nil
+}
+
+func (l *queryLog) Top(ctx context.Context, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var top *redis.StringSliceCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, queryLogTopKey, &redis.ZStore{
+			Keys: []string{l.bucketKey(now), l.bucketKey(now.Add(-l.window))},
+		})
+		top = pipe.ZRevRange(ctx, queryLogTopKey, 0, int64(n-1))
+		pipe.Del(ctx, queryLogTopKey)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query log top failed: %w", err)
+	}
+	return top.Val(), nil
+}
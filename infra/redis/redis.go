@@ -2,18 +2,32 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
+	"search-engine/app/search"
+	"search-engine/pkg/codec"
+
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type RedisCache struct {
 	client *redis.Client
+	codec  *codec.Codec
 }
 
-func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
+// NewRedisCache connects to Redis. Values are encoded with entryCodec, or as
+// JSON when it is nil.
+func NewRedisCache(addr, password string, db int, entryCodec *codec.Codec) (*RedisCache, error) {
+	if entryCodec == nil {
+		var err error
+		if entryCodec, err = codec.New(codec.Config{}); err != nil {
+			return nil, fmt.Errorf("failed to create cache codec: %w", err)
+		}
+	}
+
 	client := redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: password,
@@ -27,7 +41,7 @@ func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
 		return nil, fmt.Errorf("redis connection failed: %w", err)
 	}
 
-	return &RedisCache{client: client}, nil
+	return &RedisCache{client: client, codec: entryCodec}, nil
 }
 
 func (c *RedisCache) Close() error {
@@ -35,7 +49,7 @@ func (c *RedisCache) Close() error {
 }
 
 func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
-	val, err := c.client.Get(ctx, key).Result()
+	val, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
 		return ErrCacheMiss
 	}
@@ -43,15 +57,43 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 		return fmt.Errorf("cache get failed: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(val), dest); err != nil {
+	if err := c.codec.Unmarshal(val, dest); err != nil {
 		return fmt.Errorf("cache unmarshal failed: %w", err)
 	}
 
 	return nil
 }
 
+// getWithTTL reads key like Get and also returns its remaining lifetime,
+// which is negative when the key does not expire.
+func (c *RedisCache) getWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	var val *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		val = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return 0, ErrCacheMiss
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cache get failed: %w", err)
+	}
+
+	data, err := val.Bytes()
+	if err != nil {
+		return 0, fmt.Errorf("cache get failed: %w", err)
+	}
+	if err := c.codec.Unmarshal(data, dest); err != nil {
+		return 0, fmt.Errorf("cache unmarshal failed: %w", err)
+	}
+
+	return ttl.Val(), nil
+}
+
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	data, err := json.Marshal(value)
+	data, err := c.codec.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("cache marshal failed: %w", err)
 	}
@@ -83,6 +125,98 @@ func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// unlockScript deletes a lock only if it still holds the caller's token, so
+// a lock that expired and was taken over is not released by its old owner.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock takes key for ttl if nobody holds it and returns the token to release
+// it with. It returns ErrLockHeld otherwise.
+func (c *RedisCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	token := uuid.NewString()
+	ok, err := c.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", fmt.Errorf("cache lock failed: %w", err)
+	}
+	if !ok {
+		return "", ErrLockHeld
+	}
+	return token, nil
+}
+
+func (c *RedisCache) Unlock(ctx context.Context, key, token string) error {
+	if err := unlockScript.Run(ctx, c.client, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("cache unlock failed: %w", err)
+	}
+	return nil
+}
+
+const tagKeyPrefix = "tag:"
+
+// Tag sets are sorted sets scored by the expiry of each member, so members
+// that expired on their own are pruned as new ones arrive.
+func (c *RedisCache) Tag(ctx context.Context, key string, tags []string, ttl time.Duration) error {
+	now := time.Now()
+	expires := float64(now.Add(ttl).UnixMilli())
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			tagKey := tagKeyPrefix + tag
+			pipe.ZRemRangeByScore(ctx, tagKey, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+			pipe.ZAdd(ctx, tagKey, redis.Z{Score: expires, Member: key})
+			pipe.PExpire(ctx, tagKey, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache tag failed: %w", err)
+	}
+	return nil
+}
+
+// invalidateScript deletes the live members of the given tag sets and the
+// sets themselves, returning the deleted keys.
+var invalidateScript = redis.NewScript(`
+local dropped, seen = {}, {}
+for _, tag in ipairs(KEYS) do
+	for _, key in ipairs(redis.call("ZRANGEBYSCORE", tag, ARGV[1], "+inf")) do
+		if not seen[key] then
+			seen[key] = true
+			table.insert(dropped, key)
+		end
+	end
+	redis.call("DEL", tag)
+end
+for i = 1, #dropped, 500 do
+	redis.call("DEL", unpack(dropped, i, math.min(i + 499, #dropped)))
+end
+return dropped`)
+
+func (c *RedisCache) Invalidate(ctx context.Context, tags ...string) (int, error) {
+	keys, err := c.invalidate(ctx, tags)
+	return len(keys), err
+}
+
+func (c *RedisCache) invalidate(ctx context.Context, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		tagKeys[i] = tagKeyPrefix + tag
+	}
+
+	keys, err := invalidateScript.Run(ctx, c.client, tagKeys, time.Now().UnixMilli()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("cache invalidate failed: %w", err)
+	}
+	return keys, nil
+}
+
 func (c *RedisCache) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
 }
@@ -91,4 +225,7 @@ func GenerateSearchKey(query string, tags, contentTypes []string, sortBy string,
 	return fmt.Sprintf("search:%s:%v:%v:%s:%d:%d", query, tags, contentTypes, sortBy, page, perPage)
 }
 
-var ErrCacheMiss = fmt.Errorf("cache miss")
+var (
+	ErrCacheMiss = search.ErrCacheMiss
+	ErrLockHeld  = search.ErrLockHeld
+)
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"search-engine/app/suggest"
+	"search-engine/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	suggestPrefixKeyPrefix = "suggest:prefix:"
+	suggestTextKey         = "suggest:text"
+)
+
+// suggestIndex shares the prefix index between instances. Every prefix of a
+// suggestion's keys, up to prefixLength characters, has a sorted set of
+// suggest.ID members scored by popularity, so completions are read in
+// popularity order. The display text lives in a hash keyed by suggest.ID.
+type suggestIndex struct {
+	client       *redis.Client
+	prefixLength int
+	pageSize     int
+}
+
+func NewSuggestIndex(cache *RedisCache, prefixLength, pageSize int) suggest.Index {
+	if prefixLength <= 0 {
+		prefixLength = 10
+	}
+	if pageSize <= 0 {
+		pageSize = 500
+	}
+	return &suggestIndex{client: cache.client, prefixLength: prefixLength, pageSize: pageSize}
+}
+
+func (i *suggestIndex) Add(ctx context.Context, suggestions []domain.Suggestion) error {
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	pipe := i.client.Pipeline()
+	for _, s := range suggestions {
+		id := suggest.ID(s)
+		member := redis.Z{Score: float64(s.Popularity), Member: id}
+		for _, prefix := range i.prefixes(suggest.Keys(s.Text)) {
+			pipe.ZAddGT(ctx, suggestPrefixKeyPrefix+prefix, member)
+		}
+		pipe.HSet(ctx, suggestTextKey, id, s.Text)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("suggest index add failed: %w", err)
+	}
+	return nil
+}
+
+// prefixes returns the distinct prefixes of keys up to prefixLength
+// characters.
+func (i *suggestIndex) prefixes(keys []string) []string {
+	seen := make(map[string]bool)
+	var prefixes []string
+	for _, key := range keys {
+		runes := []rune(key)
+		for n := 1; n <= min(len(runes), i.prefixLength); n++ {
+			prefix := string(runes[:n])
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+func (i *suggestIndex) Complete(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	prefix = suggest.Normalize(prefix)
+	if prefix == "" {
+		return []domain.Suggestion{}, nil
+	}
+
+	key := suggestPrefixKeyPrefix + prefix
+	if runes := []rune(prefix); len(runes) > i.prefixLength {
+		key = suggestPrefixKeyPrefix + string(runes[:i.prefixLength])
+	}
+
+	// Members come in descending popularity. Longer prefixes than the indexed
+	// ones are matched against the text in the ID. Reading stops once the
+	// popularity drops below that of the limit-th match, so ties with it are
+	// still ranked by text.
+	var matches []redis.Z
+	for start := int64(0); ; start += int64(i.pageSize) {
+		page, err := i.client.ZRevRangeWithScores(ctx, key, start, start+int64(i.pageSize)-1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("suggest index range failed: %w", err)
+		}
+		for _, member := range page {
+			if id, ok := member.Member.(string); ok && matchesPrefix(id, prefix) {
+				matches = append(matches, member)
+			}
+		}
+		if len(page) < i.pageSize ||
+			(limit > 0 && len(matches) >= limit && page[len(page)-1].Score < matches[limit-1].Score) {
+			break
+		}
+	}
+	if len(matches) == 0 {
+		return []domain.Suggestion{}, nil
+	}
+
+	ids := make([]string, len(matches))
+	for j, member := range matches {
+		ids[j] = member.Member.(string)
+	}
+	texts, err := i.client.HMGet(ctx, suggestTextKey, ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("suggest index lookup failed: %w", err)
+	}
+
+	suggestions := make([]domain.Suggestion, 0, len(ids))
+	for j, id := range ids {
+		text, ok := texts[j].(string)
+		if !ok {
+			continue
+		}
+		suggestionType := domain.SuggestionType(id[:strings.Index(id, "\x00")])
+		suggestions = append(suggestions, domain.Suggestion{
+			Text:       text,
+			Type:       suggestionType,
+			Popularity: int64(matches[j].Score),
+		})
+	}
+
+	return suggest.Rank(suggestions, limit), nil
+}
+
+// matchesPrefix tells whether one of the keys of the suggestion with the
+// given ID starts with prefix. The ID holds the normalized text, whose words
+// the keys start at.
+func matchesPrefix(id, prefix string) bool {
+	text := id[strings.Index(id, "\x00")+1:]
+	return strings.HasPrefix(text, prefix) || strings.Contains(text, " "+prefix)
+}
@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"search-engine/app/search"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const invalidationChannel = "cache:invalidate"
+
+// TieredCache keeps values read from Redis in a local cache for up to
+// localTTL. Every write, delete and invalidation is announced on a pub/sub
+// channel, so the other instances drop their local copies. An announcement
+// missed while the subscription reconnects leaves a stale local copy for at
+// most localTTL.
+type TieredCache struct {
+	remote   *RedisCache
+	local    search.Cache
+	localTTL time.Duration
+	id       string
+	pubsub   *redis.PubSub
+}
+
+func NewTieredCache(remote *RedisCache, local search.Cache, localTTL time.Duration) *TieredCache {
+	c := &TieredCache{
+		remote:   remote,
+		local:    local,
+		localTTL: localTTL,
+		id:       uuid.NewString(),
+		pubsub:   remote.client.Subscribe(context.Background(), invalidationChannel),
+	}
+	go c.listen()
+	return c
+}
+
+func (c *TieredCache) listen() {
+	for msg := range c.pubsub.Channel() {
+		origin, keys, ok := strings.Cut(msg.Payload, "\n")
+		if !ok || origin == c.id {
+			continue
+		}
+		for _, key := range strings.Split(keys, "\n") {
+			c.local.Delete(context.Background(), key)
+		}
+	}
+}
+
+func (c *TieredCache) Close() error {
+	return c.pubsub.Close()
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string, dest interface{}) error {
+	if err := c.local.Get(ctx, key, dest); err == nil {
+		return nil
+	}
+
+	ttl, err := c.remote.getWithTTL(ctx, key, dest)
+	if err != nil {
+		return err
+	}
+	return c.local.Set(ctx, key, reflect.ValueOf(dest).Elem().Interface(), c.localLifetime(ttl))
+}
+
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	if err := c.local.Set(ctx, key, value, c.localLifetime(ttl)); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, key)
+}
+
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := c.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := c.local.Delete(ctx, key); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, key)
+}
+
+func (c *TieredCache) Tag(ctx context.Context, key string, tags []string, ttl time.Duration) error {
+	if err := c.remote.Tag(ctx, key, tags, ttl); err != nil {
+		return err
+	}
+	return c.local.Tag(ctx, key, tags, c.localLifetime(ttl))
+}
+
+// Invalidate drops the keys Redis has under tags. Local copies are found
+// through those keys, since other instances did not tag what they read.
+func (c *TieredCache) Invalidate(ctx context.Context, tags ...string) (int, error) {
+	keys, err := c.remote.invalidate(ctx, tags)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := c.local.Invalidate(ctx, tags...); err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := c.local.Delete(ctx, key); err != nil {
+			return 0, err
+		}
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return len(keys), c.invalidate(ctx, keys...)
+}
+
+func (c *TieredCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return c.remote.Lock(ctx, key, ttl)
+}
+
+func (c *TieredCache) Unlock(ctx context.Context, key, token string) error {
+	return c.remote.Unlock(ctx, key, token)
+}
+
+// localLifetime keeps local copies from outliving the Redis key.
+func (c *TieredCache) localLifetime(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.localTTL {
+		return c.localTTL
+	}
+	return ttl
+}
+
+// invalidate announces keys as one message: the sender ID, then one key per
+// line.
+func (c *TieredCache) invalidate(ctx context.Context, keys ...string) error {
+	payload := c.id + "\n" + strings.Join(keys, "\n")
+	if err := c.remote.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		return fmt.Errorf("cache invalidation failed: %w", err)
+	}
+	return nil
+}
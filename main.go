@@ -7,12 +7,22 @@ import (
 	"syscall"
 	"time"
 
+	"search-engine/app/events"
 	"search-engine/app/health"
+	"search-engine/app/ingestion"
+	"search-engine/app/rescore"
 	"search-engine/app/search"
+	"search-engine/app/suggest"
+	"search-engine/app/warmup"
+	"search-engine/domain/dedup"
+	"search-engine/domain/fuzzy"
+	"search-engine/domain/highlight"
+	"search-engine/domain/scoring"
 	"search-engine/infra/httpclient"
 	"search-engine/infra/postgres"
 	"search-engine/infra/provider"
 	"search-engine/infra/redis"
+	"search-engine/pkg/codec"
 	"search-engine/pkg/config"
 	"search-engine/pkg/log"
 	"search-engine/pkg/middleware"
@@ -53,23 +63,210 @@ func main() {
 	defer db.Close()
 	logger.Info("database connected")
 
-	redisCache, err := redis.NewRedisCache(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB)
-	if err != nil {
-		logger.Fatal("failed to connect to Redis", zap.Error(err))
+	var redisCache *redis.RedisCache
+	if cfg.Redis.Enabled {
+		cacheCodec, err := codec.New(codec.Config{
+			Format:      cfg.Redis.Codec,
+			Compression: cfg.Redis.Compression,
+			Threshold:   cfg.Redis.CompressionThreshold,
+		})
+		if err != nil {
+			logger.Fatal("invalid redis cache codec", zap.Error(err))
+		}
+
+		redisCache, err = redis.NewRedisCache(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB, cacheCodec)
+		if err != nil {
+			logger.Fatal("failed to connect to Redis", zap.Error(err))
+		}
+		defer redisCache.Close()
+		logger.Info("redis connected")
+	} else {
+		logger.Warn("redis disabled, search results are cached in process only")
+	}
+
+	// Without Redis the in-process cache is the only one; with it, the local
+	// cache sits in front of Redis unless it is switched off.
+	var searchCache search.Cache = search.NewMemoryCache(cfg.Search.LocalCache.Size)
+	if redisCache != nil {
+		if cfg.Search.LocalCache.Enabled {
+			tieredCache := redis.NewTieredCache(redisCache, searchCache, cfg.Search.LocalCache.TTL)
+			defer tieredCache.Close()
+			searchCache = tieredCache
+		} else {
+			searchCache = redisCache
+		}
 	}
-	defer redisCache.Close()
-	logger.Info("redis connected")
 
 	providerManager := provider.NewManager(cfg.Provider.Timeout)
 
+	var suggestIndex suggest.Index = suggest.NewMemoryIndex()
+	if cfg.Suggest.Backend == "redis" {
+		if redisCache != nil {
+			suggestIndex = redis.NewSuggestIndex(redisCache, cfg.Suggest.PrefixLength, cfg.Suggest.MaxCandidates)
+		} else {
+			logger.Warn("redis disabled, using the in-memory suggest index")
+		}
+	}
+	suggestService := suggest.NewService(suggestIndex, postgres.NewSuggestRepository(db), suggest.Config{
+		DefaultLimit: cfg.Suggest.DefaultLimit,
+		MaxLimit:     cfg.Suggest.MaxLimit,
+		BatchSize:    cfg.Suggest.BatchSize,
+	}, logger)
+
+	// Every upsert, from ingestion or live searches, feeds the "did you mean"
+	// dictionary, the autocomplete index and the duplicate clusters.
+	dictionary := fuzzy.NewDictionary(cfg.Search.Fuzzy.DictionarySize)
+	clusterStore := postgres.NewClusterRepository(db)
+	deduper := dedup.New(cfg.Search.Dedup.Window, cfg.Search.Dedup.CanonicalURL)
+	searchRepo := search.NewIndexingRepository(postgres.NewRepository(db),
+		search.DictionaryIndexer(dictionary),
+		suggestService,
+		search.ClusterIndexer(clusterStore, deduper, logger),
+	)
+
+	strategies, err := buildScoringStrategies(cfg.Scoring)
+	if err != nil {
+		logger.Fatal("failed to configure scoring strategies", zap.Error(err))
+	}
+	logger.Info("scoring strategies configured",
+		zap.Strings("strategies", strategies.Names()),
+		zap.String("default", strategies.Default().Name()),
+	)
+
+	eventService := events.NewService(postgres.NewEventRepository(db), events.Config{
+		CTRWeight:   cfg.Events.CTRWeight,
+		Window:      cfg.Events.Window,
+		PriorCTR:    cfg.Events.PriorCTR,
+		PriorWeight: cfg.Events.PriorWeight,
+	}, logger)
+
+	var engagement search.EngagementSignal
+	if cfg.Events.CTRBoost {
+		engagement = eventService
+	}
+
+	// Searches are only counted when something warms them.
+	var queryLog search.QueryLog
+	if cfg.Warmup.Enabled {
+		queryLog = search.NewMemoryQueryLog(cfg.Warmup.Window, cfg.Warmup.MaxSearches)
+		if redisCache != nil {
+			queryLog = redis.NewQueryLog(redisCache, cfg.Warmup.Window, cfg.Warmup.MaxSearches)
+		}
+	}
+
+	searchService := search.NewService(searchRepo, providerManager, searchCache, logger, search.Config{
+		Mode:         search.Mode(cfg.Search.Mode),
+		CacheTTL:     cfg.Search.CacheTTL,
+		MinResults:   cfg.Search.MinResults,
+		MaxStaleness: cfg.Search.MaxStaleness,
+		Strategies:   strategies,
+		Engagement:   engagement,
+		Fuzzy: search.FuzzyConfig{
+			Enabled:           cfg.Search.Fuzzy.Enabled,
+			MinResults:        cfg.Search.Fuzzy.MinResults,
+			MaxResults:        cfg.Search.Fuzzy.MaxResults,
+			DictionarySize:    cfg.Search.Fuzzy.DictionarySize,
+			DictionaryRefresh: cfg.Search.Fuzzy.DictionaryRefresh,
+		},
+		Dictionary:  dictionary,
+		Highlighter: highlight.NewHighlighter(cfg.Search.Highlight.PreTag, cfg.Search.Highlight.PostTag),
+		Cursor: search.CursorConfig{
+			Secret:       cfg.Search.Cursor.Secret,
+			SnapshotTTL:  cfg.Search.Cursor.SnapshotTTL,
+			SnapshotSize: cfg.Search.Cursor.SnapshotSize,
+		},
+		Dedup: search.DedupConfig{
+			Enabled:      cfg.Search.Dedup.Enabled,
+			Window:       cfg.Search.Dedup.Window,
+			CanonicalURL: cfg.Search.Dedup.CanonicalURL,
+			Clusters:     clusterStore,
+		},
+		Cache: search.CacheConfig{
+			StaleTTL: cfg.Search.StaleTTL,
+			LockTTL:  cfg.Search.LockTTL,
+		},
+		QueryLog: queryLog,
+	})
+	if cfg.Search.Cursor.Secret == "" {
+		logger.Warn("search cursor secret not set, cursors will not survive restarts")
+	}
+	if err := searchService.LoadDictionary(context.Background()); err != nil {
+		logger.Warn("failed to load suggestion dictionary", zap.Error(err))
+	} else {
+		logger.Info("suggestion dictionary loaded", zap.Int("terms", dictionary.Len()))
+	}
+
+	scheduler := ingestion.NewScheduler(searchRepo, ingestion.Config{
+		Interval:    cfg.Ingestion.Interval,
+		Jitter:      cfg.Ingestion.Jitter,
+		BatchSize:   cfg.Ingestion.BatchSize,
+		Timeout:     cfg.Ingestion.Timeout,
+		Scorer:      strategies.Default(),
+		Invalidator: searchService,
+	}, logger)
+
+	rescoreJob := rescore.NewJob(postgres.NewScoreRepository(db), rescore.Config{
+		Interval:    cfg.Rescore.Interval,
+		BatchSize:   cfg.Rescore.BatchSize,
+		MinDelta:    cfg.Rescore.MinDelta,
+		Scorer:      strategies.Default(),
+		Invalidator: searchService,
+	}, logger)
+
+	warmupJob := warmup.NewJob(searchService, warmup.Config{
+		Interval:    cfg.Warmup.Interval,
+		TopN:        cfg.Warmup.TopN,
+		Concurrency: cfg.Warmup.Concurrency,
+		Breakers:    providerManager,
+	}, logger)
+	if cfg.Warmup.Enabled && cfg.Warmup.Interval >= cfg.Search.CacheTTL {
+		logger.Warn("warmup interval is not shorter than the cache TTL, popular searches may expire between runs")
+	}
+
 	httpClient := httpclient.NewDefaultHTTPClient(
 		httpclient.WithTimeout(cfg.Provider.Timeout),
 	)
 
 	for _, p := range cfg.Providers {
-		providerFormat := "http_" + p.Format
+		providerFormat := p.Format
+		if _, err := provider.GetFactory("http_" + p.Format); err == nil {
+			providerFormat = "http_" + p.Format
+		}
+
+		opts := []provider.Option{
+			provider.WithCapabilities(provider.Capabilities{
+				QueryParam:           p.Capabilities.QueryParam,
+				PageParam:            p.Capabilities.PageParam,
+				PerPageParam:         p.Capabilities.PerPageParam,
+				MaxPageSize:          p.Capabilities.MaxPageSize,
+				LocalFiltering:       p.Capabilities.LocalFiltering,
+				ServerSidePagination: p.Capabilities.ServerSidePagination,
+			}),
+			provider.WithFuzzyMatching(cfg.Search.Fuzzy.Enabled),
+		}
+
+		if p.Mapping != nil {
+			opts = append(opts, provider.WithMapping(provider.MappingSpec{
+				Source: p.Mapping.Source,
+				Items:  p.Mapping.Items,
+				Total:  p.Mapping.Total,
+				Fields: provider.FieldMapping{
+					ID:          p.Mapping.Fields.ID,
+					Title:       p.Mapping.Fields.Title,
+					Type:        p.Mapping.Fields.Type,
+					Views:       p.Mapping.Fields.Views,
+					Likes:       p.Mapping.Fields.Likes,
+					Reactions:   p.Mapping.Fields.Reactions,
+					ReadingTime: p.Mapping.Fields.ReadingTime,
+					PublishedAt: p.Mapping.Fields.PublishedAt,
+					Tags:        p.Mapping.Fields.Tags,
+				},
+				DateLayouts: p.Mapping.DateLayouts,
+				TypeAliases: p.Mapping.TypeAliases,
+			}))
+		}
 
-		contentProvider, err := provider.CreateProvider(providerFormat, p.Name, p.URL, cfg.Provider.Timeout, httpClient, logger)
+		contentProvider, err := provider.CreateProvider(providerFormat, p.Name, p.URL, cfg.Provider.Timeout, httpClient, logger, opts...)
 		if err != nil {
 			logger.Warn("failed to create provider",
 				zap.String("name", p.Name),
@@ -86,21 +283,27 @@ func main() {
 		)
 
 		providerManager.Register(wrappedProvider)
+		scheduler.AddProvider(wrappedProvider, p.IngestInterval)
 		logger.Info("registered provider",
 			zap.String("name", p.Name),
 			zap.String("format", providerFormat),
 			zap.String("url", p.URL),
+			zap.Bool("local_filtering", p.Capabilities.LocalFiltering),
+			zap.Bool("server_side_pagination", p.Capabilities.ServerSidePagination),
 		)
 	}
 
 	logger.Info("providers registered", zap.Int("count", len(cfg.Providers)))
 
-	searchRepo := postgres.NewRepository(db)
-
-	searchService := search.NewService(searchRepo, providerManager, redisCache, logger)
+	logger.Info("search mode configured", zap.String("mode", cfg.Search.Mode))
 
 	healthHandler := health.NewHandler(db, redisCache, providerManager)
 	searchHandler := search.NewHandler(searchService, logger)
+	ingestionHandler := ingestion.NewHandler(scheduler, logger)
+	rescoreHandler := rescore.NewHandler(rescoreJob, logger)
+	warmupHandler := warmup.NewHandler(warmupJob, logger)
+	eventHandler := events.NewHandler(eventService, logger)
+	suggestHandler := suggest.NewHandler(suggestService, logger)
 
 	app := fiber.New(fiber.Config{
 		AppName:      cfg.App.Name,
@@ -135,6 +338,27 @@ func main() {
 
 	healthHandler.RegisterRoutes(app)
 	searchHandler.RegisterRoutes(app)
+	ingestionHandler.RegisterRoutes(app)
+	rescoreHandler.RegisterRoutes(app)
+	warmupHandler.RegisterRoutes(app)
+	eventHandler.RegisterRoutes(app)
+	suggestHandler.RegisterRoutes(app)
+
+	if cfg.Ingestion.Enabled {
+		scheduler.Start(context.Background())
+	}
+	if cfg.Rescore.Enabled {
+		rescoreJob.Start(context.Background())
+	}
+	if cfg.Warmup.Enabled {
+		warmupJob.Start(context.Background())
+	}
+
+	go func() {
+		if _, err := suggestService.Build(context.Background()); err != nil {
+			logger.Warn("failed to build suggest index", zap.Error(err))
+		}
+	}()
 
 	go func() {
 		if err := app.Listen(":" + cfg.Server.Port); err != nil {
@@ -148,6 +372,16 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	if cfg.Ingestion.Enabled {
+		scheduler.Stop()
+	}
+	if cfg.Rescore.Enabled {
+		rescoreJob.Stop()
+	}
+	if cfg.Warmup.Enabled {
+		warmupJob.Stop()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -157,3 +391,35 @@ func main() {
 
 	logger.Info("server stopped")
 }
+
+func buildScoringStrategies(cfg config.ScoringConfig) (*scoring.Strategies, error) {
+	strategies := make([]scoring.Strategy, 0, len(cfg.Strategies))
+	for _, s := range cfg.Strategies {
+		weights := scoring.Weights{
+			VideoMultiplier:       s.Weights.VideoMultiplier,
+			TextMultiplier:        s.Weights.TextMultiplier,
+			ViewsDivisor:          s.Weights.ViewsDivisor,
+			LikesDivisor:          s.Weights.LikesDivisor,
+			ReadingTimeWeight:     s.Weights.ReadingTimeWeight,
+			ReactionsDivisor:      s.Weights.ReactionsDivisor,
+			VideoEngagementWeight: s.Weights.VideoEngagementWeight,
+			TextEngagementWeight:  s.Weights.TextEngagementWeight,
+			FreshnessHalfLife:     s.Weights.FreshnessHalfLife,
+			FreshnessMax:          s.Weights.FreshnessMax,
+		}
+		for _, bucket := range s.Freshness {
+			weights.Freshness = append(weights.Freshness, scoring.FreshnessBucket{
+				MaxAge: bucket.MaxAge,
+				Score:  bucket.Score,
+			})
+		}
+
+		strategy, err := scoring.CreateStrategy(s.Type, s.Name, weights)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+	}
+
+	return scoring.NewStrategies(cfg.Default, strategies...)
+}
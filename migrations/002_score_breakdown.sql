@@ -0,0 +1,6 @@
+-- Persisted score components so rankings can be explained without rescoring
+ALTER TABLE contents
+    ADD COLUMN IF NOT EXISTS base_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS type_multiplier DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS freshness_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS engagement_score DOUBLE PRECISION NOT NULL DEFAULT 0;
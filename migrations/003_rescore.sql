@@ -0,0 +1,17 @@
+-- Score recomputation only rewrites score columns; it must not make rows look
+-- freshly fetched, so updated_at is bumped only when content data changes.
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.updated_at IS DISTINCT FROM OLD.updated_at
+        OR (NEW.title, NEW.type, NEW.published_at, NEW.raw_data, NEW.views, NEW.likes,
+            NEW.reactions, NEW.reading_time, NEW.tags)
+        IS DISTINCT FROM
+           (OLD.title, OLD.type, OLD.published_at, OLD.raw_data, OLD.views, OLD.likes,
+            OLD.reactions, OLD.reading_time, OLD.tags)
+    THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
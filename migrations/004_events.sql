@@ -0,0 +1,27 @@
+-- Raw impression and click events reported by API clients
+CREATE TABLE IF NOT EXISTS search_events (
+    id BIGSERIAL PRIMARY KEY,
+    request_id VARCHAR(100) NOT NULL,
+    content_id UUID NOT NULL,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('impression', 'click')),
+    position INTEGER NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_events_request ON search_events(request_id);
+CREATE INDEX IF NOT EXISTS idx_search_events_content_created ON search_events(content_id, created_at DESC);
+
+-- Daily per-content rollup; examinations is the sum of position propensities
+-- of the impressions and is the denominator of the debiased click-through rate
+CREATE TABLE IF NOT EXISTS content_event_daily (
+    content_id UUID NOT NULL,
+    day DATE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    examinations DOUBLE PRECISION NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (content_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_event_daily_day ON content_event_daily(day);
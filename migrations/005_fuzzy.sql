@@ -0,0 +1,5 @@
+-- Trigram similarity for typo-tolerant title matching
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_contents_title_trgm ON contents
+    USING GIN (lower(title) gin_trgm_ops);
@@ -0,0 +1,3 @@
+-- Keyset pagination walks (score, published_at, id); the id tiebreaker makes
+-- the order total so cursors never skip or repeat rows.
+CREATE INDEX IF NOT EXISTS idx_contents_keyset ON contents(score, published_at, id);
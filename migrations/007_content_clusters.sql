@@ -0,0 +1,14 @@
+-- Duplicate clusters: the same content published by several providers, or
+-- by one provider under several external IDs. cluster_id is the ID of the
+-- content that started the cluster.
+CREATE TABLE IF NOT EXISTS content_clusters (
+    content_id UUID PRIMARY KEY REFERENCES contents(id) ON DELETE CASCADE,
+    cluster_id UUID NOT NULL,
+    title_key TEXT NOT NULL,
+    canonical_url TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_clusters_cluster ON content_clusters(cluster_id);
+CREATE INDEX IF NOT EXISTS idx_content_clusters_title ON content_clusters(title_key, published_at);
+CREATE INDEX IF NOT EXISTS idx_content_clusters_url ON content_clusters(canonical_url) WHERE canonical_url <> '';
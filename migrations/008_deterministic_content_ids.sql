@@ -0,0 +1,49 @@
+-- Content IDs are derived from the provider item, as
+-- uuid_generate_v5(namespace, provider || '/' || external_id), so live results
+-- and stored rows share them. Rows stored before keep a random ID; rewrite
+-- those and everything that refers to them.
+BEGIN;
+
+CREATE TEMP TABLE content_id_changes ON COMMIT DROP AS
+SELECT id AS old_id,
+       uuid_generate_v5('8d95b0a6-e7e4-4220-bd19-0ea73ab821dd', provider || '/' || external_id) AS new_id
+FROM contents;
+
+DELETE FROM content_id_changes WHERE old_id = new_id;
+
+-- Cluster membership follows its content when the ID changes
+ALTER TABLE content_clusters DROP CONSTRAINT IF EXISTS content_clusters_content_id_fkey;
+ALTER TABLE content_clusters ADD CONSTRAINT content_clusters_content_id_fkey
+    FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE ON UPDATE CASCADE;
+
+UPDATE contents c
+SET id = m.new_id
+FROM content_id_changes m
+WHERE c.id = m.old_id;
+
+UPDATE content_clusters cc
+SET cluster_id = m.new_id
+FROM content_id_changes m
+WHERE cc.cluster_id = m.old_id;
+
+UPDATE search_events e
+SET content_id = m.new_id
+FROM content_id_changes m
+WHERE e.content_id = m.old_id;
+
+-- Live results may already have recorded events under the new ID, so daily
+-- rollups are merged rather than renamed
+INSERT INTO content_event_daily (content_id, day, impressions, clicks, examinations)
+SELECT m.new_id, d.day, d.impressions, d.clicks, d.examinations
+FROM content_event_daily d
+JOIN content_id_changes m ON m.old_id = d.content_id
+ON CONFLICT (content_id, day) DO UPDATE SET
+    impressions = content_event_daily.impressions + EXCLUDED.impressions,
+    clicks = content_event_daily.clicks + EXCLUDED.clicks,
+    examinations = content_event_daily.examinations + EXCLUDED.examinations;
+
+DELETE FROM content_event_daily d
+USING content_id_changes m
+WHERE d.content_id = m.old_id;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Tags are stored in lower case, trimmed and without repeats, so tag filters
+-- compare them exactly on every backend.
+UPDATE contents c
+SET tags = normalized.tags
+FROM (
+    SELECT id,
+           COALESCE(ARRAY(
+               SELECT tag
+               FROM (
+                   SELECT lower(btrim(t)) AS tag, MIN(ord) AS first
+                   FROM unnest(contents.tags) WITH ORDINALITY AS u(t, ord)
+                   WHERE btrim(t) <> ''
+                   GROUP BY lower(btrim(t))
+               ) deduped
+               ORDER BY first
+           ), '{}') AS tags
+    FROM contents
+    WHERE tags IS NOT NULL
+) normalized
+WHERE c.id = normalized.id
+  AND c.tags IS DISTINCT FROM normalized.tags;
@@ -23,6 +23,7 @@ const (
 	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
 	CodeUnauthorized      = "UNAUTHORIZED"
 	CodeBadRequest        = "BAD_REQUEST"
+	CodeConflict          = "CONFLICT"
 )
 
 var (
@@ -85,6 +86,14 @@ func NewNotFoundError(resource string) *APIError {
 	}
 }
 
+func NewConflictError(message string) *APIError {
+	return &APIError{
+		Code:       CodeConflict,
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
 func NewProviderError(providerName string, err error) *APIError {
 	return &APIError{
 		Code:       CodeProviderError,
@@ -0,0 +1,217 @@
+// Package codec encodes cached values into self-describing entries. Each
+// entry starts with a header naming the format and compression it was written
+// with, so entries stay readable after either setting changes.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type Format byte
+
+const (
+	FormatJSON    Format = 1
+	FormatMsgpack Format = 2
+	FormatGob     Format = 3
+)
+
+type Compression byte
+
+const (
+	CompressionNone   Compression = 0
+	CompressionZstd   Compression = 1
+	CompressionSnappy Compression = 2
+)
+
+// version is the first byte of every entry, followed by the format and the
+// compression. Versions stay below 0x20 so they never collide with the first
+// byte of a JSON document, which is how entries written before the header
+// existed are recognised.
+const (
+	version    byte = 1
+	headerSize      = 3
+)
+
+const defaultThreshold = 1024
+
+var ErrUnsupportedVersion = errors.New("unsupported cache entry version")
+
+type Config struct {
+	Format      string
+	Compression string
+	// Threshold is the encoded size in bytes from which entries are
+	// compressed. Smaller ones are stored as they are.
+	Threshold int
+}
+
+type Codec struct {
+	format      Format
+	compression Compression
+	threshold   int
+}
+
+func New(config Config) (*Codec, error) {
+	c := &Codec{threshold: config.Threshold}
+	if c.threshold <= 0 {
+		c.threshold = defaultThreshold
+	}
+
+	switch config.Format {
+	case "", "json":
+		c.format = FormatJSON
+	case "msgpack":
+		c.format = FormatMsgpack
+	case "gob":
+		c.format = FormatGob
+	default:
+		return nil, fmt.Errorf("unknown cache codec %q", config.Format)
+	}
+
+	switch config.Compression {
+	case "", "none":
+		c.compression = CompressionNone
+	case "zstd":
+		c.compression = CompressionZstd
+	case "snappy":
+		c.compression = CompressionSnappy
+	default:
+		return nil, fmt.Errorf("unknown cache compression %q", config.Compression)
+	}
+
+	if c.compression == CompressionZstd {
+		if _, err := zstdEncoder(); err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *Codec) Marshal(v any) ([]byte, error) {
+	payload, err := encode(c.format, v)
+	if err != nil {
+		return nil, err
+	}
+
+	compression := c.compression
+	if len(payload) < c.threshold {
+		compression = CompressionNone
+	}
+
+	data := make([]byte, headerSize, headerSize+len(payload))
+	data[0], data[1], data[2] = version, byte(c.format), byte(compression)
+
+	switch compression {
+	case CompressionZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		return encoder.EncodeAll(payload, data), nil
+	case CompressionSnappy:
+		return append(data, snappy.Encode(nil, payload)...), nil
+	default:
+		return append(data, payload...), nil
+	}
+}
+
+// Unmarshal decodes an entry by its own header, whatever c is configured to
+// write.
+func (c *Codec) Unmarshal(data []byte, v any) error {
+	if len(data) == 0 {
+		return errors.New("empty cache entry")
+	}
+	if data[0] >= 0x20 {
+		return json.Unmarshal(data, v)
+	}
+	if data[0] != version {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, data[0])
+	}
+	if len(data) < headerSize {
+		return errors.New("truncated cache entry header")
+	}
+
+	payload, err := decompress(Compression(data[2]), data[headerSize:])
+	if err != nil {
+		return err
+	}
+	return decode(Format(data[1]), payload, v)
+}
+
+func encode(format Format, v any) ([]byte, error) {
+	switch format {
+	case FormatMsgpack:
+		var buf bytes.Buffer
+		enc := msgpack.GetEncoder()
+		defer msgpack.PutEncoder(enc)
+		enc.Reset(&buf)
+		enc.SetCustomStructTag("json")
+		enc.UseCompactInts(true)
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatGob:
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(gobValue(v)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return json.Marshal(v)
+	}
+}
+
+func decode(format Format, payload []byte, v any) error {
+	switch format {
+	case FormatJSON:
+		return json.Unmarshal(payload, v)
+	case FormatMsgpack:
+		dec := msgpack.GetDecoder()
+		defer msgpack.PutDecoder(dec)
+		dec.Reset(bytes.NewReader(payload))
+		dec.SetCustomStructTag("json")
+		return dec.Decode(v)
+	case FormatGob:
+		return gob.NewDecoder(bytes.NewReader(payload)).Decode(v)
+	default:
+		return fmt.Errorf("unknown cache entry format %d", format)
+	}
+}
+
+func decompress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		return decoder.DecodeAll(data, nil)
+	case CompressionSnappy:
+		return snappy.Decode(nil, data)
+	default:
+		return nil, fmt.Errorf("unknown cache entry compression %d", compression)
+	}
+}
+
+// The zstd encoder and decoder are safe for concurrent EncodeAll and
+// DecodeAll calls and costly to create, so they are created on first use and
+// shared.
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+)
@@ -0,0 +1,265 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// item and result mirror the shape of a cached search result.
+type item struct {
+	ID          string    `json:"id"`
+	Provider    string    `json:"provider"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	PublishedAt time.Time `json:"published_at"`
+	RawData     []byte    `json:"-"`
+	Views       int       `json:"views,omitempty"`
+	Likes       int       `json:"likes,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Score       float64   `json:"score"`
+}
+
+type result struct {
+	Items      []item    `json:"items"`
+	Total      int64     `json:"total"`
+	Page       int       `json:"page"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+func mustNew(tb testing.TB, config Config) *Codec {
+	tb.Helper()
+
+	c, err := New(config)
+	if err != nil {
+		tb.Fatalf("New(%+v) error = %v", config, err)
+	}
+	return c
+}
+
+func newResult(n int) result {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := result{Total: int64(n * 10), Page: 1, FreshUntil: published.Add(time.Hour)}
+	for i := range n {
+		r.Items = append(r.Items, item{
+			ID:          fmt.Sprintf("7d7a8c1e-5f0b-4a43-9d3e-%012d", i),
+			Provider:    "json_provider",
+			Title:       fmt.Sprintf("Introduction to Go concurrency, part %d", i),
+			Type:        "video",
+			PublishedAt: published.Add(-time.Duration(i) * time.Hour),
+			Views:       1000 * i,
+			Likes:       37 * i,
+			Tags:        []string{"go", "concurrency", "programming"},
+			Score:       float64(i) * 1.25,
+		})
+	}
+	return r
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "msgpack", "gob"} {
+		for _, compression := range []string{"none", "zstd", "snappy"} {
+			for _, n := range []int{1, 100} {
+				t.Run(fmt.Sprintf("%s/%s/%d", format, compression, n), func(t *testing.T) {
+					c, err := New(Config{Format: format, Compression: compression, Threshold: 1024})
+					if err != nil {
+						t.Fatalf("New() error = %v", err)
+					}
+
+					want := newResult(n)
+					data, err := c.Marshal(want)
+					if err != nil {
+						t.Fatalf("Marshal() error = %v", err)
+					}
+
+					wantCompression := c.compression
+					if n == 1 {
+						wantCompression = CompressionNone
+					}
+					if data[0] != version || Format(data[1]) != c.format || Compression(data[2]) != wantCompression {
+						t.Errorf("header = %v, want [%d %d %d]", data[:headerSize], version, c.format, wantCompression)
+					}
+
+					var got result
+					if err := c.Unmarshal(data, &got); err != nil {
+						t.Fatalf("Unmarshal() error = %v", err)
+					}
+					assertEqual(t, got, want)
+				})
+			}
+		}
+	}
+}
+
+func TestCodec_ReadsOtherConfigurations(t *testing.T) {
+	writer := mustNew(t, Config{Format: "gob", Compression: "zstd", Threshold: 1})
+	reader := mustNew(t, Config{Format: "msgpack"})
+
+	want := newResult(3)
+	data, err := writer.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got result
+	if err := reader.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	assertEqual(t, got, want)
+}
+
+func TestCodec_ReadsLegacyJSON(t *testing.T) {
+	c := mustNew(t, Config{Format: "msgpack", Compression: "zstd"})
+
+	want := newResult(3)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got result
+	if err := c.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	assertEqual(t, got, want)
+}
+
+func TestCodec_UnsupportedVersion(t *testing.T) {
+	c := mustNew(t, Config{})
+
+	var got result
+	err := c.Unmarshal([]byte{version + 1, byte(FormatJSON), byte(CompressionNone), '{', '}'}, &got)
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Unmarshal() error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestCodec_SkipsFieldsHiddenFromJSON(t *testing.T) {
+	for _, format := range []string{"json", "msgpack", "gob"} {
+		t.Run(format, func(t *testing.T) {
+			c := mustNew(t, Config{Format: format})
+
+			want := newResult(2)
+			want.Items[0].RawData = []byte(`{"id":"a"}`)
+			data, err := c.Marshal(&want)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if bytes.Contains(data, []byte("RawData")) {
+				t.Errorf("entry mentions RawData: %q", data)
+			}
+
+			var got result
+			if err := c.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			want.Items[0].RawData = nil
+			assertEqual(t, got, want)
+		})
+	}
+}
+
+func TestNew_UnknownSettings(t *testing.T) {
+	tests := []Config{
+		{Format: "xml"},
+		{Compression: "gzip"},
+	}
+	for _, config := range tests {
+		if _, err := New(config); err == nil {
+			t.Errorf("New(%+v) error = nil, want error", config)
+		}
+	}
+}
+
+// assertEqual compares results with times compared as instants, since
+// msgpack decodes them in the local time zone.
+func assertEqual(t *testing.T, got, want result) {
+	t.Helper()
+
+	if !got.FreshUntil.Equal(want.FreshUntil) {
+		t.Errorf("FreshUntil = %v, want %v", got.FreshUntil, want.FreshUntil)
+	}
+	got.FreshUntil = want.FreshUntil
+	if len(got.Items) != len(want.Items) {
+		t.Fatalf("len(Items) = %d, want %d", len(got.Items), len(want.Items))
+	}
+	for i := range got.Items {
+		if !got.Items[i].PublishedAt.Equal(want.Items[i].PublishedAt) {
+			t.Errorf("Items[%d].PublishedAt = %v, want %v", i, got.Items[i].PublishedAt, want.Items[i].PublishedAt)
+		}
+		got.Items[i].PublishedAt = want.Items[i].PublishedAt
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+// The "json" benchmarks without compression match the encoding used before
+// codecs were configurable, apart from the three header bytes.
+var benchmarkConfigs = []Config{
+	{Format: "json"},
+	{Format: "json", Compression: "zstd"},
+	{Format: "json", Compression: "snappy"},
+	{Format: "msgpack"},
+	{Format: "msgpack", Compression: "zstd"},
+	{Format: "msgpack", Compression: "snappy"},
+	{Format: "gob"},
+	{Format: "gob", Compression: "zstd"},
+	{Format: "gob", Compression: "snappy"},
+}
+
+func benchmarkName(config Config) string {
+	if config.Compression == "" {
+		return config.Format
+	}
+	return config.Format + "+" + config.Compression
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	value := newResult(100)
+	for _, config := range benchmarkConfigs {
+		b.Run(benchmarkName(config), func(b *testing.B) {
+			c := mustNew(b, config)
+			data, err := c.Marshal(value)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				if _, err := c.Marshal(value); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/entry")
+		})
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	value := newResult(100)
+	for _, config := range benchmarkConfigs {
+		b.Run(benchmarkName(config), func(b *testing.B) {
+			c := mustNew(b, config)
+			data, err := c.Marshal(value)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				var got result
+				if err := c.Unmarshal(data, &got); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/entry")
+		})
+	}
+}
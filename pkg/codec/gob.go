@@ -0,0 +1,149 @@
+package codec
+
+import (
+	"encoding"
+	"encoding/gob"
+	"reflect"
+	"sync"
+)
+
+// gob writes every exported field, json:"-" ones included. gobValue copies v
+// into a type without those fields, so gob entries hold what the other formats
+// hold. Decoding needs no counterpart, since gob leaves fields missing from
+// the entry untouched.
+func gobValue(v any) any {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return v
+	}
+	projected := gobType(value.Type())
+	if projected == value.Type() {
+		return v
+	}
+
+	dst := reflect.New(projected).Elem()
+	project(dst, value)
+	return dst.Interface()
+}
+
+var (
+	gobTypesMu sync.Mutex
+	gobTypes   = make(map[reflect.Type]reflect.Type)
+)
+
+var (
+	gobEncoderType    = reflect.TypeFor[gob.GobEncoder]()
+	binaryMarshalType = reflect.TypeFor[encoding.BinaryMarshaler]()
+)
+
+// gobType returns t with the fields hidden from JSON removed at any depth, or
+// t itself when it has none.
+func gobType(t reflect.Type) reflect.Type {
+	gobTypesMu.Lock()
+	defer gobTypesMu.Unlock()
+	return cachedGobType(t)
+}
+
+func cachedGobType(t reflect.Type) reflect.Type {
+	if projected, ok := gobTypes[t]; ok {
+		return projected
+	}
+
+	// Recursive types are left as they are rather than projected forever.
+	gobTypes[t] = t
+	projected := projectType(t)
+	gobTypes[t] = projected
+	return projected
+}
+
+func projectType(t reflect.Type) reflect.Type {
+	for _, marshaler := range []reflect.Type{gobEncoderType, binaryMarshalType} {
+		if t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler) {
+			return t
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if elem := cachedGobType(t.Elem()); elem != t.Elem() {
+			return reflect.PointerTo(elem)
+		}
+	case reflect.Slice:
+		if elem := cachedGobType(t.Elem()); elem != t.Elem() {
+			return reflect.SliceOf(elem)
+		}
+	case reflect.Array:
+		if elem := cachedGobType(t.Elem()); elem != t.Elem() {
+			return reflect.ArrayOf(t.Len(), elem)
+		}
+	case reflect.Map:
+		if elem := cachedGobType(t.Elem()); elem != t.Elem() {
+			return reflect.MapOf(t.Key(), elem)
+		}
+	case reflect.Struct:
+		var fields []reflect.StructField
+		changed := false
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("json") == "-" {
+				changed = true
+				continue
+			}
+			if projected := cachedGobType(field.Type); projected != field.Type {
+				field.Type = projected
+				changed = true
+			}
+			// Embedded fields keep their name, which is all gob matches on.
+			field.Anonymous = false
+			fields = append(fields, field)
+		}
+		if changed {
+			return reflect.StructOf(fields)
+		}
+	}
+	return t
+}
+
+// project copies src into dst, whose type is gobType of the type of src.
+func project(dst, src reflect.Value) {
+	if dst.Type() == src.Type() {
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if !src.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+			project(dst.Elem(), src.Elem())
+		}
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+			for i := range src.Len() {
+				project(dst.Index(i), src.Index(i))
+			}
+		}
+	case reflect.Array:
+		for i := range src.Len() {
+			project(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+			iter := src.MapRange()
+			for iter.Next() {
+				elem := reflect.New(dst.Type().Elem()).Elem()
+				project(elem, iter.Value())
+				dst.SetMapIndex(iter.Key(), elem)
+			}
+		}
+	case reflect.Struct:
+		for i := range dst.NumField() {
+			project(dst.Field(i), src.FieldByName(dst.Type().Field(i).Name))
+		}
+	}
+}
@@ -15,14 +15,171 @@ type Config struct {
 	Database  DatabaseConfig   `yaml:"database"`
 	Redis     RedisConfig      `yaml:"redis"`
 	Provider  ProviderConfig   `yaml:"provider"`
+	Search    SearchConfig     `yaml:"search"`
+	Ingestion IngestionConfig  `yaml:"ingestion"`
+	Scoring   ScoringConfig    `yaml:"scoring"`
+	Rescore   RescoreConfig    `yaml:"rescore"`
+	Warmup    WarmupConfig     `yaml:"warmup"`
+	Events    EventsConfig     `yaml:"events"`
+	Suggest   SuggestConfig    `yaml:"suggest"`
 	Providers []ProviderSource `yaml:"providers"`
 }
 
+type SearchConfig struct {
+	Mode           string           `yaml:"mode"`
+	CacheTTL       time.Duration    `yaml:"cache_ttl"`
+	StaleTTL       time.Duration    `yaml:"stale_ttl"`
+	LockTTL        time.Duration    `yaml:"lock_ttl"`
+	PreferDatabase bool             `yaml:"prefer_database"`
+	MinResults     int              `yaml:"min_results"`
+	MaxStaleness   time.Duration    `yaml:"max_staleness"`
+	Fuzzy          FuzzyConfig      `yaml:"fuzzy"`
+	Highlight      HighlightConfig  `yaml:"highlight"`
+	Cursor         CursorConfig     `yaml:"cursor"`
+	Dedup          DedupConfig      `yaml:"dedup"`
+	LocalCache     LocalCacheConfig `yaml:"local_cache"`
+}
+
+type LocalCacheConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Size    int           `yaml:"size"`
+	TTL     time.Duration `yaml:"ttl"`
+}
+
+type DedupConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Window       time.Duration `yaml:"window"`
+	CanonicalURL bool          `yaml:"canonical_url"`
+}
+
+type CursorConfig struct {
+	Secret       string        `yaml:"secret"`
+	SnapshotTTL  time.Duration `yaml:"snapshot_ttl"`
+	SnapshotSize int           `yaml:"snapshot_size"`
+}
+
+type HighlightConfig struct {
+	PreTag  string `yaml:"pre_tag"`
+	PostTag string `yaml:"post_tag"`
+}
+
+type FuzzyConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	MinResults        int           `yaml:"min_results"`
+	MaxResults        int           `yaml:"max_results"`
+	DictionarySize    int           `yaml:"dictionary_size"`
+	DictionaryRefresh time.Duration `yaml:"dictionary_refresh"`
+}
+
+type IngestionConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	Jitter    time.Duration `yaml:"jitter"`
+	BatchSize int           `yaml:"batch_size"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+type EventsConfig struct {
+	CTRBoost    bool          `yaml:"ctr_boost"`
+	CTRWeight   float64       `yaml:"ctr_weight"`
+	Window      time.Duration `yaml:"window"`
+	PriorCTR    float64       `yaml:"prior_ctr"`
+	PriorWeight float64       `yaml:"prior_weight"`
+}
+
+type SuggestConfig struct {
+	Backend       string `yaml:"backend"`
+	DefaultLimit  int    `yaml:"default_limit"`
+	MaxLimit      int    `yaml:"max_limit"`
+	BatchSize     int    `yaml:"batch_size"`
+	MaxCandidates int    `yaml:"max_candidates"`
+	PrefixLength  int    `yaml:"prefix_length"`
+}
+
+type RescoreConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+	MinDelta  float64       `yaml:"min_delta"`
+}
+
+type WarmupConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Interval    time.Duration `yaml:"interval"`
+	TopN        int           `yaml:"top_n"`
+	Concurrency int           `yaml:"concurrency"`
+	Window      time.Duration `yaml:"window"`
+	MaxSearches int           `yaml:"max_searches"`
+}
+
+type ScoringConfig struct {
+	Default    string            `yaml:"default"`
+	Strategies []ScoringStrategy `yaml:"strategies"`
+}
+
+type ScoringStrategy struct {
+	Name      string            `yaml:"name"`
+	Type      string            `yaml:"type"`
+	Weights   ScoringWeights    `yaml:"weights"`
+	Freshness []FreshnessBucket `yaml:"freshness"`
+}
+
+type ScoringWeights struct {
+	VideoMultiplier       *float64      `yaml:"video_multiplier"`
+	TextMultiplier        *float64      `yaml:"text_multiplier"`
+	ViewsDivisor          *float64      `yaml:"views_divisor"`
+	LikesDivisor          *float64      `yaml:"likes_divisor"`
+	ReadingTimeWeight     *float64      `yaml:"reading_time_weight"`
+	ReactionsDivisor      *float64      `yaml:"reactions_divisor"`
+	VideoEngagementWeight *float64      `yaml:"video_engagement_weight"`
+	TextEngagementWeight  *float64      `yaml:"text_engagement_weight"`
+	FreshnessHalfLife     time.Duration `yaml:"freshness_half_life"`
+	FreshnessMax          *float64      `yaml:"freshness_max"`
+}
+
+type FreshnessBucket struct {
+	MaxAge time.Duration `yaml:"max_age"`
+	Score  float64       `yaml:"score"`
+}
+
 type ProviderSource struct {
-	Name      string `yaml:"name"`
-	URL       string `yaml:"url"`
-	Format    string `yaml:"format"`
-	RateLimit int    `yaml:"rate_limit"`
+	Name           string               `yaml:"name"`
+	URL            string               `yaml:"url"`
+	Format         string               `yaml:"format"`
+	RateLimit      int                  `yaml:"rate_limit"`
+	IngestInterval time.Duration        `yaml:"ingest_interval"`
+	Capabilities   ProviderCapabilities `yaml:"capabilities"`
+	Mapping        *ProviderMapping     `yaml:"mapping"`
+}
+
+type ProviderMapping struct {
+	Source      string            `yaml:"source"`
+	Items       string            `yaml:"items"`
+	Total       string            `yaml:"total"`
+	Fields      MappingFields     `yaml:"fields"`
+	DateLayouts []string          `yaml:"date_layouts"`
+	TypeAliases map[string]string `yaml:"type_aliases"`
+}
+
+type MappingFields struct {
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Type        string `yaml:"type"`
+	Views       string `yaml:"views"`
+	Likes       string `yaml:"likes"`
+	Reactions   string `yaml:"reactions"`
+	ReadingTime string `yaml:"reading_time"`
+	PublishedAt string `yaml:"published_at"`
+	Tags        string `yaml:"tags"`
+}
+
+type ProviderCapabilities struct {
+	QueryParam           string `yaml:"query_param"`
+	PageParam            string `yaml:"page_param"`
+	PerPageParam         string `yaml:"per_page_param"`
+	MaxPageSize          int    `yaml:"max_page_size"`
+	LocalFiltering       bool   `yaml:"local_filtering"`
+	ServerSidePagination bool   `yaml:"server_side_pagination"`
 }
 
 type AppConfig struct {
@@ -44,10 +201,14 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
-	Password string `yaml:"password"`
-	DB       int    `yaml:"db"`
+	Enabled              bool   `yaml:"enabled"`
+	Host                 string `yaml:"host"`
+	Port                 string `yaml:"port"`
+	Password             string `yaml:"password"`
+	DB                   int    `yaml:"db"`
+	Codec                string `yaml:"codec"`
+	Compression          string `yaml:"compression"`
+	CompressionThreshold int    `yaml:"compression_threshold"`
 }
 
 type ProviderConfig struct {
@@ -56,12 +217,15 @@ type ProviderConfig struct {
 	CircuitBreakerTimeout   time.Duration `yaml:"circuit_breaker_timeout"`
 	RateLimitMax            int           `yaml:"rate_limit_max"`
 	RateLimitWindow         time.Duration `yaml:"rate_limit_window"`
+	DefaultPageSize         int           `yaml:"default_page_size"`
+	MaxPageSize             int           `yaml:"max_page_size"`
 }
 
 func Load(path string) (*Config, error) {
 	godotenv.Load()
 
-	cfg := &Config{}
+	// Redis stays on unless the config turns it off.
+	cfg := &Config{Redis: RedisConfig{Enabled: true}}
 
 	if path != "" {
 		data, err := os.ReadFile(path)
@@ -101,6 +265,11 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("DB_NAME"); v != "" {
 		c.Database.Name = v
 	}
+	if v := os.Getenv("REDIS_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.Redis.Enabled = enabled
+		}
+	}
 	if v := os.Getenv("REDIS_HOST"); v != "" {
 		c.Redis.Host = v
 	}
@@ -131,6 +300,33 @@ func (c *Config) applyEnvOverrides() {
 			c.Provider.RateLimitMax = max
 		}
 	}
+	if v := os.Getenv("SEARCH_MODE"); v != "" {
+		c.Search.Mode = v
+	}
+	if v := os.Getenv("SEARCH_CURSOR_SECRET"); v != "" {
+		c.Search.Cursor.Secret = v
+	}
+	if v := os.Getenv("SCORING_DEFAULT"); v != "" {
+		c.Scoring.Default = v
+	}
+	if v := os.Getenv("SUGGEST_BACKEND"); v != "" {
+		c.Suggest.Backend = v
+	}
+	if v := os.Getenv("RESCORE_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.Rescore.Enabled = enabled
+		}
+	}
+	if v := os.Getenv("WARMUP_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.Warmup.Enabled = enabled
+		}
+	}
+	if v := os.Getenv("INGESTION_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.Ingestion.Enabled = enabled
+		}
+	}
 	if v := os.Getenv("RATE_LIMIT_WINDOW"); v != "" {
 		if window, err := strconv.Atoi(v); err == nil {
 			c.Provider.RateLimitWindow = time.Duration(window) * time.Second
@@ -172,6 +368,15 @@ func (c *Config) setDefaults() {
 	if c.Redis.Port == "" {
 		c.Redis.Port = "6379"
 	}
+	if c.Redis.Codec == "" {
+		c.Redis.Codec = "json"
+	}
+	if c.Redis.Compression == "" {
+		c.Redis.Compression = "none"
+	}
+	if c.Redis.CompressionThreshold == 0 {
+		c.Redis.CompressionThreshold = 1024
+	}
 	if c.Provider.Timeout == 0 {
 		c.Provider.Timeout = 5 * time.Second
 	}
@@ -187,6 +392,131 @@ func (c *Config) setDefaults() {
 	if c.Provider.RateLimitWindow == 0 {
 		c.Provider.RateLimitWindow = 60 * time.Second
 	}
+	if c.Provider.DefaultPageSize == 0 {
+		c.Provider.DefaultPageSize = 20
+	}
+	if c.Provider.MaxPageSize == 0 {
+		c.Provider.MaxPageSize = 100
+	}
+	if c.Search.Mode == "" {
+		c.Search.Mode = "live"
+		if c.Search.PreferDatabase {
+			c.Search.Mode = "hybrid"
+		}
+	}
+	if c.Search.CacheTTL == 0 {
+		c.Search.CacheTTL = 5 * time.Minute
+	}
+	if c.Search.StaleTTL == 0 {
+		c.Search.StaleTTL = 5 * time.Minute
+	}
+	if c.Search.LockTTL == 0 {
+		c.Search.LockTTL = 10 * time.Second
+	}
+	if c.Search.MinResults == 0 {
+		c.Search.MinResults = 1
+	}
+	if c.Search.Fuzzy.MinResults == 0 {
+		c.Search.Fuzzy.MinResults = 1
+	}
+	if c.Search.Fuzzy.MaxResults == 0 {
+		c.Search.Fuzzy.MaxResults = 200
+	}
+	if c.Search.Fuzzy.DictionarySize == 0 {
+		c.Search.Fuzzy.DictionarySize = 50000
+	}
+	if c.Search.Fuzzy.DictionaryRefresh == 0 {
+		c.Search.Fuzzy.DictionaryRefresh = time.Hour
+	}
+	if c.Search.Cursor.SnapshotTTL == 0 {
+		c.Search.Cursor.SnapshotTTL = 10 * time.Minute
+	}
+	if c.Search.Cursor.SnapshotSize == 0 {
+		c.Search.Cursor.SnapshotSize = 100
+	}
+	if c.Search.Dedup.Window == 0 {
+		c.Search.Dedup.Window = 24 * time.Hour
+	}
+	if c.Search.LocalCache.Size == 0 {
+		c.Search.LocalCache.Size = 10000
+	}
+	if c.Search.LocalCache.TTL == 0 {
+		c.Search.LocalCache.TTL = time.Minute
+	}
+	if c.Suggest.Backend == "" {
+		c.Suggest.Backend = "memory"
+	}
+	if c.Suggest.DefaultLimit == 0 {
+		c.Suggest.DefaultLimit = 10
+	}
+	if c.Suggest.MaxLimit == 0 {
+		c.Suggest.MaxLimit = 50
+	}
+	if c.Suggest.BatchSize == 0 {
+		c.Suggest.BatchSize = 500
+	}
+	if c.Suggest.MaxCandidates == 0 {
+		c.Suggest.MaxCandidates = 500
+	}
+	if c.Suggest.PrefixLength == 0 {
+		c.Suggest.PrefixLength = 10
+	}
+	if c.Ingestion.Interval == 0 {
+		c.Ingestion.Interval = 10 * time.Minute
+	}
+	if c.Ingestion.Jitter == 0 {
+		c.Ingestion.Jitter = 30 * time.Second
+	}
+	if c.Ingestion.BatchSize == 0 {
+		c.Ingestion.BatchSize = 100
+	}
+	if c.Ingestion.Timeout == 0 {
+		c.Ingestion.Timeout = time.Minute
+	}
+	if c.Rescore.Interval == 0 {
+		c.Rescore.Interval = time.Hour
+	}
+	if c.Rescore.BatchSize == 0 {
+		c.Rescore.BatchSize = 500
+	}
+	if c.Rescore.MinDelta == 0 {
+		c.Rescore.MinDelta = 0.01
+	}
+	if c.Warmup.Interval == 0 {
+		c.Warmup.Interval = time.Minute
+	}
+	if c.Warmup.TopN == 0 {
+		c.Warmup.TopN = 50
+	}
+	if c.Warmup.Concurrency == 0 {
+		c.Warmup.Concurrency = 4
+	}
+	if c.Warmup.Window == 0 {
+		c.Warmup.Window = time.Hour
+	}
+	if c.Warmup.MaxSearches == 0 {
+		c.Warmup.MaxSearches = 10000
+	}
+	if c.Events.CTRWeight == 0 {
+		c.Events.CTRWeight = 10
+	}
+	if c.Events.Window == 0 {
+		c.Events.Window = 30 * 24 * time.Hour
+	}
+	if c.Events.PriorCTR == 0 {
+		c.Events.PriorCTR = 0.05
+	}
+	if c.Events.PriorWeight == 0 {
+		c.Events.PriorWeight = 20
+	}
+	if c.Scoring.Default == "" {
+		c.Scoring.Default = "default"
+	}
+	for i := range c.Providers {
+		if c.Providers[i].Capabilities.MaxPageSize == 0 {
+			c.Providers[i].Capabilities.MaxPageSize = c.Provider.MaxPageSize
+		}
+	}
 }
 
 func (c *DatabaseConfig) DSN() string {
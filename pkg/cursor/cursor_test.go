@@ -0,0 +1,67 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type position struct {
+	Offset int    `json:"o"`
+	ID     string `json:"id"`
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret")
+
+	token, err := signer.Encode(position{Offset: 20, ID: "abc"})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var got position
+	if err := signer.Decode(token, &got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got != (position{Offset: 20, ID: "abc"}) {
+		t.Errorf("Decode() = %+v", got)
+	}
+}
+
+func TestSigner_RejectsInvalidTokens(t *testing.T) {
+	signer := NewSigner("secret")
+	token, _ := signer.Encode(position{Offset: 20})
+	other, _ := NewSigner("other").Encode(position{Offset: 20})
+	forged, _ := signer.Encode(position{Offset: 40})
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"other secret", other},
+		{"tampered payload", forgedPayload + "." + signature},
+		{"garbage", "not-base64!.???"},
+		{"truncated payload", payload[1:] + "." + signature},
+	}
+
+	for _, tt := range tests {
+		var got position
+		if err := signer.Decode(tt.token, &got); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Decode() error = %v, want ErrInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestNewSigner_RandomSecret(t *testing.T) {
+	token, _ := NewSigner("").Encode(position{Offset: 1})
+
+	var got position
+	if err := NewSigner("").Decode(token, &got); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Decode() with another random secret error = %v, want ErrInvalid", err)
+	}
+}